@@ -18,7 +18,11 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 // +k8s:defaulter-gen=true
@@ -34,8 +38,50 @@ type Configuration struct {
 	// InternalCertManagerment is configuration for internalCertManagerment
 	InternalCertManagement *InternalCertManagement `json:"internalCertManagement,omitempty"`
 
-	// ClientConnection is configuration of the client while connecting to API Server
+	// ExternalCertManagement is configuration for the webhook certificates provided by a Secret
+	// or by a cert-manager Certificate. It can't be set when the internal cert management is enabled,
+	// which is disabled by default when it is set.
+	// +optional
+	ExternalCertManagement *ExternalCertManagement `json:"externalCertManagement,omitempty"`
+
+	// ClientConnection is configuration of the client while connecting to API Server.
+	// It's applied without restarting the controller when the configuration file changes.
 	ClientConnection *ClientConnection `json:"clientConnection,omitempty"`
+
+	// Logging is the configuration of the logs of the controller.
+	// It's applied without restarting the controller when the configuration file changes.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// Tracing is the configuration of the OpenTelemetry tracing of the groups lifecycle,
+	// the spans are exported to an OTLP gRPC collector. Tracing is disabled if it is not set.
+	// +optional
+	Tracing *tracingapi.TracingConfiguration `json:"tracing,omitempty"`
+
+	// GroupRecreation is the configuration of the recreation of the groups of the LeaderWorkerSets
+	// with the RecreateGroupOnPodRestart restart policy.
+	// +optional
+	GroupRecreation *GroupRecreation `json:"groupRecreation,omitempty"`
+
+	// Scope restricts the LeaderWorkerSets managed by the controller, to split them between
+	// several controllers. All the LeaderWorkerSets of the cluster are managed if it is not set.
+	// +optional
+	Scope *ControllerScope `json:"scope,omitempty"`
+
+	// Controllers is the configuration of the concurrency and the requeueing of the controllers.
+	// +optional
+	Controllers *Controllers `json:"controllers,omitempty"`
+
+	// FeatureGates is a map of feature names to bools that enable or disable alpha or beta features,
+	// see the pkg/features package for the known features. The --feature-gates flag overrides them.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// LeaderWorkerSetDefaults are the cluster wide defaults and bounds of the LeaderWorkerSets.
+	// They are applied by the LeaderWorkerSet webhook, so they're ignored when the webhooks are disabled.
+	// They're applied without restarting the controller when the configuration file changes.
+	// +optional
+	LeaderWorkerSetDefaults *LeaderWorkerSetDefaults `json:"leaderWorkerSetDefaults,omitempty"`
 }
 
 type ControllerManager struct {
@@ -84,6 +130,15 @@ type ControllerMetrics struct {
 	// It can be set to "0" to disable the metrics serving.
 	// +optional
 	BindAddress string `json:"bindAddress,omitempty"`
+
+	// MaxTrackedLeaderWorkerSets bounds the cardinality of the LeaderWorkerSet metrics,
+	// it is the maximum number of LeaderWorkerSets reported with their own namespace and name labels.
+	// Past it, the gauges of the other LeaderWorkerSets aren't reported and their counters
+	// are reported with empty namespace and name labels.
+	// It can be set to 0 to only report the metrics aggregated over all the LeaderWorkerSets.
+	// Defaults to 1000.
+	// +optional
+	MaxTrackedLeaderWorkerSets *int32 `json:"maxTrackedLeaderWorkerSets,omitempty"`
 }
 
 // ControllerHealth defines the health configs.
@@ -101,6 +156,11 @@ type ControllerHealth struct {
 	// LivenessEndpointName, defaults to "healthz"
 	// +optional
 	LivenessEndpointName string `json:"livenessEndpointName,omitempty"`
+
+	// StalledReconcileTimeout is the time after which the liveness probe fails when a controller
+	// has pending reconciles but didn't start or finish any of them. Defaults to 10m.
+	// +optional
+	StalledReconcileTimeout *metav1.Duration `json:"stalledReconcileTimeout,omitempty"`
 }
 
 // InternalCertManagement defines internal certificate management configs
@@ -119,6 +179,37 @@ type InternalCertManagement struct {
 	WebhookSecretName *string `json:"webhookSecretName,omitempty"`
 }
 
+// ExternalCertManagement defines the webhook certificates provided by a Secret or by a cert-manager Certificate.
+// The Secret is read from the API server and its changes are served without restarting the controller.
+type ExternalCertManagement struct {
+	// SecretName is the name of the Secret, in the namespace of the controller, holding the
+	// tls.crt and the tls.key of the webhook server, and optionally the ca.crt of their CA.
+	// Exactly one of secretName and certificateName must be set.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// CertificateName is the name of the cert-manager Certificate, in the namespace of the controller,
+	// whose spec.secretName holds the certificates.
+	// +optional
+	CertificateName string `json:"certificateName,omitempty"`
+
+	// InjectCABundle controls whether the ca.crt of the Secret is injected as the caBundle of the webhook
+	// configurations and of the conversion webhook of the CRD. Leave it disabled when the CA bundle is
+	// injected by the cert-manager CA injector. Defaults to false.
+	// +optional
+	InjectCABundle *bool `json:"injectCABundle,omitempty"`
+
+	// ExpiryWarningThreshold is the remaining validity of the certificate below which a warning
+	// event is recorded. Defaults to 720h.
+	// +optional
+	ExpiryWarningThreshold *metav1.Duration `json:"expiryWarningThreshold,omitempty"`
+
+	// ServeMetrics controls whether the metrics server serves the same certificate rather than
+	// a self-signed one. Defaults to false.
+	// +optional
+	ServeMetrics *bool `json:"serveMetrics,omitempty"`
+}
+
 // ClientConnection defines the connection related fields while connecting to API Server
 type ClientConnection struct {
 	// QPS controls the number of queries per second allowed for K8S api server
@@ -128,3 +219,204 @@ type ClientConnection struct {
 	// Burst allows extra queries to accumulate when a client is exceeding its rate.
 	Burst *int32 `json:"burst,omitempty"`
 }
+
+// Logging defines the verbosity of the logs.
+type Logging struct {
+	// Verbosity is the verbosity of the logs, the logs up to this V level are written.
+	// The --zap-log-level flag overrides it.
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+}
+
+// GroupRecreation defines when the groups are recreated, besides the restart or deletion of their pods.
+type GroupRecreation struct {
+	// NodeNotReadyGracePeriod is how long the node of a pod can be NotReady or unreachable
+	// before the pod is treated as failed and its group is recreated. The pods of a failed node
+	// are otherwise kept until they are evicted, which stalls the group meanwhile.
+	// It can be set to 0 to disable it.
+	// Defaults to 2m.
+	// +optional
+	NodeNotReadyGracePeriod *metav1.Duration `json:"nodeNotReadyGracePeriod,omitempty"`
+
+	// DisruptionPolicies configure how a group is recreated once one of its worker pods is disrupted,
+	// as reported by the DisruptionTarget condition of the pod. The groups of the pods disrupted
+	// for a reason without a policy are recreated immediately, as are the groups of disrupted leader pods.
+	// +optional
+	// +listType=map
+	// +listMapKey=reason
+	DisruptionPolicies []DisruptionPolicy `json:"disruptionPolicies,omitempty"`
+}
+
+// DisruptionReason is the kind of disruption of a pod.
+type DisruptionReason string
+
+const (
+	// PreemptionDisruptionReason means the pod was preempted by the scheduler.
+	PreemptionDisruptionReason DisruptionReason = "Preemption"
+	// EvictionDisruptionReason means the pod was evicted with the eviction API, e.g. by a node drain.
+	EvictionDisruptionReason DisruptionReason = "Eviction"
+	// TaintManagerDeletionDisruptionReason means the pod was deleted by the taint manager because
+	// of a NoExecute taint of its node it doesn't tolerate.
+	TaintManagerDeletionDisruptionReason DisruptionReason = "TaintManagerDeletion"
+)
+
+// DisruptionAction is how a group is recreated after the disruption of one of its pods.
+type DisruptionAction string
+
+const (
+	// RecreateDisruptionAction recreates the group immediately.
+	RecreateDisruptionAction DisruptionAction = "Recreate"
+	// DelayDisruptionAction recreates the group once the delay of the policy has passed.
+	DelayDisruptionAction DisruptionAction = "Delay"
+	// WaitForCapacityDisruptionAction recreates the group once the replacement of the disrupted pod
+	// is scheduled, so that the group isn't put back onto capacity which is still contended.
+	WaitForCapacityDisruptionAction DisruptionAction = "WaitForCapacity"
+)
+
+// DisruptionPolicy is the recreation of the groups with a pod disrupted for a reason.
+type DisruptionPolicy struct {
+	// Reason is the disruption the policy applies to, one of Preemption, Eviction or TaintManagerDeletion.
+	Reason DisruptionReason `json:"reason"`
+
+	// Action is how the group is recreated, one of Recreate, Delay or WaitForCapacity.
+	Action DisruptionAction `json:"action"`
+
+	// Delay is how long the recreation of the group is delayed, it's required with the Delay action.
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+}
+
+// ControllerScope restricts the LeaderWorkerSets managed by the controller to some namespaces and to a shard.
+type ControllerScope struct {
+	// Namespaces restricts the controller to the LeaderWorkerSets of the listed namespaces,
+	// only the objects of these namespaces are cached.
+	// It can't be set along with NamespaceSelector.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector restricts the controller to the LeaderWorkerSets of the namespaces
+	// whose labels match the selector. The namespaces are watched to follow their labels.
+	// It can't be set along with Namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Shard restricts the controller to the LeaderWorkerSets with the leaderworkerset.sigs.k8s.io/shard
+	// label set to this value, only these LeaderWorkerSets are cached. Each shard of the LeaderWorkerSets
+	// is managed by its own controller, the LeaderWorkerSets are not sharded if it is not set.
+	// The webhooks don't depend on the shard, a single controller serves them for every shard.
+	// +optional
+	Shard string `json:"shard,omitempty"`
+}
+
+// Controllers defines the configuration of each controller.
+type Controllers struct {
+	// LeaderWorkerSet is the configuration of the controller of the LeaderWorkerSets.
+	// +optional
+	LeaderWorkerSet *ControllerConfiguration `json:"leaderWorkerSet,omitempty"`
+
+	// Pod is the configuration of the controller of the LeaderWorkerSet pods.
+	// +optional
+	Pod *ControllerConfiguration `json:"pod,omitempty"`
+}
+
+// ControllerConfiguration defines the concurrency and the rate limiting of the reconciles of a controller.
+type ControllerConfiguration struct {
+	// MaxConcurrentReconciles is the maximum number of reconciles the controller runs concurrently.
+	// Defaults to 1.
+	// +optional
+	MaxConcurrentReconciles *int32 `json:"maxConcurrentReconciles,omitempty"`
+
+	// RateLimiter bounds the rate of the reconciles of the controller.
+	// +optional
+	RateLimiter *RateLimiter `json:"rateLimiter,omitempty"`
+
+	// Backoff is the per object backoff of the reconciles which failed or asked to be requeued.
+	// +optional
+	Backoff *Backoff `json:"backoff,omitempty"`
+}
+
+// RateLimiter defines the token bucket shared by all the reconciles of a controller.
+type RateLimiter struct {
+	// QPS is the overall number of reconciles per second.
+	// Defaults to 10.
+	// +optional
+	QPS *float32 `json:"qps,omitempty"`
+
+	// Burst is the number of reconciles which can run above QPS.
+	// Defaults to 100.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// Backoff defines the exponential backoff of the requeues of an object, it's reset once
+// the object is reconciled successfully.
+type Backoff struct {
+	// BaseDelay is the delay before the first requeue, it's doubled on each requeue.
+	// Defaults to 5ms.
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum delay between two requeues.
+	// Defaults to 1000s.
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+// LeaderWorkerSetDefaults defines the defaults and the bounds of the LeaderWorkerSets of the cluster,
+// and their overrides for some namespaces.
+type LeaderWorkerSetDefaults struct {
+	LeaderWorkerSetPolicy `json:",inline"`
+
+	// NamespaceOverrides override the defaults and the bounds for the LeaderWorkerSets of the namespaces
+	// whose labels match their namespaceSelector. Only the first matching override applies, and
+	// the fields it doesn't set fall back to the cluster wide ones.
+	// +optional
+	NamespaceOverrides []LeaderWorkerSetPolicyOverride `json:"namespaceOverrides,omitempty"`
+}
+
+// LeaderWorkerSetPolicy defines the defaults applied to the LeaderWorkerSets when they are created,
+// before the built-in defaults, and the bounds enforced when they are created or updated.
+// As the CRD defaults the restartPolicy, the startupPolicy and the maxSurge of the LeaderWorkerSets,
+// these fields are defaulted when they are unset or set to their built-in default: an explicit
+// RecreateGroupOnPodRestart, LeaderCreated or maxSurge of 0 is overridden too. A LeaderWorkerSet
+// created with the leaderworkerset.sigs.k8s.io/skip-policy-defaults annotation set to "true" keeps
+// its values, only the bounds apply to it.
+type LeaderWorkerSetPolicy struct {
+	// ExclusiveTopology is the default of the leaderworkerset.sigs.k8s.io/exclusive-topology annotation,
+	// the topology key of the exclusive placement of the groups.
+	// +optional
+	ExclusiveTopology string `json:"exclusiveTopology,omitempty"`
+
+	// RestartPolicy is the default restart policy, one of RecreateGroupOnPodRestart or None.
+	// +optional
+	RestartPolicy leaderworkerset.RestartPolicyType `json:"restartPolicy,omitempty"`
+
+	// StartupPolicy is the default startup policy, one of LeaderCreated or LeaderReady.
+	// +optional
+	StartupPolicy leaderworkerset.StartupPolicyType `json:"startupPolicy,omitempty"`
+
+	// SubdomainPolicy is the default subdomain policy, one of Shared or UniquePerReplica.
+	// +optional
+	SubdomainPolicy leaderworkerset.SubdomainPolicy `json:"subdomainPolicy,omitempty"`
+
+	// MaxSurge is the default maxSurge of the rolling updates.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxSize is the maximum size of the groups.
+	// +optional
+	MaxSize *int32 `json:"maxSize,omitempty"`
+
+	// MaxReplicas is the maximum number of replicas, including the replicas changed through the
+	// scale subresource, e.g. by a HorizontalPodAutoscaler.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// LeaderWorkerSetPolicyOverride defines the defaults and the bounds of the LeaderWorkerSets of some namespaces.
+type LeaderWorkerSetPolicyOverride struct {
+	// NamespaceSelector selects the namespaces of the override by their labels.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+
+	LeaderWorkerSetPolicy `json:",inline"`
+}
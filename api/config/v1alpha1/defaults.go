@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/utils/ptr"
 )
@@ -34,6 +37,19 @@ const (
 	DefaultResourceLock                   = "leases"
 	DefaultClientConnectionQPS    float32 = 500
 	DefaultClientConnectionBurst  int32   = 500
+
+	DefaultMaxTrackedLeaderWorkerSets int32 = 1000
+	DefaultNodeNotReadyGracePeriod          = 2 * time.Minute
+
+	DefaultMaxConcurrentReconciles int32   = 1
+	DefaultRateLimiterQPS          float32 = 10
+	DefaultRateLimiterBurst        int32   = 100
+	DefaultBackoffBaseDelay                = 5 * time.Millisecond
+	DefaultBackoffMaxDelay                 = 1000 * time.Second
+
+	DefaultExpiryWarningThreshold = 30 * 24 * time.Hour
+
+	DefaultStalledReconcileTimeout = 10 * time.Minute
 )
 
 // SetDefaults_Configuration sets default values for ComponentConfig.
@@ -49,6 +65,9 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if len(cfg.Metrics.BindAddress) == 0 {
 		cfg.Metrics.BindAddress = DefaultMetricsBindAddress
 	}
+	if cfg.Metrics.MaxTrackedLeaderWorkerSets == nil {
+		cfg.Metrics.MaxTrackedLeaderWorkerSets = ptr.To(DefaultMaxTrackedLeaderWorkerSets)
+	}
 	if len(cfg.Health.HealthProbeBindAddress) == 0 {
 		cfg.Health.HealthProbeBindAddress = DefaultHealthProbeBindAddress
 	}
@@ -58,6 +77,9 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if cfg.Health.ReadinessEndpointName == "" {
 		cfg.Health.ReadinessEndpointName = DefaultReadinessEndpoint
 	}
+	if cfg.Health.StalledReconcileTimeout == nil {
+		cfg.Health.StalledReconcileTimeout = &metav1.Duration{Duration: DefaultStalledReconcileTimeout}
+	}
 
 	if cfg.LeaderElection == nil {
 		cfg.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{}
@@ -75,7 +97,7 @@ func SetDefaults_Configuration(cfg *Configuration) {
 		cfg.InternalCertManagement = &InternalCertManagement{}
 	}
 	if cfg.InternalCertManagement.Enable == nil {
-		cfg.InternalCertManagement.Enable = ptr.To(true)
+		cfg.InternalCertManagement.Enable = ptr.To(cfg.ExternalCertManagement == nil)
 	}
 	if *cfg.InternalCertManagement.Enable {
 		if cfg.InternalCertManagement.WebhookServiceName == nil {
@@ -85,6 +107,17 @@ func SetDefaults_Configuration(cfg *Configuration) {
 			cfg.InternalCertManagement.WebhookSecretName = ptr.To(DefaultWebhookSecretName)
 		}
 	}
+	if cfg.ExternalCertManagement != nil {
+		if cfg.ExternalCertManagement.InjectCABundle == nil {
+			cfg.ExternalCertManagement.InjectCABundle = ptr.To(false)
+		}
+		if cfg.ExternalCertManagement.ExpiryWarningThreshold == nil {
+			cfg.ExternalCertManagement.ExpiryWarningThreshold = &metav1.Duration{Duration: DefaultExpiryWarningThreshold}
+		}
+		if cfg.ExternalCertManagement.ServeMetrics == nil {
+			cfg.ExternalCertManagement.ServeMetrics = ptr.To(false)
+		}
+	}
 	if cfg.ClientConnection == nil {
 		cfg.ClientConnection = &ClientConnection{}
 	}
@@ -94,4 +127,45 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if cfg.ClientConnection.Burst == nil {
 		cfg.ClientConnection.Burst = ptr.To(DefaultClientConnectionBurst)
 	}
+	if cfg.GroupRecreation == nil {
+		cfg.GroupRecreation = &GroupRecreation{}
+	}
+	if cfg.GroupRecreation.NodeNotReadyGracePeriod == nil {
+		cfg.GroupRecreation.NodeNotReadyGracePeriod = &metav1.Duration{Duration: DefaultNodeNotReadyGracePeriod}
+	}
+	if cfg.Controllers == nil {
+		cfg.Controllers = &Controllers{}
+	}
+	if cfg.Controllers.LeaderWorkerSet == nil {
+		cfg.Controllers.LeaderWorkerSet = &ControllerConfiguration{}
+	}
+	setDefaultsControllerConfiguration(cfg.Controllers.LeaderWorkerSet)
+	if cfg.Controllers.Pod == nil {
+		cfg.Controllers.Pod = &ControllerConfiguration{}
+	}
+	setDefaultsControllerConfiguration(cfg.Controllers.Pod)
+}
+
+func setDefaultsControllerConfiguration(c *ControllerConfiguration) {
+	if c.MaxConcurrentReconciles == nil {
+		c.MaxConcurrentReconciles = ptr.To(DefaultMaxConcurrentReconciles)
+	}
+	if c.RateLimiter == nil {
+		c.RateLimiter = &RateLimiter{}
+	}
+	if c.RateLimiter.QPS == nil {
+		c.RateLimiter.QPS = ptr.To(DefaultRateLimiterQPS)
+	}
+	if c.RateLimiter.Burst == nil {
+		c.RateLimiter.Burst = ptr.To(DefaultRateLimiterBurst)
+	}
+	if c.Backoff == nil {
+		c.Backoff = &Backoff{}
+	}
+	if c.Backoff.BaseDelay == nil {
+		c.Backoff.BaseDelay = &metav1.Duration{Duration: DefaultBackoffBaseDelay}
+	}
+	if c.Backoff.MaxDelay == nil {
+		c.Backoff.MaxDelay = &metav1.Duration{Duration: DefaultBackoffMaxDelay}
+	}
 }
@@ -52,18 +52,38 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			CertDir: DefaultWebhookCertDir,
 		},
 		Metrics: ControllerMetrics{
-			BindAddress: DefaultMetricsBindAddress,
+			BindAddress:                DefaultMetricsBindAddress,
+			MaxTrackedLeaderWorkerSets: ptr.To(DefaultMaxTrackedLeaderWorkerSets),
 		},
 		Health: ControllerHealth{
-			HealthProbeBindAddress: DefaultHealthProbeBindAddress,
-			ReadinessEndpointName:  DefaultReadinessEndpoint,
-			LivenessEndpointName:   DefaultLivenessEndpoint,
+			HealthProbeBindAddress:  DefaultHealthProbeBindAddress,
+			ReadinessEndpointName:   DefaultReadinessEndpoint,
+			LivenessEndpointName:    DefaultLivenessEndpoint,
+			StalledReconcileTimeout: &metav1.Duration{Duration: DefaultStalledReconcileTimeout},
 		},
 	}
 	defaultClientConnection := &ClientConnection{
 		QPS:   ptr.To(DefaultClientConnectionQPS),
 		Burst: ptr.To(DefaultClientConnectionBurst),
 	}
+	defaultGroupRecreation := &GroupRecreation{
+		NodeNotReadyGracePeriod: &metav1.Duration{Duration: DefaultNodeNotReadyGracePeriod},
+	}
+	defaultControllerConfiguration := &ControllerConfiguration{
+		MaxConcurrentReconciles: ptr.To(DefaultMaxConcurrentReconciles),
+		RateLimiter: &RateLimiter{
+			QPS:   ptr.To(DefaultRateLimiterQPS),
+			Burst: ptr.To(DefaultRateLimiterBurst),
+		},
+		Backoff: &Backoff{
+			BaseDelay: &metav1.Duration{Duration: DefaultBackoffBaseDelay},
+			MaxDelay:  &metav1.Duration{Duration: DefaultBackoffMaxDelay},
+		},
+	}
+	defaultControllers := &Controllers{
+		LeaderWorkerSet: defaultControllerConfiguration,
+		Pod:             defaultControllerConfiguration,
+	}
 
 	testCases := map[string]struct {
 		original *Configuration
@@ -81,6 +101,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"defaulting ControllerManager": {
@@ -101,12 +123,14 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						CertDir: DefaultWebhookCertDir,
 					},
 					Metrics: ControllerMetrics{
-						BindAddress: DefaultMetricsBindAddress,
+						BindAddress:                DefaultMetricsBindAddress,
+						MaxTrackedLeaderWorkerSets: ptr.To(DefaultMaxTrackedLeaderWorkerSets),
 					},
 					Health: ControllerHealth{
-						HealthProbeBindAddress: DefaultHealthProbeBindAddress,
-						ReadinessEndpointName:  DefaultReadinessEndpoint,
-						LivenessEndpointName:   DefaultLivenessEndpoint,
+						HealthProbeBindAddress:  DefaultHealthProbeBindAddress,
+						ReadinessEndpointName:   DefaultReadinessEndpoint,
+						LivenessEndpointName:    DefaultLivenessEndpoint,
+						StalledReconcileTimeout: &metav1.Duration{Duration: DefaultStalledReconcileTimeout},
 					},
 					LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
 						LeaderElect:   ptr.To(true),
@@ -121,6 +145,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"should not default ControllerManager": {
@@ -130,7 +156,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						Port: ptr.To(overwriteWebhookPort),
 					},
 					Metrics: ControllerMetrics{
-						BindAddress: overwriteMetricBindAddress,
+						BindAddress:                overwriteMetricBindAddress,
+						MaxTrackedLeaderWorkerSets: ptr.To(DefaultMaxTrackedLeaderWorkerSets),
 					},
 					Health: ControllerHealth{
 						HealthProbeBindAddress: overwriteHealthProbeBindAddress,
@@ -155,12 +182,14 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						CertDir: DefaultWebhookCertDir,
 					},
 					Metrics: ControllerMetrics{
-						BindAddress: overwriteMetricBindAddress,
+						BindAddress:                overwriteMetricBindAddress,
+						MaxTrackedLeaderWorkerSets: ptr.To(DefaultMaxTrackedLeaderWorkerSets),
 					},
 					Health: ControllerHealth{
-						HealthProbeBindAddress: overwriteHealthProbeBindAddress,
-						ReadinessEndpointName:  DefaultReadinessEndpoint,
-						LivenessEndpointName:   DefaultLivenessEndpoint,
+						HealthProbeBindAddress:  overwriteHealthProbeBindAddress,
+						ReadinessEndpointName:   DefaultReadinessEndpoint,
+						LivenessEndpointName:    DefaultLivenessEndpoint,
+						StalledReconcileTimeout: &metav1.Duration{Duration: DefaultStalledReconcileTimeout},
 					},
 					LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
 						LeaderElect:   ptr.To(true),
@@ -175,6 +204,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"should not set LeaderElectionID": {
@@ -195,12 +226,14 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						CertDir: DefaultWebhookCertDir,
 					},
 					Metrics: ControllerMetrics{
-						BindAddress: DefaultMetricsBindAddress,
+						BindAddress:                DefaultMetricsBindAddress,
+						MaxTrackedLeaderWorkerSets: ptr.To(DefaultMaxTrackedLeaderWorkerSets),
 					},
 					Health: ControllerHealth{
-						HealthProbeBindAddress: DefaultHealthProbeBindAddress,
-						ReadinessEndpointName:  DefaultReadinessEndpoint,
-						LivenessEndpointName:   DefaultLivenessEndpoint,
+						HealthProbeBindAddress:  DefaultHealthProbeBindAddress,
+						ReadinessEndpointName:   DefaultReadinessEndpoint,
+						LivenessEndpointName:    DefaultLivenessEndpoint,
+						StalledReconcileTimeout: &metav1.Duration{Duration: DefaultStalledReconcileTimeout},
 					},
 					LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
 						LeaderElect:   ptr.To(false),
@@ -215,6 +248,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"defaulting InternalCertManagement": {
@@ -227,6 +262,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					WebhookSecretName:  ptr.To(DefaultWebhookSecretName),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"should not default InternalCertManagement": {
@@ -241,6 +278,30 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
+			},
+		},
+		"defaulting ExternalCertManagement": {
+			original: &Configuration{
+				ExternalCertManagement: &ExternalCertManagement{
+					CertificateName: "lws-serving-cert",
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ExternalCertManagement: &ExternalCertManagement{
+					CertificateName:        "lws-serving-cert",
+					InjectCABundle:         ptr.To(false),
+					ExpiryWarningThreshold: &metav1.Duration{Duration: DefaultExpiryWarningThreshold},
+					ServeMetrics:           ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 		},
 		"should not default values in custom ClientConnection": {
@@ -262,6 +323,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					QPS:   ptr.To[float32](123.0),
 					Burst: ptr.To[int32](456),
 				},
+				GroupRecreation: defaultGroupRecreation,
+				Controllers:     defaultControllers,
 			},
 		},
 		"should default empty custom ClientConnection": {
@@ -277,6 +340,69 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
+			},
+		},
+		"should not default a disabled node NotReady grace period": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				GroupRecreation: &GroupRecreation{
+					NodeNotReadyGracePeriod: &metav1.Duration{},
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				GroupRecreation: &GroupRecreation{
+					NodeNotReadyGracePeriod: &metav1.Duration{},
+				},
+				Controllers: defaultControllers,
+			},
+		},
+		"should not default the controllers": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				Controllers: &Controllers{
+					Pod: &ControllerConfiguration{
+						MaxConcurrentReconciles: ptr.To[int32](10),
+						RateLimiter: &RateLimiter{
+							QPS: ptr.To[float32](50),
+						},
+						Backoff: &Backoff{
+							MaxDelay: &metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
+			},
+			want: &Configuration{
+				ControllerManager: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers: &Controllers{
+					LeaderWorkerSet: defaultControllerConfiguration,
+					Pod: &ControllerConfiguration{
+						MaxConcurrentReconciles: ptr.To[int32](10),
+						RateLimiter: &RateLimiter{
+							QPS:   ptr.To[float32](50),
+							Burst: ptr.To(DefaultRateLimiterBurst),
+						},
+						Backoff: &Backoff{
+							BaseDelay: &metav1.Duration{Duration: DefaultBackoffBaseDelay},
+							MaxDelay:  &metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
 			},
 		},
 	}
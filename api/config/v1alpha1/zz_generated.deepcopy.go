@@ -18,10 +18,38 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/component-base/tracing/api/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backoff) DeepCopyInto(out *Backoff) {
+	*out = *in
+	if in.BaseDelay != nil {
+		in, out := &in.BaseDelay, &out.BaseDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backoff.
+func (in *Backoff) DeepCopy() *Backoff {
+	if in == nil {
+		return nil
+	}
+	out := new(Backoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConnection) DeepCopyInto(out *ClientConnection) {
 	*out = *in
@@ -57,11 +85,53 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(InternalCertManagement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalCertManagement != nil {
+		in, out := &in.ExternalCertManagement, &out.ExternalCertManagement
+		*out = new(ExternalCertManagement)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClientConnection != nil {
 		in, out := &in.ClientConnection, &out.ClientConnection
 		*out = new(ClientConnection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(v1.TracingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupRecreation != nil {
+		in, out := &in.GroupRecreation, &out.GroupRecreation
+		*out = new(GroupRecreation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(ControllerScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = new(Controllers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LeaderWorkerSetDefaults != nil {
+		in, out := &in.LeaderWorkerSetDefaults, &out.LeaderWorkerSetDefaults
+		*out = new(LeaderWorkerSetDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
@@ -82,9 +152,44 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerConfiguration) DeepCopyInto(out *ControllerConfiguration) {
+	*out = *in
+	if in.MaxConcurrentReconciles != nil {
+		in, out := &in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RateLimiter != nil {
+		in, out := &in.RateLimiter, &out.RateLimiter
+		*out = new(RateLimiter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(Backoff)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerConfiguration.
+func (in *ControllerConfiguration) DeepCopy() *ControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerHealth) DeepCopyInto(out *ControllerHealth) {
 	*out = *in
+	if in.StalledReconcileTimeout != nil {
+		in, out := &in.StalledReconcileTimeout, &out.StalledReconcileTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerHealth.
@@ -106,8 +211,8 @@ func (in *ControllerManager) DeepCopyInto(out *ControllerManager) {
 		*out = new(configv1alpha1.LeaderElectionConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Metrics = in.Metrics
-	out.Health = in.Health
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	in.Health.DeepCopyInto(&out.Health)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerManager.
@@ -123,6 +228,11 @@ func (in *ControllerManager) DeepCopy() *ControllerManager {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerMetrics) DeepCopyInto(out *ControllerMetrics) {
 	*out = *in
+	if in.MaxTrackedLeaderWorkerSets != nil {
+		in, out := &in.MaxTrackedLeaderWorkerSets, &out.MaxTrackedLeaderWorkerSets
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerMetrics.
@@ -135,6 +245,31 @@ func (in *ControllerMetrics) DeepCopy() *ControllerMetrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerScope) DeepCopyInto(out *ControllerScope) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerScope.
+func (in *ControllerScope) DeepCopy() *ControllerScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerWebhook) DeepCopyInto(out *ControllerWebhook) {
 	*out = *in
@@ -155,6 +290,108 @@ func (in *ControllerWebhook) DeepCopy() *ControllerWebhook {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Controllers) DeepCopyInto(out *Controllers) {
+	*out = *in
+	if in.LeaderWorkerSet != nil {
+		in, out := &in.LeaderWorkerSet, &out.LeaderWorkerSet
+		*out = new(ControllerConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(ControllerConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Controllers.
+func (in *Controllers) DeepCopy() *Controllers {
+	if in == nil {
+		return nil
+	}
+	out := new(Controllers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionPolicy) DeepCopyInto(out *DisruptionPolicy) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionPolicy.
+func (in *DisruptionPolicy) DeepCopy() *DisruptionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCertManagement) DeepCopyInto(out *ExternalCertManagement) {
+	*out = *in
+	if in.InjectCABundle != nil {
+		in, out := &in.InjectCABundle, &out.InjectCABundle
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExpiryWarningThreshold != nil {
+		in, out := &in.ExpiryWarningThreshold, &out.ExpiryWarningThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ServeMetrics != nil {
+		in, out := &in.ServeMetrics, &out.ServeMetrics
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCertManagement.
+func (in *ExternalCertManagement) DeepCopy() *ExternalCertManagement {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCertManagement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupRecreation) DeepCopyInto(out *GroupRecreation) {
+	*out = *in
+	if in.NodeNotReadyGracePeriod != nil {
+		in, out := &in.NodeNotReadyGracePeriod, &out.NodeNotReadyGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DisruptionPolicies != nil {
+		in, out := &in.DisruptionPolicies, &out.DisruptionPolicies
+		*out = make([]DisruptionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupRecreation.
+func (in *GroupRecreation) DeepCopy() *GroupRecreation {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupRecreation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternalCertManagement) DeepCopyInto(out *InternalCertManagement) {
 	*out = *in
@@ -184,3 +421,122 @@ func (in *InternalCertManagement) DeepCopy() *InternalCertManagement {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderWorkerSetDefaults) DeepCopyInto(out *LeaderWorkerSetDefaults) {
+	*out = *in
+	in.LeaderWorkerSetPolicy.DeepCopyInto(&out.LeaderWorkerSetPolicy)
+	if in.NamespaceOverrides != nil {
+		in, out := &in.NamespaceOverrides, &out.NamespaceOverrides
+		*out = make([]LeaderWorkerSetPolicyOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetDefaults.
+func (in *LeaderWorkerSetDefaults) DeepCopy() *LeaderWorkerSetDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderWorkerSetDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderWorkerSetPolicy) DeepCopyInto(out *LeaderWorkerSetPolicy) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetPolicy.
+func (in *LeaderWorkerSetPolicy) DeepCopy() *LeaderWorkerSetPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderWorkerSetPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderWorkerSetPolicyOverride) DeepCopyInto(out *LeaderWorkerSetPolicyOverride) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LeaderWorkerSetPolicy.DeepCopyInto(&out.LeaderWorkerSetPolicy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetPolicyOverride.
+func (in *LeaderWorkerSetPolicyOverride) DeepCopy() *LeaderWorkerSetPolicyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderWorkerSetPolicyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logging) DeepCopyInto(out *Logging) {
+	*out = *in
+	if in.Verbosity != nil {
+		in, out := &in.Verbosity, &out.Verbosity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logging.
+func (in *Logging) DeepCopy() *Logging {
+	if in == nil {
+		return nil
+	}
+	out := new(Logging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimiter) DeepCopyInto(out *RateLimiter) {
+	*out = *in
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(float32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimiter.
+func (in *RateLimiter) DeepCopy() *RateLimiter {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimiter)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -69,6 +69,11 @@ const (
 	// track the size of the LWS group.
 	LwsGroupSize string = "LWS_GROUP_SIZE"
 
+	// Environment variable added to all containers in the LeaderWorkerSet when
+	// pods are not mutated by the pod webhook, holding the name of the leader pod.
+	// LwsLeaderAddress is derived from it, since the leader name differs per group.
+	LwsLeaderName string = "LWS_LEADER_NAME"
+
 	// Subgroup index tracks which subgroup the pod is part of. It will be added
 	// as a label to the pod only if LeaderWorkerSet.Spec.SubGroupSize is set.
 	SubGroupIndexLabelKey string = "leaderworkerset.sigs.k8s.io/subgroup-index"
@@ -83,6 +88,32 @@ const (
 	// Leader pods will have an annotation that determines what type of domain
 	// will be injected. Corresponds to LeaderWorkerSet.Spec.NetworkConfig.SubdomainPolicy
 	SubdomainPolicyAnnotationKey string = "leaderworkerset.sigs.k8s.io/subdomainPolicy"
+
+	// Group pods will have an annotation with the W3C traceparent of the span covering the
+	// lifecycle of their group, when the group is traced.
+	TraceContextAnnotationKey string = "leaderworkerset.sigs.k8s.io/trace-context"
+
+	// When set to "true" on a LeaderWorkerSet, the rolling update is paused: the groups
+	// not updated yet are kept on their revision until the annotation is removed.
+	RolloutPausedAnnotationKey string = "leaderworkerset.sigs.k8s.io/rollout-paused"
+
+	// Set on the leader and worker templates to the time of a restart request, like
+	// kubectl.kubernetes.io/restartedAt for Deployments. Changing it is a template change,
+	// so every group is recreated by a rolling update honoring maxUnavailable.
+	RestartedAtAnnotationKey string = "leaderworkerset.sigs.k8s.io/restartedAt"
+
+	// Set on a LeaderWorkerSet to a comma separated list of group indexes, e.g. "0,2",
+	// the leader pods of these groups are deleted to recreate the groups, then the
+	// annotation is removed.
+	RestartGroupsAnnotationKey string = "leaderworkerset.sigs.k8s.io/restart-groups"
+
+	// When set to "true" on a LeaderWorkerSet being created, the defaults of the leaderWorkerSetDefaults
+	// of the controller configuration aren't applied to it, its bounds still are.
+	SkipPolicyDefaultsAnnotationKey string = "leaderworkerset.sigs.k8s.io/skip-policy-defaults"
+
+	// Set on a LeaderWorkerSet to assign it to a shard, it's then only managed by the
+	// controller configured with this shard.
+	ShardLabelKey string = "leaderworkerset.sigs.k8s.io/shard"
 )
 
 // One group consists of a single leader and M workers, and the total number of pods in a group is M+1.
@@ -95,6 +126,8 @@ const (
 // Each worker pod in the group has a unique workerIndex between 1 and M. The leader also
 // gets a workerIndex, and it is always set to 0.
 // Worker pods are named using the format: leaderWorkerSetName-leaderIndex-workerIndex.
+// +kubebuilder:validation:XValidation:rule="(has(self.replicas) ? self.replicas : 1) * (has(self.leaderWorkerTemplate.size) ? self.leaderWorkerTemplate.size : 1) <= 2147483647",message="the product of replicas and size must not exceed 2147483647",fieldPath=".replicas"
+// +kubebuilder:validation:XValidation:rule="!has(self.rolloutStrategy) || !has(self.rolloutStrategy.rollingUpdateConfiguration) || !has(self.rolloutStrategy.rollingUpdateConfiguration.maxUnavailable) || !has(self.rolloutStrategy.rollingUpdateConfiguration.maxSurge) || (type(self.rolloutStrategy.rollingUpdateConfiguration.maxUnavailable) == int ? self.rolloutStrategy.rollingUpdateConfiguration.maxUnavailable != 0 : int(self.rolloutStrategy.rollingUpdateConfiguration.maxUnavailable.substring(0, size(self.rolloutStrategy.rollingUpdateConfiguration.maxUnavailable) - 1)) * (has(self.replicas) ? self.replicas : 1) / 100 != 0) || (type(self.rolloutStrategy.rollingUpdateConfiguration.maxSurge) == int ? self.rolloutStrategy.rollingUpdateConfiguration.maxSurge != 0 : int(self.rolloutStrategy.rollingUpdateConfiguration.maxSurge.substring(0, size(self.rolloutStrategy.rollingUpdateConfiguration.maxSurge) - 1)) * (has(self.replicas) ? self.replicas : 1) != 0)",message="maxUnavailable must not be 0 when maxSurge is 0",fieldPath=".rolloutStrategy.rollingUpdateConfiguration.maxUnavailable"
 type LeaderWorkerSetSpec struct {
 	// Number of leader-workers groups. A scale subresource is available to enable HPA. The
 	// selector for HPA will be that of the leader pod, and so practically HPA will be looking up the
@@ -106,6 +139,7 @@ type LeaderWorkerSetSpec struct {
 	//
 	// +optional
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
 
 	// LeaderWorkerTemplate defines the template for leader/worker pods
@@ -133,6 +167,9 @@ type LeaderWorkerSetSpec struct {
 // For the leader it represents the id of the group, while for the workers it represents the
 // index within the group. For this reason, users should depend on the labels injected by this
 // API whenever possible.
+// +kubebuilder:validation:XValidation:rule="!has(self.subGroupPolicy) || !has(self.subGroupPolicy.subGroupSize) || (has(self.size) ? self.size : 1) >= self.subGroupPolicy.subGroupSize",message="subGroupSize cannot be larger than size",fieldPath=".subGroupPolicy.subGroupSize"
+// +kubebuilder:validation:XValidation:rule="!has(self.subGroupPolicy) || !has(self.subGroupPolicy.subGroupSize) || self.subGroupPolicy.subGroupSize < 1 || (has(self.size) ? self.size : 1) % self.subGroupPolicy.subGroupSize == 0 || ((has(self.size) ? self.size : 1) - 1) % self.subGroupPolicy.subGroupSize == 0",message="size or size - 1 must be divisible by subGroupSize",fieldPath=".subGroupPolicy.subGroupSize"
+// +kubebuilder:validation:XValidation:rule="has(self.subGroupPolicy) == has(oldSelf.subGroupPolicy)",message="subGroupPolicy cannot be added or removed after the lws is created"
 type LeaderWorkerTemplate struct {
 	// LeaderTemplate defines the pod template for leader pods.
 	LeaderTemplate *corev1.PodTemplateSpec `json:"leaderTemplate,omitempty"`
@@ -147,6 +184,8 @@ type LeaderWorkerTemplate struct {
 	//
 	// +optional
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="size is immutable"
 	Size *int32 `json:"size,omitempty"`
 
 	// RestartPolicy defines the restart policy when pod failures happen.
@@ -185,6 +224,8 @@ type SubGroupPolicy struct {
 	// subgroups will be of equal size. Or size - 1 is divisible
 	// by subGroupSize, in which case the leader is considered as
 	// the extra pod, and will be part of the first subgroup.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="subGroupSize is immutable"
 	SubGroupSize *int32 `json:"subGroupSize,omitempty"`
 }
 
@@ -225,6 +266,7 @@ type RollingUpdateConfiguration struct {
 	//
 	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 0 : self.matches('^(100|[1-9]?[0-9])%$')",message="must be a non-negative integer or a percentage between 0% and 100%"
 	MaxUnavailable intstr.IntOrString `json:"maxUnavailable,omitempty"`
 
 	// The maximum number of replicas that can be scheduled above the original number of
@@ -241,6 +283,7 @@ type RollingUpdateConfiguration struct {
 	//
 	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:default=0
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 0 : self.matches('^(100|[1-9]?[0-9])%$')",message="must be a non-negative integer or a percentage between 0% and 100%"
 	MaxSurge intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
@@ -301,6 +344,27 @@ type LeaderWorkerSetStatus struct {
 	// needed for HPA to know what pods belong to the LeaderWorkerSet object. Here
 	// we only select the leader pods.
 	HPAPodSelector string `json:"hpaPodSelector,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller, the
+	// rest of the status reflects the spec of this generation.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AvailableReplicas track the number of groups whose pods are all available.
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// CurrentRevision is the key of the revision the groups ran before the ongoing rolling
+	// update, the groups not updated yet still run it. It equals UpdateRevision once the
+	// rolling update is done.
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// UpdateRevision is the key of the revision of the latest leaderWorkerTemplate, the
+	// groups are rolled to it.
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// CollisionCount is the count of hash collisions for the ControllerRevisions of the
+	// leaderworkerset. The controller uses it as a collision avoidance mechanism when it
+	// needs to create the name of the newest ControllerRevision.
+	CollisionCount *int32 `json:"collisionCount,omitempty"`
 }
 
 type LeaderWorkerSetConditionType string
@@ -322,6 +386,15 @@ const (
 	// is true when the lws is in upgrade process after the (leader/worker) template is updated. If only replicas is modified, it will
 	// not be considered as UpdateInProgress.
 	LeaderWorkerSetUpdateInProgress LeaderWorkerSetConditionType = "UpdateInProgress"
+
+	// LeaderWorkerSetDegraded means a group was recreated after one of its pods failed, and not
+	// all the groups have been ready since. The reason summarizes the failure, e.g. OOMKilled, and
+	// the message details it, so that it can be diagnosed once the pods are gone.
+	LeaderWorkerSetDegraded LeaderWorkerSetConditionType = "Degraded"
+
+	// LeaderWorkerSetUnsupportedWithoutWebhooks means the lws relies on settings which aren't supported
+	// since the controller runs without webhooks, its groups are not created and the message lists them.
+	LeaderWorkerSetUnsupportedWithoutWebhooks LeaderWorkerSetConditionType = "UnsupportedWithoutWebhooks"
 )
 
 // +genclient
@@ -329,6 +402,7 @@ const (
 //+kubebuilder:subresource:status
 //+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.hpaPodSelector
 //+kubebuilder:resource:shortName={lws}
+//+kubebuilder:storageversion
 
 // LeaderWorkerSet is the Schema for the leaderworkersets API
 type LeaderWorkerSet struct {
@@ -122,6 +122,11 @@ func (in *LeaderWorkerSetStatus) DeepCopyInto(out *LeaderWorkerSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CollisionCount != nil {
+		in, out := &in.CollisionCount, &out.CollisionCount
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderWorkerSetStatus.
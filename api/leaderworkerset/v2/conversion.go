@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"maps"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/utils/ptr"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// The conversions below complete the generated ones of zz_generated.conversion.go for the fields
+// that changed between v1 and v2. The exclusive placement annotations of v1 are fields in v2, and
+// the deprecated Default restart policy of v1 is converted to None.
+
+func Convert_v1_LeaderWorkerSet_To_v2_LeaderWorkerSet(in *v1.LeaderWorkerSet, out *LeaderWorkerSet, s conversion.Scope) error {
+	if err := autoConvert_v1_LeaderWorkerSet_To_v2_LeaderWorkerSet(in, out, s); err != nil {
+		return err
+	}
+	// The annotations are shared with the input object, don't remove the keys in place.
+	out.Annotations = maps.Clone(in.Annotations)
+	if key, found := out.Annotations[v1.ExclusiveKeyAnnotationKey]; found {
+		out.Spec.LeaderWorkerTemplate.ExclusiveTopologyKey = ptr.To(key)
+		delete(out.Annotations, v1.ExclusiveKeyAnnotationKey)
+	}
+	// Without a subgroup policy the annotation has no field to go to, it's kept as is.
+	if key, found := out.Annotations[v1.SubGroupExclusiveKeyAnnotationKey]; found && out.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
+		out.Spec.LeaderWorkerTemplate.SubGroupPolicy.ExclusiveTopologyKey = ptr.To(key)
+		delete(out.Annotations, v1.SubGroupExclusiveKeyAnnotationKey)
+	}
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+	return nil
+}
+
+func Convert_v2_LeaderWorkerSet_To_v1_LeaderWorkerSet(in *LeaderWorkerSet, out *v1.LeaderWorkerSet, s conversion.Scope) error {
+	if err := autoConvert_v2_LeaderWorkerSet_To_v1_LeaderWorkerSet(in, out, s); err != nil {
+		return err
+	}
+	template := in.Spec.LeaderWorkerTemplate
+	exclusiveKey := template.ExclusiveTopologyKey
+	var subGroupExclusiveKey *string
+	if template.SubGroupPolicy != nil {
+		subGroupExclusiveKey = template.SubGroupPolicy.ExclusiveTopologyKey
+	}
+	if exclusiveKey == nil && subGroupExclusiveKey == nil {
+		return nil
+	}
+	// The annotations are shared with the input object, don't add the keys in place.
+	out.Annotations = maps.Clone(in.Annotations)
+	if out.Annotations == nil {
+		out.Annotations = map[string]string{}
+	}
+	if exclusiveKey != nil {
+		out.Annotations[v1.ExclusiveKeyAnnotationKey] = *exclusiveKey
+	}
+	if subGroupExclusiveKey != nil {
+		out.Annotations[v1.SubGroupExclusiveKeyAnnotationKey] = *subGroupExclusiveKey
+	}
+	return nil
+}
+
+func Convert_v1_LeaderWorkerTemplate_To_v2_LeaderWorkerTemplate(in *v1.LeaderWorkerTemplate, out *LeaderWorkerTemplate, s conversion.Scope) error {
+	if err := autoConvert_v1_LeaderWorkerTemplate_To_v2_LeaderWorkerTemplate(in, out, s); err != nil {
+		return err
+	}
+	if in.RestartPolicy == v1.DeprecatedDefaultRestartPolicy {
+		out.RestartPolicy = NoneRestartPolicy
+	}
+	return nil
+}
+
+// Convert_v2_LeaderWorkerTemplate_To_v1_LeaderWorkerTemplate leaves ExclusiveTopologyKey to the
+// LeaderWorkerSet conversion, since it's an annotation of the LeaderWorkerSet in v1.
+func Convert_v2_LeaderWorkerTemplate_To_v1_LeaderWorkerTemplate(in *LeaderWorkerTemplate, out *v1.LeaderWorkerTemplate, s conversion.Scope) error {
+	return autoConvert_v2_LeaderWorkerTemplate_To_v1_LeaderWorkerTemplate(in, out, s)
+}
+
+// Convert_v2_SubGroupPolicy_To_v1_SubGroupPolicy leaves ExclusiveTopologyKey to the
+// LeaderWorkerSet conversion, since it's an annotation of the LeaderWorkerSet in v1.
+func Convert_v2_SubGroupPolicy_To_v1_SubGroupPolicy(in *SubGroupPolicy, out *v1.SubGroupPolicy, s conversion.Scope) error {
+	return autoConvert_v2_SubGroupPolicy_To_v1_SubGroupPolicy(in, out, s)
+}
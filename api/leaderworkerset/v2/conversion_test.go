@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func v1LeaderWorkerSet(annotations map[string]string, restartPolicy v1.RestartPolicyType, subGroupPolicy *v1.SubGroupPolicy) *v1.LeaderWorkerSet {
+	return &v1.LeaderWorkerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "lws", Namespace: "default", Annotations: annotations},
+		Spec: v1.LeaderWorkerSetSpec{
+			Replicas: ptr.To[int32](2),
+			LeaderWorkerTemplate: v1.LeaderWorkerTemplate{
+				Size:           ptr.To[int32](4),
+				RestartPolicy:  restartPolicy,
+				SubGroupPolicy: subGroupPolicy,
+				WorkerTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "nginx"}}},
+				},
+			},
+			RolloutStrategy: v1.RolloutStrategy{
+				Type: v1.RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &v1.RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(1),
+					MaxSurge:       intstr.FromInt32(0),
+				},
+			},
+			StartupPolicy: v1.LeaderCreatedStartupPolicy,
+		},
+		Status: v1.LeaderWorkerSetStatus{Replicas: 2, ReadyReplicas: 1, HPAPodSelector: "leaderworkerset.sigs.k8s.io/name=lws"},
+	}
+}
+
+func v2LeaderWorkerSet(annotations map[string]string, restartPolicy RestartPolicyType, exclusiveKey *string, subGroupPolicy *SubGroupPolicy) *LeaderWorkerSet {
+	return &LeaderWorkerSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "lws", Namespace: "default", Annotations: annotations},
+		Spec: LeaderWorkerSetSpec{
+			Replicas: ptr.To[int32](2),
+			LeaderWorkerTemplate: LeaderWorkerTemplate{
+				Size:                 ptr.To[int32](4),
+				RestartPolicy:        restartPolicy,
+				ExclusiveTopologyKey: exclusiveKey,
+				SubGroupPolicy:       subGroupPolicy,
+				WorkerTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "nginx"}}},
+				},
+			},
+			RolloutStrategy: RolloutStrategy{
+				Type: RollingUpdateStrategyType,
+				RollingUpdateConfiguration: &RollingUpdateConfiguration{
+					MaxUnavailable: intstr.FromInt32(1),
+					MaxSurge:       intstr.FromInt32(0),
+				},
+			},
+			StartupPolicy: LeaderCreatedStartupPolicy,
+		},
+		Status: LeaderWorkerSetStatus{Replicas: 2, ReadyReplicas: 1, HPAPodSelector: "leaderworkerset.sigs.k8s.io/name=lws"},
+	}
+}
+
+func TestConvertV1ToV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       *v1.LeaderWorkerSet
+		expected *LeaderWorkerSet
+		// roundTrip is the v1 object expected after converting back, when it's not the input.
+		roundTrip *v1.LeaderWorkerSet
+	}{
+		{
+			name:     "no annotations",
+			in:       v1LeaderWorkerSet(nil, v1.RecreateGroupOnPodRestart, nil),
+			expected: v2LeaderWorkerSet(nil, RecreateGroupOnPodRestart, nil, nil),
+		},
+		{
+			name: "exclusive annotations become fields",
+			in: v1LeaderWorkerSet(map[string]string{
+				v1.ExclusiveKeyAnnotationKey:         "topology.kubernetes.io/zone",
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+				"foo":                                "bar",
+			}, v1.RecreateGroupOnPodRestart, &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2)}),
+			expected: v2LeaderWorkerSet(map[string]string{"foo": "bar"}, RecreateGroupOnPodRestart,
+				ptr.To("topology.kubernetes.io/zone"),
+				&SubGroupPolicy{SubGroupSize: ptr.To[int32](2), ExclusiveTopologyKey: ptr.To("cloud.google.com/gke-nodepool")}),
+		},
+		{
+			name: "subgroup exclusive annotation is kept without a subgroup policy",
+			in: v1LeaderWorkerSet(map[string]string{
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+			}, v1.RecreateGroupOnPodRestart, nil),
+			expected: v2LeaderWorkerSet(map[string]string{
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+			}, RecreateGroupOnPodRestart, nil, nil),
+		},
+		{
+			// Default is deprecated, converting it to None loses the Default value on purpose.
+			name:      "deprecated Default restart policy becomes None and doesn't round-trip",
+			in:        v1LeaderWorkerSet(nil, v1.DeprecatedDefaultRestartPolicy, nil),
+			expected:  v2LeaderWorkerSet(nil, NoneRestartPolicy, nil, nil),
+			roundTrip: v1LeaderWorkerSet(nil, v1.NoneRestartPolicy, nil),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := newScheme(t)
+			in := tc.in.DeepCopy()
+			got := &LeaderWorkerSet{}
+			if err := scheme.Convert(in, got, nil); err != nil {
+				t.Fatalf("Failed to convert to v2: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Unexpected v2 object (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.in, in); diff != "" {
+				t.Errorf("The input object was mutated (-want,+got):\n%s", diff)
+			}
+
+			back := &v1.LeaderWorkerSet{}
+			if err := scheme.Convert(got, back, nil); err != nil {
+				t.Fatalf("Failed to convert back to v1: %v", err)
+			}
+			wantBack := tc.in
+			if tc.roundTrip != nil {
+				wantBack = tc.roundTrip
+			}
+			if diff := cmp.Diff(wantBack, back); diff != "" {
+				t.Errorf("Unexpected v1 object after the round trip (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertV2ToV1(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       *LeaderWorkerSet
+		expected *v1.LeaderWorkerSet
+	}{
+		{
+			name:     "no exclusive placement",
+			in:       v2LeaderWorkerSet(map[string]string{"foo": "bar"}, NoneRestartPolicy, nil, nil),
+			expected: v1LeaderWorkerSet(map[string]string{"foo": "bar"}, v1.NoneRestartPolicy, nil),
+		},
+		{
+			name: "exclusive fields become annotations",
+			in: v2LeaderWorkerSet(nil, RecreateGroupOnPodRestart, ptr.To("topology.kubernetes.io/zone"),
+				&SubGroupPolicy{SubGroupSize: ptr.To[int32](2), ExclusiveTopologyKey: ptr.To("cloud.google.com/gke-nodepool")}),
+			expected: v1LeaderWorkerSet(map[string]string{
+				v1.ExclusiveKeyAnnotationKey:         "topology.kubernetes.io/zone",
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+			}, v1.RecreateGroupOnPodRestart, &v1.SubGroupPolicy{SubGroupSize: ptr.To[int32](2)}),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := newScheme(t)
+			in := tc.in.DeepCopy()
+			got := &v1.LeaderWorkerSet{}
+			if err := scheme.Convert(in, got, nil); err != nil {
+				t.Fatalf("Failed to convert to v1: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("Unexpected v1 object (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.in, in); diff != "" {
+				t.Errorf("The input object was mutated (-want,+got):\n%s", diff)
+			}
+
+			back := &LeaderWorkerSet{}
+			if err := scheme.Convert(got, back, nil); err != nil {
+				t.Fatalf("Failed to convert back to v2: %v", err)
+			}
+			if diff := cmp.Diff(tc.in, back); diff != "" {
+				t.Errorf("Unexpected v2 object after the round trip (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
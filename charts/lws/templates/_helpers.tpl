@@ -74,3 +74,27 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Whether the controller is restricted to some namespaces or to a shard of the LeaderWorkerSets
+*/}}
+{{- define "lws.scoped" -}}
+{{- if or .Values.scope.namespaces .Values.scope.namespaceSelector .Values.scope.shard }}true{{- end }}
+{{- end }}
+
+{{/*
+Namespace selector of the webhooks matching the scope of the controller
+*/}}
+{{- define "lws.webhookNamespaceSelector" -}}
+{{- if .Values.scope.namespaces }}
+namespaceSelector:
+  matchExpressions:
+    - key: kubernetes.io/metadata.name
+      operator: In
+      values:
+        {{- toYaml .Values.scope.namespaces | nindent 8 }}
+{{- else if .Values.scope.namespaceSelector }}
+namespaceSelector:
+  {{- toYaml .Values.scope.namespaceSelector | nindent 2 }}
+{{- end }}
+{{- end }}
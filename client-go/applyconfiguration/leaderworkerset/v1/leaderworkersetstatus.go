@@ -24,11 +24,16 @@ import (
 // LeaderWorkerSetStatusApplyConfiguration represents a declarative configuration of the LeaderWorkerSetStatus type for use
 // with apply.
 type LeaderWorkerSetStatusApplyConfiguration struct {
-	Conditions      []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
-	ReadyReplicas   *int32                               `json:"readyReplicas,omitempty"`
-	UpdatedReplicas *int32                               `json:"updatedReplicas,omitempty"`
-	Replicas        *int32                               `json:"replicas,omitempty"`
-	HPAPodSelector  *string                              `json:"hpaPodSelector,omitempty"`
+	Conditions         []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	ReadyReplicas      *int32                               `json:"readyReplicas,omitempty"`
+	UpdatedReplicas    *int32                               `json:"updatedReplicas,omitempty"`
+	Replicas           *int32                               `json:"replicas,omitempty"`
+	HPAPodSelector     *string                              `json:"hpaPodSelector,omitempty"`
+	ObservedGeneration *int64                               `json:"observedGeneration,omitempty"`
+	AvailableReplicas  *int32                               `json:"availableReplicas,omitempty"`
+	CurrentRevision    *string                              `json:"currentRevision,omitempty"`
+	UpdateRevision     *string                              `json:"updateRevision,omitempty"`
+	CollisionCount     *int32                               `json:"collisionCount,omitempty"`
 }
 
 // LeaderWorkerSetStatusApplyConfiguration constructs a declarative configuration of the LeaderWorkerSetStatus type for use with
@@ -81,3 +86,43 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithHPAPodSelector(value strin
 	b.HPAPodSelector = &value
 	return b
 }
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithObservedGeneration(value int64) *LeaderWorkerSetStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithAvailableReplicas sets the AvailableReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AvailableReplicas field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithAvailableReplicas(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.AvailableReplicas = &value
+	return b
+}
+
+// WithCurrentRevision sets the CurrentRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CurrentRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCurrentRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.CurrentRevision = &value
+	return b
+}
+
+// WithUpdateRevision sets the UpdateRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdateRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithUpdateRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.UpdateRevision = &value
+	return b
+}
+
+// WithCollisionCount sets the CollisionCount field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CollisionCount field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCollisionCount(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.CollisionCount = &value
+	return b
+}
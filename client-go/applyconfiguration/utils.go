@@ -22,8 +22,10 @@ import (
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	testing "k8s.io/client-go/testing"
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	v2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
 	internal "sigs.k8s.io/lws/client-go/applyconfiguration/internal"
 	leaderworkersetv1 "sigs.k8s.io/lws/client-go/applyconfiguration/leaderworkerset/v1"
+	leaderworkersetv2 "sigs.k8s.io/lws/client-go/applyconfiguration/leaderworkerset/v2"
 )
 
 // ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
@@ -48,6 +50,24 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 	case v1.SchemeGroupVersion.WithKind("SubGroupPolicy"):
 		return &leaderworkersetv1.SubGroupPolicyApplyConfiguration{}
 
+		// Group=leaderworkerset.x-k8s.io, Version=v2
+	case v2.SchemeGroupVersion.WithKind("LeaderWorkerSet"):
+		return &leaderworkersetv2.LeaderWorkerSetApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("LeaderWorkerSetSpec"):
+		return &leaderworkersetv2.LeaderWorkerSetSpecApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("LeaderWorkerSetStatus"):
+		return &leaderworkersetv2.LeaderWorkerSetStatusApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("LeaderWorkerTemplate"):
+		return &leaderworkersetv2.LeaderWorkerTemplateApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("NetworkConfig"):
+		return &leaderworkersetv2.NetworkConfigApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("RollingUpdateConfiguration"):
+		return &leaderworkersetv2.RollingUpdateConfigurationApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("RolloutStrategy"):
+		return &leaderworkersetv2.RolloutStrategyApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("SubGroupPolicy"):
+		return &leaderworkersetv2.SubGroupPolicyApplyConfiguration{}
+
 	}
 	return nil
 }
@@ -25,17 +25,20 @@ import (
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
 	leaderworkersetv1 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v1"
+	leaderworkersetv2 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v2"
 )
 
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	LeaderworkersetV1() leaderworkersetv1.LeaderworkersetV1Interface
+	LeaderworkersetV2() leaderworkersetv2.LeaderworkersetV2Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	leaderworkersetV1 *leaderworkersetv1.LeaderworkersetV1Client
+	leaderworkersetV2 *leaderworkersetv2.LeaderworkersetV2Client
 }
 
 // LeaderworkersetV1 retrieves the LeaderworkersetV1Client
@@ -43,6 +46,11 @@ func (c *Clientset) LeaderworkersetV1() leaderworkersetv1.LeaderworkersetV1Inter
 	return c.leaderworkersetV1
 }
 
+// LeaderworkersetV2 retrieves the LeaderworkersetV2Client
+func (c *Clientset) LeaderworkersetV2() leaderworkersetv2.LeaderworkersetV2Interface {
+	return c.leaderworkersetV2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -91,6 +99,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.leaderworkersetV2, err = leaderworkersetv2.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -113,6 +125,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.leaderworkersetV1 = leaderworkersetv1.New(c)
+	cs.leaderworkersetV2 = leaderworkersetv2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
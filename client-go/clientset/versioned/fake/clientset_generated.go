@@ -27,6 +27,8 @@ import (
 	clientset "sigs.k8s.io/lws/client-go/clientset/versioned"
 	leaderworkersetv1 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v1"
 	fakeleaderworkersetv1 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v1/fake"
+	leaderworkersetv2 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v2"
+	fakeleaderworkersetv2 "sigs.k8s.io/lws/client-go/clientset/versioned/typed/leaderworkerset/v2/fake"
 )
 
 // NewSimpleClientset returns a clientset that will respond with the provided objects.
@@ -119,3 +121,8 @@ var (
 func (c *Clientset) LeaderworkersetV1() leaderworkersetv1.LeaderworkersetV1Interface {
 	return &fakeleaderworkersetv1.FakeLeaderworkersetV1{Fake: &c.Fake}
 }
+
+// LeaderworkersetV2 retrieves the LeaderworkersetV2Client
+func (c *Clientset) LeaderworkersetV2() leaderworkersetv2.LeaderworkersetV2Interface {
+	return &fakeleaderworkersetv2.FakeLeaderworkersetV2{Fake: &c.Fake}
+}
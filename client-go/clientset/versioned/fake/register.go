@@ -24,6 +24,7 @@ import (
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	leaderworkersetv2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
 )
 
 var scheme = runtime.NewScheme()
@@ -31,6 +32,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	leaderworkersetv1.AddToScheme,
+	leaderworkersetv2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -23,6 +23,7 @@ import (
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	v2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
 )
 
 // GenericInformer is type of SharedIndexInformer which will locate and delegate to other
@@ -55,6 +56,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1.SchemeGroupVersion.WithResource("leaderworkersets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Leaderworkerset().V1().LeaderWorkerSets().Informer()}, nil
 
+		// Group=leaderworkerset.x-k8s.io, Version=v2
+	case v2.SchemeGroupVersion.WithResource("leaderworkersets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Leaderworkerset().V2().LeaderWorkerSets().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/utils/ptr"
+
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+)
+
+func newDescribeCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe NAME",
+		Short: "Show the details of a LeaderWorkerSet, including the layout of its subgroups",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.describe(cmd.Context(), args[0])
+		},
+	}
+}
+
+func (o *options) describe(ctx context.Context, name string) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	spec := lws.Spec
+	size := int(ptr.Deref(spec.LeaderWorkerTemplate.Size, 1))
+
+	w := tabwriter.NewWriter(o.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", lws.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", lws.Namespace)
+	fmt.Fprintf(w, "Replicas:\t%d desired | %d total | %d ready | %d updated | %d available\n",
+		ptr.Deref(spec.Replicas, 1), lws.Status.Replicas, lws.Status.ReadyReplicas, lws.Status.UpdatedReplicas, lws.Status.AvailableReplicas)
+	fmt.Fprintf(w, "Size:\t%d\n", size)
+	fmt.Fprintf(w, "Restart Policy:\t%s\n", spec.LeaderWorkerTemplate.RestartPolicy)
+	fmt.Fprintf(w, "Startup Policy:\t%s\n", spec.StartupPolicy)
+	fmt.Fprintf(w, "Rollout Strategy:\t%s\n", spec.RolloutStrategy.Type)
+	if config := spec.RolloutStrategy.RollingUpdateConfiguration; config != nil {
+		fmt.Fprintf(w, "  Max Unavailable:\t%s\n", config.MaxUnavailable.String())
+		fmt.Fprintf(w, "  Max Surge:\t%s\n", config.MaxSurge.String())
+	}
+	fmt.Fprintf(w, "Rollout Paused:\t%t\n", rolloutPaused(lws))
+	fmt.Fprintf(w, "Current Revision:\t%s\n", valueOrNone(lws.Status.CurrentRevision))
+	fmt.Fprintf(w, "Update Revision:\t%s\n", valueOrNone(lws.Status.UpdateRevision))
+
+	fmt.Fprintln(w, "Subgroups:")
+	if policy := spec.LeaderWorkerTemplate.SubGroupPolicy; policy == nil || policy.SubGroupSize == nil {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintf(w, "  Size:\t%d\n", *policy.SubGroupSize)
+		fmt.Fprintln(w, "  SUBGROUP\tWORKER INDEXES")
+		for i, indexes := range subGroupLayout(size, int(*policy.SubGroupSize)) {
+			fmt.Fprintf(w, "  %d\t%s\n", i, strings.Join(indexes, ","))
+		}
+	}
+
+	fmt.Fprintln(w, "Conditions:")
+	if len(lws.Status.Conditions) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, condition := range lws.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+	return w.Flush()
+}
+
+// subGroupLayout returns the worker indexes of each subgroup, the leader (index 0) is always part of the first one.
+func subGroupLayout(size, subGroupSize int) [][]string {
+	layout := [][]string{{"0"}}
+	for workerIndex := 1; workerIndex < size; workerIndex++ {
+		subGroup, _ := strconv.Atoi(podutils.GetSubGroupIndex(size, subGroupSize, workerIndex))
+		for len(layout) <= subGroup {
+			layout = append(layout, nil)
+		}
+		layout[subGroup] = append(layout[subGroup], strconv.Itoa(workerIndex))
+	}
+	return layout
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSubGroupLayout(t *testing.T) {
+	testCases := []struct {
+		name         string
+		size         int
+		subGroupSize int
+		want         [][]string
+	}{
+		{
+			name:         "size divisible by subGroupSize",
+			size:         4,
+			subGroupSize: 2,
+			want:         [][]string{{"0", "1"}, {"2", "3"}},
+		},
+		{
+			name:         "leader as the extra pod of the first subgroup",
+			size:         5,
+			subGroupSize: 2,
+			want:         [][]string{{"0", "1", "2"}, {"3", "4"}},
+		},
+		{
+			name:         "leader only",
+			size:         1,
+			subGroupSize: 1,
+			want:         [][]string{{"0"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, subGroupLayout(tc.size, tc.subGroupSize)); diff != "" {
+				t.Errorf("unexpected layout (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
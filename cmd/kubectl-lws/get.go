@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
+)
+
+func newGetCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Display the resources of a LeaderWorkerSet",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "groups NAME",
+		Short: "List the groups of a LeaderWorkerSet with their revision, ready pods and nodes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.getGroups(cmd.Context(), args[0])
+		},
+	})
+	return cmd
+}
+
+// group is a group of pods of a leaderworkerset.
+type group struct {
+	index  int
+	leader *corev1.Pod
+	pods   []corev1.Pod
+}
+
+// listGroups returns the groups of the leaderworkerset sorted by index.
+func (o *options) listGroups(ctx context.Context, name string) ([]*group, error) {
+	pods, err := o.listPods(ctx, name, false)
+	if err != nil {
+		return nil, err
+	}
+	groupsByIndex := map[int]*group{}
+	for i := range pods {
+		pod := &pods[i]
+		index, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			// The pod wasn't labeled yet.
+			continue
+		}
+		g, found := groupsByIndex[index]
+		if !found {
+			g = &group{index: index}
+			groupsByIndex[index] = g
+		}
+		g.pods = append(g.pods, *pod)
+		if podutils.LeaderPod(*pod) {
+			g.leader = pod
+		}
+	}
+	groups := make([]*group, 0, len(groupsByIndex))
+	for _, g := range groupsByIndex {
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b *group) int { return a.index - b.index })
+	return groups, nil
+}
+
+func (o *options) getGroups(ctx context.Context, name string) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	groups, err := o.listGroups(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		o.printf("No groups found for leaderworkerset %q in namespace %s.\n", name, o.namespace)
+		return nil
+	}
+	w := tabwriter.NewWriter(o.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tLEADER\tREVISION\tREADY\tNODES")
+	for _, g := range groups {
+		leader, revision := "<none>", "<none>"
+		if g.leader != nil {
+			leader = g.leader.Name
+			revision = revisionutils.GetRevisionKey(g.leader)
+		}
+		ready := 0
+		nodes := sets.New[string]()
+		for _, pod := range g.pods {
+			if podutils.PodRunningAndReady(pod) {
+				ready++
+			}
+			if pod.Spec.NodeName != "" {
+				nodes.Insert(pod.Spec.NodeName)
+			}
+		}
+		nodeNames := "<none>"
+		if nodes.Len() > 0 {
+			nodeNames = strings.Join(sets.List(nodes), ",")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d/%d\t%s\n", g.index, leader, revision, ready, *lws.Spec.LeaderWorkerTemplate.Size, nodeNames)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/client-go/clientset/versioned/fake"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+// newTestOptions returns options backed by fake clientsets, with the output written to the returned buffer.
+func newTestOptions(lwsObjects []runtime.Object, kubeObjects []runtime.Object) (*options, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	o := newOptions(out, out)
+	o.namespace = "default"
+	o.lwsClient = fake.NewSimpleClientset(lwsObjects...)
+	o.kubeClient = kubefake.NewSimpleClientset(kubeObjects...)
+	return o, out
+}
+
+// makePod returns a pod of the group, ready on the node if nodeName is set.
+func makePod(groupIndex, workerIndex, revision, nodeName string) *corev1.Pod {
+	pod := wrappers.MakePodWithLabels("test-sample", groupIndex, workerIndex, "default", 2)
+	pod.Labels[leaderworkerset.WorkerIndexLabelKey] = workerIndex
+	pod.Labels[leaderworkerset.RevisionKey] = revision
+	if nodeName != "" {
+		pod.Spec.NodeName = nodeName
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func TestGetGroups(t *testing.T) {
+	testCases := []struct {
+		name string
+		pods []runtime.Object
+		want string
+	}{
+		{
+			name: "no groups",
+			want: "No groups found for leaderworkerset \"test-sample\" in namespace default.\n",
+		},
+		{
+			name: "groups with ready, unready and unscheduled pods",
+			pods: []runtime.Object{
+				makePod("1", "0", "rev-2", "node-b"),
+				makePod("1", "1", "rev-2", ""),
+				makePod("0", "0", "rev-1", "node-a"),
+				makePod("0", "1", "rev-1", "node-b"),
+			},
+			want: `GROUP  LEADER         REVISION  READY  NODES
+0      test-sample-0  rev-1     2/2    node-a,node-b
+1      test-sample-1  rev-2     1/2    node-b
+`,
+		},
+		{
+			name: "group without leader",
+			pods: []runtime.Object{makePod("0", "1", "rev-1", "")},
+			want: `GROUP  LEADER  REVISION  READY  NODES
+0      <none>  <none>    0/2    <none>
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			o, out := newTestOptions([]runtime.Object{lws}, tc.pods)
+			if err := o.getGroups(context.TODO(), lws.Name); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
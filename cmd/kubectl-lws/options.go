@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/client-go/clientset/versioned"
+)
+
+// options holds the clients and the output streams shared by the subcommands.
+type options struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+	overrides    *clientcmd.ConfigOverrides
+
+	namespace string
+	// The clients are built from the kubeconfig unless they are set, e.g. to fake clientsets in tests.
+	lwsClient  versioned.Interface
+	kubeClient kubernetes.Interface
+
+	out    io.Writer
+	errOut io.Writer
+	// now returns the time of the restart requests, it's replaced in tests.
+	now func() time.Time
+}
+
+func newOptions(out, errOut io.Writer) *options {
+	return &options{
+		loadingRules: clientcmd.NewDefaultClientConfigLoadingRules(),
+		overrides:    &clientcmd.ConfigOverrides{},
+		out:          out,
+		errOut:       errOut,
+		now:          time.Now,
+	}
+}
+
+func (o *options) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.loadingRules.ExplicitPath, clientcmd.RecommendedConfigPathFlag, "", "Path to the kubeconfig file to use for CLI requests.")
+	clientcmd.BindOverrideFlags(o.overrides, flags, clientcmd.RecommendedConfigOverrideFlags(""))
+}
+
+// complete resolves the namespace and builds the clients from the kubeconfig.
+func (o *options) complete() error {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(o.loadingRules, o.overrides)
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+	o.namespace = namespace
+	if o.lwsClient != nil && o.kubeClient != nil {
+		return nil
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if o.lwsClient, err = versioned.NewForConfig(restConfig); err != nil {
+		return err
+	}
+	o.kubeClient, err = kubernetes.NewForConfig(restConfig)
+	return err
+}
+
+func (o *options) getLeaderWorkerSet(ctx context.Context, name string) (*leaderworkerset.LeaderWorkerSet, error) {
+	return o.lwsClient.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// listPods lists the pods of the leaderworkerset, the leader pods only if leadersOnly is set.
+func (o *options) listPods(ctx context.Context, name string, leadersOnly bool) ([]corev1.Pod, error) {
+	selector := labels.Set{leaderworkerset.SetNameLabelKey: name}
+	if leadersOnly {
+		selector[leaderworkerset.WorkerIndexLabelKey] = "0"
+	}
+	pods, err := o.kubeClient.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func newRootCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubectl-lws",
+		Short: "Inspect the groups of a LeaderWorkerSet and manage its rollouts",
+		// Errors returned by the subcommands, e.g. a resource not found, are not usage errors.
+		SilenceUsage: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return o.complete()
+		},
+	}
+	cmd.SetOut(o.out)
+	cmd.SetErr(o.errOut)
+	o.addFlags(cmd.PersistentFlags())
+	cmd.AddCommand(
+		newGetCommand(o),
+		newRolloutCommand(o),
+		newRestartCommand(o),
+		newDescribeCommand(o),
+	)
+	return cmd
+}
+
+// printf writes to the output of the subcommands, the errors of the writer are ignored like fmt.Printf does.
+func (o *options) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(o.out, format, args...)
+}
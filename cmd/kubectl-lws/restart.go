@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func newRestartCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the resources of a LeaderWorkerSet",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "group NAME INDEX",
+		Short: "Restart a group, its leader pod is deleted by the controller and the group is recreated with its workers",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.restartGroup(cmd.Context(), args[0], args[1])
+		},
+	})
+	return cmd
+}
+
+// restartGroup requests the restart of the group with the restart-groups annotation, the controller
+// then recreates the group and removes the annotation.
+func (o *options) restartGroup(ctx context.Context, name, index string) error {
+	var groupIndex int
+	var alreadyRequested bool
+	// The annotation is patched with the resourceVersion it was read at, so that a concurrent request or
+	// the removal of the annotation by the controller isn't overwritten, and it's read again on conflicts.
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lws, err := o.getLeaderWorkerSet(ctx, name)
+		if err != nil {
+			return err
+		}
+		groupIndex, err = strconv.Atoi(index)
+		if err != nil || groupIndex < 0 || groupIndex >= int(*lws.Spec.Replicas) {
+			return fmt.Errorf("invalid group index %q, leaderworkerset %q has %d groups", index, name, *lws.Spec.Replicas)
+		}
+		// The groups whose restart is still pending are kept.
+		indexes := []string{strconv.Itoa(groupIndex)}
+		pending, found := lws.Annotations[leaderworkerset.RestartGroupsAnnotationKey]
+		alreadyRequested = found && slices.Contains(strings.Split(pending, ","), indexes[0])
+		if alreadyRequested {
+			return nil
+		}
+		if found {
+			indexes = append([]string{pending}, indexes...)
+		}
+		return o.patchAnnotation(ctx, name, lws.ResourceVersion, leaderworkerset.RestartGroupsAnnotationKey, ptr.To(strings.Join(indexes, ",")))
+	})
+	if err != nil {
+		return err
+	}
+	if alreadyRequested {
+		o.printf("restart of group %d of leaderworkerset %q already requested\n", groupIndex, name)
+		return nil
+	}
+	o.printf("restart of group %d of leaderworkerset %q requested\n", groupIndex, name)
+	return nil
+}
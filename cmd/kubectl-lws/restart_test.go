@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/client-go/clientset/versioned/fake"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+func TestRestartGroup(t *testing.T) {
+	testCases := []struct {
+		name            string
+		pending         string
+		index           string
+		wantOutput      string
+		wantErr         string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "restart requested",
+			index:           "1",
+			wantOutput:      "restart of group 1 of leaderworkerset \"test-sample\" requested\n",
+			wantAnnotations: map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "1"},
+		},
+		{
+			name:            "restart requested with another pending one",
+			pending:         "0",
+			index:           "1",
+			wantOutput:      "restart of group 1 of leaderworkerset \"test-sample\" requested\n",
+			wantAnnotations: map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,1"},
+		},
+		{
+			name:            "restart already requested",
+			pending:         "1",
+			index:           "1",
+			wantOutput:      "restart of group 1 of leaderworkerset \"test-sample\" already requested\n",
+			wantAnnotations: map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "1"},
+		},
+		{
+			name:    "index out of range",
+			index:   "2",
+			wantErr: "invalid group index \"2\", leaderworkerset \"test-sample\" has 2 groups",
+		},
+		{
+			name:    "invalid index",
+			index:   "leader",
+			wantErr: "invalid group index \"leader\", leaderworkerset \"test-sample\" has 2 groups",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if tc.pending != "" {
+				lws.Annotations = map[string]string{leaderworkerset.RestartGroupsAnnotationKey: tc.pending}
+			}
+			o, out := newTestOptions([]runtime.Object{lws}, nil)
+			err := o.restartGroup(context.TODO(), lws.Name, tc.index)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOutput, out.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+			got, err := o.getLeaderWorkerSet(context.TODO(), lws.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, got.Annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRestartGroupConflict(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	lws.ResourceVersion = "1"
+	o, out := newTestOptions([]runtime.Object{lws}, nil)
+	lwsClient := o.lwsClient.(*fake.Clientset)
+	var patches []string
+	// The restart of group 0 is requested concurrently, after the leaderworkerset was read by the first attempt.
+	lwsClient.PrependReactor("patch", "leaderworkersets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := string(action.(clienttesting.PatchAction).GetPatch())
+		patches = append(patches, patch)
+		if len(patches) > 1 {
+			return false, nil, nil
+		}
+		concurrent := lws.DeepCopy()
+		concurrent.Annotations = map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0"}
+		concurrent.ResourceVersion = "2"
+		if err := lwsClient.Tracker().Update(leaderworkerset.GroupVersion.WithResource("leaderworkersets"), concurrent, concurrent.Namespace); err != nil {
+			return true, nil, err
+		}
+		return true, nil, apierrors.NewConflict(leaderworkerset.GroupVersion.WithResource("leaderworkersets").GroupResource(), lws.Name, errors.New("the object has been modified"))
+	})
+
+	if err := o.restartGroup(context.TODO(), lws.Name, "1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantPatches := []string{
+		`{"metadata":{"annotations":{"leaderworkerset.sigs.k8s.io/restart-groups":"1"},"resourceVersion":"1"}}`,
+		`{"metadata":{"annotations":{"leaderworkerset.sigs.k8s.io/restart-groups":"0,1"},"resourceVersion":"2"}}`,
+	}
+	if diff := cmp.Diff(wantPatches, patches); diff != "" {
+		t.Errorf("unexpected patches (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("restart of group 1 of leaderworkerset \"test-sample\" requested\n", out.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+	got, err := o.getLeaderWorkerSet(context.TODO(), lws.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,1"}, got.Annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
+)
+
+func newRolloutCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Manage the rollout of a LeaderWorkerSet",
+	}
+
+	var watchStatus bool
+	var timeout time.Duration
+	status := &cobra.Command{
+		Use:   "status NAME",
+		Short: "Show the status of the rollout, and wait for it to finish unless --watch=false",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.rolloutStatus(cmd.Context(), args[0], watchStatus, timeout)
+		},
+	}
+	status.Flags().BoolVarP(&watchStatus, "watch", "w", true, "Watch the status of the rollout until it's done.")
+	status.Flags().DurationVar(&timeout, "timeout", 0, "The length of time to wait for the rollout, zero means forever.")
+
+	var toRevision int64
+	undo := &cobra.Command{
+		Use:   "undo NAME",
+		Short: "Roll back to the previous revision, or to the one of --to-revision",
+		Long: `Roll back to the previous revision, or to the one of --to-revision.
+The revisions are removed once a rolling update is done, so a leaderworkerset can only be
+rolled back while its rolling update is ongoing.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.rolloutUndo(cmd.Context(), args[0], toRevision)
+		},
+	}
+	undo.Flags().Int64Var(&toRevision, "to-revision", 0, "The revision to roll back to, zero means the previous revision.")
+
+	cmd.AddCommand(
+		status,
+		&cobra.Command{
+			Use:   "history NAME",
+			Short: "List the revisions of the rollout",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.rolloutHistory(cmd.Context(), args[0])
+			},
+		},
+		undo,
+		&cobra.Command{
+			Use:   "pause NAME",
+			Short: "Pause the rolling update, the groups not updated yet keep their revision",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.setRolloutPaused(cmd.Context(), args[0], true)
+			},
+		},
+		&cobra.Command{
+			Use:   "restart NAME",
+			Short: "Restart all the groups with a rolling update honoring maxUnavailable",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.rolloutRestart(cmd.Context(), args[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "resume NAME",
+			Short: "Resume a paused rolling update",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.setRolloutPaused(cmd.Context(), args[0], false)
+			},
+		},
+	)
+	return cmd
+}
+
+func rolloutPaused(lws *leaderworkerset.LeaderWorkerSet) bool {
+	return lws.Annotations[leaderworkerset.RolloutPausedAnnotationKey] == "true"
+}
+
+// rolloutStatusMessage returns the status of the rollout of the leaderworkerset, and whether it's done.
+// Mostly inspired by the DeploymentStatusViewer of kubectl.
+func rolloutStatusMessage(lws *leaderworkerset.LeaderWorkerSet) (string, bool) {
+	if lws.Generation > lws.Status.ObservedGeneration {
+		return "Waiting for rollout to finish: observed leaderworkerset generation less than desired generation\n", false
+	}
+	replicas := *lws.Spec.Replicas
+	if rolloutPaused(lws) && lws.Status.UpdatedReplicas < replicas {
+		return fmt.Sprintf("Rollout is paused: %d out of %d groups have been updated, resume it with \"kubectl lws rollout resume %s\"\n", lws.Status.UpdatedReplicas, replicas, lws.Name), false
+	}
+	if lws.Status.UpdatedReplicas < replicas {
+		return fmt.Sprintf("Waiting for rollout to finish: %d out of %d new groups have been updated...\n", lws.Status.UpdatedReplicas, replicas), false
+	}
+	if lws.Status.Replicas > lws.Status.UpdatedReplicas {
+		return fmt.Sprintf("Waiting for rollout to finish: %d old groups are pending termination...\n", lws.Status.Replicas-lws.Status.UpdatedReplicas), false
+	}
+	if !meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetAvailable)) {
+		return fmt.Sprintf("Waiting for rollout to finish: %d of %d updated groups are ready...\n", lws.Status.ReadyReplicas, replicas), false
+	}
+	return fmt.Sprintf("leaderworkerset %q successfully rolled out\n", lws.Name), true
+}
+
+func (o *options) rolloutStatus(ctx context.Context, name string, watchStatus bool, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	var previous string
+	for {
+		message, done := rolloutStatusMessage(lws)
+		if message != previous {
+			o.printf("%s", message)
+			previous = message
+		}
+		if done || !watchStatus {
+			return nil
+		}
+		if lws, err = o.waitForLeaderWorkerSetChange(ctx, lws); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForLeaderWorkerSetChange returns the leaderworkerset once it changed.
+func (o *options) waitForLeaderWorkerSetChange(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (*leaderworkerset.LeaderWorkerSet, error) {
+	watcher, err := o.lwsClient.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", lws.Name).String(),
+		ResourceVersion: lws.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the rollout of leaderworkerset %q", lws.Name)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				// The watch expired, it's started again from the last seen version.
+				return lws, nil
+			}
+			changed, isLWS := event.Object.(*leaderworkerset.LeaderWorkerSet)
+			if !isLWS || changed.Name != lws.Name {
+				continue
+			}
+			switch event.Type {
+			case watch.Deleted:
+				return nil, fmt.Errorf("leaderworkerset %q was deleted", lws.Name)
+			case watch.Added, watch.Modified:
+				return changed, nil
+			}
+		}
+	}
+}
+
+// listRevisions returns the ControllerRevisions of the leaderworkerset sorted by revision number.
+func (o *options) listRevisions(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) ([]appsv1.ControllerRevision, error) {
+	list, err := o.kubeClient.AppsV1().ControllerRevisions(o.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{leaderworkerset.SetNameLabelKey: lws.Name}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var revisions []appsv1.ControllerRevision
+	for _, revision := range list.Items {
+		if metav1.IsControlledBy(&revision, lws) {
+			revisions = append(revisions, revision)
+		}
+	}
+	slices.SortFunc(revisions, func(a, b appsv1.ControllerRevision) int { return int(a.Revision - b.Revision) })
+	return revisions, nil
+}
+
+func (o *options) rolloutHistory(ctx context.Context, name string) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	revisions, err := o.listRevisions(ctx, lws)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		o.printf("No rollout history found for leaderworkerset %q.\n", name)
+		return nil
+	}
+	w := tabwriter.NewWriter(o.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tKEY\tSTATUS")
+	for _, revision := range revisions {
+		key := revisionutils.GetRevisionKey(&revision)
+		status := ""
+		switch key {
+		case lws.Status.UpdateRevision:
+			status = "update"
+		case lws.Status.CurrentRevision:
+			status = "current"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", revision.Revision, key, status)
+	}
+	return w.Flush()
+}
+
+func (o *options) rolloutUndo(ctx context.Context, name string, toRevision int64) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	revisions, err := o.listRevisions(ctx, lws)
+	if err != nil {
+		return err
+	}
+	var target *appsv1.ControllerRevision
+	for i := range revisions {
+		revision := &revisions[i]
+		if toRevision > 0 && revision.Revision == toRevision {
+			target = revision
+			break
+		}
+		// Without --to-revision, the latest revision but the one being rolled out is the previous one.
+		if toRevision == 0 && revisionutils.GetRevisionKey(revision) != lws.Status.UpdateRevision {
+			target = revision
+		}
+	}
+	if target == nil {
+		if toRevision > 0 {
+			return fmt.Errorf("revision %d of leaderworkerset %q not found", toRevision, name)
+		}
+		return fmt.Errorf("no previous revision found for leaderworkerset %q, the revisions are only kept during a rolling update", name)
+	}
+	if revisionutils.GetRevisionKey(target) == lws.Status.UpdateRevision {
+		o.printf("leaderworkerset %q skipped rollback, revision %d is the current template\n", name, target.Revision)
+		return nil
+	}
+	restored, err := revisionutils.ApplyRevision(lws, target)
+	if err != nil {
+		return err
+	}
+	if _, err := o.lwsClient.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Update(ctx, restored, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	o.printf("leaderworkerset %q rolled back to revision %d\n", name, target.Revision)
+	return nil
+}
+
+func (o *options) setRolloutPaused(ctx context.Context, name string, paused bool) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	verb := map[bool]string{true: "paused", false: "resumed"}[paused]
+	if rolloutPaused(lws) == paused {
+		o.printf("leaderworkerset %q is already %s\n", name, verb)
+		return nil
+	}
+	// A nil value removes the annotation.
+	var value *string
+	if paused {
+		value = ptr.To("true")
+	}
+	if err := o.patchAnnotation(ctx, name, "", leaderworkerset.RolloutPausedAnnotationKey, value); err != nil {
+		return err
+	}
+	o.printf("leaderworkerset %q %s\n", name, verb)
+	return nil
+}
+
+// patchAnnotation sets the annotation of the leaderworkerset, or removes it if the value is nil. A non-empty
+// resourceVersion makes the patch fail with a conflict if the leaderworkerset was changed since it was read.
+func (o *options) patchAnnotation(ctx context.Context, name, resourceVersion, key string, value *string) error {
+	metadata := map[string]any{
+		// A null value removes the annotation in a merge patch.
+		"annotations": map[string]*string{key: value},
+	}
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+	patch, err := json.Marshal(map[string]any{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	_, err = o.lwsClient.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// rolloutRestart sets the restartedAt annotation on the templates, every group is then recreated by a rolling update.
+func (o *options) rolloutRestart(ctx context.Context, name string) error {
+	lws, err := o.getLeaderWorkerSet(ctx, name)
+	if err != nil {
+		return err
+	}
+	restartedAt := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{leaderworkerset.RestartedAtAnnotationKey: o.now().Format(time.RFC3339)},
+		},
+	}
+	template := map[string]any{"workerTemplate": restartedAt}
+	// The leader pods are created from the worker template when there is no leader template.
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		template["leaderTemplate"] = restartedAt
+	}
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"leaderWorkerTemplate": template},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := o.lwsClient.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	o.printf("leaderworkerset %q restarted\n", name)
+	return nil
+}
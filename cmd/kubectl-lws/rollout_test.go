@@ -0,0 +1,262 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+func TestRolloutStatusMessage(t *testing.T) {
+	available := []metav1.Condition{{Type: string(leaderworkerset.LeaderWorkerSetAvailable), Status: metav1.ConditionTrue}}
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		generation  int64
+		status      leaderworkerset.LeaderWorkerSetStatus
+		wantMessage string
+		wantDone    bool
+	}{
+		{
+			name:        "spec update not observed yet",
+			generation:  2,
+			status:      leaderworkerset.LeaderWorkerSetStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, Conditions: available},
+			wantMessage: "Waiting for rollout to finish: observed leaderworkerset generation less than desired generation\n",
+		},
+		{
+			name:        "groups being updated",
+			status:      leaderworkerset.LeaderWorkerSetStatus{Replicas: 2, UpdatedReplicas: 1},
+			wantMessage: "Waiting for rollout to finish: 1 out of 2 new groups have been updated...\n",
+		},
+		{
+			name:        "paused rollout",
+			annotations: map[string]string{leaderworkerset.RolloutPausedAnnotationKey: "true"},
+			status:      leaderworkerset.LeaderWorkerSetStatus{Replicas: 2, UpdatedReplicas: 1},
+			wantMessage: "Rollout is paused: 1 out of 2 groups have been updated, resume it with \"kubectl lws rollout resume test-sample\"\n",
+		},
+		{
+			name:        "surged groups pending termination",
+			status:      leaderworkerset.LeaderWorkerSetStatus{Replicas: 3, UpdatedReplicas: 2},
+			wantMessage: "Waiting for rollout to finish: 1 old groups are pending termination...\n",
+		},
+		{
+			name:        "updated groups not ready",
+			status:      leaderworkerset.LeaderWorkerSetStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 1},
+			wantMessage: "Waiting for rollout to finish: 1 of 2 updated groups are ready...\n",
+		},
+		{
+			name:        "rolled out",
+			status:      leaderworkerset.LeaderWorkerSetStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, Conditions: available},
+			wantMessage: "leaderworkerset \"test-sample\" successfully rolled out\n",
+			wantDone:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Annotation(tc.annotations).Obj()
+			lws.Generation = tc.generation
+			lws.Status = tc.status
+			message, done := rolloutStatusMessage(lws)
+			if diff := cmp.Diff(tc.wantMessage, message); diff != "" {
+				t.Errorf("unexpected message (-want +got):\n%s", diff)
+			}
+			if done != tc.wantDone {
+				t.Errorf("unexpected done, want %t, got %t", tc.wantDone, done)
+			}
+		})
+	}
+}
+
+func TestSetRolloutPaused(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	o, out := newTestOptions([]runtime.Object{lws}, nil)
+
+	steps := []struct {
+		paused     bool
+		wantOutput string
+		wantPaused bool
+	}{
+		{paused: true, wantOutput: "leaderworkerset \"test-sample\" paused\n", wantPaused: true},
+		{paused: true, wantOutput: "leaderworkerset \"test-sample\" is already paused\n", wantPaused: true},
+		{paused: false, wantOutput: "leaderworkerset \"test-sample\" resumed\n"},
+		{paused: false, wantOutput: "leaderworkerset \"test-sample\" is already resumed\n"},
+	}
+	for _, step := range steps {
+		out.Reset()
+		if err := o.setRolloutPaused(context.TODO(), lws.Name, step.paused); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(step.wantOutput, out.String()); diff != "" {
+			t.Errorf("unexpected output (-want +got):\n%s", diff)
+		}
+		got, err := o.getLeaderWorkerSet(context.TODO(), lws.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rolloutPaused(got) != step.wantPaused {
+			t.Errorf("unexpected paused annotation, want %t, got %v", step.wantPaused, got.Annotations)
+		}
+	}
+}
+
+func TestRolloutUndo(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	lws.UID = "lws-uid"
+	previousRevision, err := revisionutils.NewRevision(context.TODO(), client, lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginx:updated"
+	updateRevision, err := revisionutils.NewRevision(context.TODO(), client, lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateRevision.Revision = 2
+	lws.Status.CurrentRevision = revisionutils.GetRevisionKey(previousRevision)
+	lws.Status.UpdateRevision = revisionutils.GetRevisionKey(updateRevision)
+	// A revision of another leaderworkerset with the same name must be ignored.
+	orphanRevision := previousRevision.DeepCopy()
+	orphanRevision.Name = "orphan"
+	orphanRevision.Revision = 3
+	orphanRevision.OwnerReferences[0].UID = "other-uid"
+
+	testCases := []struct {
+		name       string
+		revisions  []*appsv1.ControllerRevision
+		toRevision int64
+		wantImage  string
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:       "roll back to the previous revision",
+			revisions:  []*appsv1.ControllerRevision{previousRevision, updateRevision, orphanRevision},
+			wantImage:  wrappers.MakeWorkerPodSpec().Containers[0].Image,
+			wantOutput: "leaderworkerset \"test-sample\" rolled back to revision 1\n",
+		},
+		{
+			name:       "roll back to the update revision",
+			revisions:  []*appsv1.ControllerRevision{previousRevision, updateRevision},
+			toRevision: 2,
+			wantImage:  "nginx:updated",
+			wantOutput: "leaderworkerset \"test-sample\" skipped rollback, revision 2 is the current template\n",
+		},
+		{
+			name:       "unknown revision",
+			revisions:  []*appsv1.ControllerRevision{previousRevision, updateRevision},
+			toRevision: 3,
+			wantImage:  "nginx:updated",
+			wantErr:    "revision 3 of leaderworkerset \"test-sample\" not found",
+		},
+		{
+			name:      "rolling update done",
+			revisions: []*appsv1.ControllerRevision{updateRevision},
+			wantImage: "nginx:updated",
+			wantErr:   "no previous revision found for leaderworkerset \"test-sample\", the revisions are only kept during a rolling update",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var revisions []runtime.Object
+			for _, revision := range tc.revisions {
+				revisions = append(revisions, revision)
+			}
+			o, out := newTestOptions([]runtime.Object{lws.DeepCopy()}, revisions)
+			err := o.rolloutUndo(context.TODO(), lws.Name, tc.toRevision)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("unexpected error (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOutput, out.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+			got, err := o.getLeaderWorkerSet(context.TODO(), lws.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if image := got.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image; image != tc.wantImage {
+				t.Errorf("unexpected worker image, want %s, got %s", tc.wantImage, image)
+			}
+		})
+	}
+}
+
+func TestRolloutRestart(t *testing.T) {
+	restartedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name                  string
+		withoutLeaderTemplate bool
+		wantLeaderAnnotations map[string]string
+	}{
+		{
+			name:                  "leader and worker templates",
+			wantLeaderAnnotations: map[string]string{leaderworkerset.RestartedAtAnnotationKey: "2025-03-01T10:00:00Z"},
+		},
+		{
+			name:                  "worker template only",
+			withoutLeaderTemplate: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+			if tc.withoutLeaderTemplate {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+			}
+			o, out := newTestOptions([]runtime.Object{lws}, nil)
+			o.now = func() time.Time { return restartedAt }
+			if err := o.rolloutRestart(context.TODO(), lws.Name); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff("leaderworkerset \"test-sample\" restarted\n", out.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+			got, err := o.getLeaderWorkerSet(context.TODO(), lws.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantWorkerAnnotations := map[string]string{leaderworkerset.RestartedAtAnnotationKey: "2025-03-01T10:00:00Z"}
+			if diff := cmp.Diff(wantWorkerAnnotations, got.Spec.LeaderWorkerTemplate.WorkerTemplate.Annotations); diff != "" {
+				t.Errorf("unexpected worker template annotations (-want +got):\n%s", diff)
+			}
+			if tc.withoutLeaderTemplate {
+				if got.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+					t.Errorf("unexpected leader template %v", got.Spec.LeaderWorkerTemplate.LeaderTemplate)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.wantLeaderAnnotations, got.Spec.LeaderWorkerTemplate.LeaderTemplate.Annotations); diff != "" {
+				t.Errorf("unexpected leader template annotations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,15 +17,25 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	uzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -34,11 +44,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	leaderworkersetv2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
 	"sigs.k8s.io/lws/pkg/cert"
 	"sigs.k8s.io/lws/pkg/config"
 	"sigs.k8s.io/lws/pkg/controllers"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/health"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/tracing"
 	"sigs.k8s.io/lws/pkg/utils"
 	"sigs.k8s.io/lws/pkg/utils/useragent"
 	"sigs.k8s.io/lws/pkg/version"
@@ -56,6 +72,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(leaderworkersetv1.AddToScheme(scheme))
+	utilruntime.Must(leaderworkersetv2.AddToScheme(scheme))
 	utilruntime.Must(configapi.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -75,6 +92,7 @@ func main() {
 		leaderElectResourceLock  string
 		leaderElectionID         string
 		configFile               string
+		featureGates             string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "DEPRECATED(please pass configuration file via --config flag): The address the metric endpoint binds to.")
@@ -103,6 +121,9 @@ func main() {
 		"The controller will load its initial configuration from this file. "+
 			"Command-line flags will override any configurations set in this file. "+
 			"Omit this flag to use the default configuration values.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"A set of key=value pairs that describe feature gates for alpha/experimental features. "+
+			"They override the featureGates of the configuration file. Options are:\n"+strings.Join(features.DefaultMutableFeatureGate.KnownFeatures(), "\n"))
 
 	opts := zap.Options{
 		Development: true,
@@ -114,9 +135,17 @@ func main() {
 		flagsSet[f.Name] = true
 	})
 
+	// The log level is kept atomic, so that the verbosity of the configuration file can be changed at runtime.
+	logLevel := uzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	if opts.Development {
+		logLevel.SetLevel(zapcore.DebugLevel)
+	}
+	if opts.Level == nil {
+		opts.Level = logLevel
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	options, cfg, err := apply(configFile, probeAddr, enableLeaderElection, leaderElectLeaseDuration, leaderElectRenewDeadline, leaderElectRetryPeriod, leaderElectResourceLock, leaderElectionID, metricsAddr)
+	options, cfg, err := apply(configFile, probeAddr, enableLeaderElection, leaderElectLeaseDuration, leaderElectRenewDeadline, leaderElectRetryPeriod, leaderElectResourceLock, leaderElectionID, metricsAddr, featureGates)
 	if err != nil {
 		setupLog.Error(err, "unable to load the configuration")
 		os.Exit(1)
@@ -124,35 +153,83 @@ func main() {
 
 	kubeConfig := ctrl.GetConfigOrDie()
 
-	kubeConfig.QPS = *cfg.ClientConnection.QPS
-	if flagsSet["kube-api-qps"] {
-		kubeConfig.QPS = float32(qps)
-	}
-	kubeConfig.Burst = int(*cfg.ClientConnection.Burst)
-	if flagsSet["kube-api-burst"] {
-		kubeConfig.Burst = burst
-	}
+	// The clients share a rate limiter, so that the changes of the configuration file are applied to all of them.
+	clientQPS, clientBurst := clientConnection(&cfg, qps, burst)
+	clientRateLimiter := config.NewClientRateLimiter(clientQPS, clientBurst)
+	kubeConfig.QPS = clientQPS
+	kubeConfig.Burst = clientBurst
+	kubeConfig.RateLimiter = clientRateLimiter
+	setLogVerbosity(&cfg, logLevel)
 	if kubeConfig.UserAgent == "" {
 		kubeConfig.UserAgent = useragent.Default()
 	}
 	setupLog.Info("Initializing", "gitVersion", version.GitVersion, "gitCommit", version.GitCommit, "userAgent", kubeConfig.UserAgent)
 
+	// Without the webhooks, the controllers do the pod mutations themselves and no certs are needed.
+	webhooksEnabled := os.Getenv("ENABLE_WEBHOOKS") != "false"
+	certsReady := make(chan struct{})
+	var externalCerts *cert.ExternalCerts
+	if webhooksEnabled && cfg.ExternalCertManagement != nil {
+		externalCerts = cert.NewExternalCerts(options.LeaderElectionNamespace, cfg.ExternalCertManagement, certsReady)
+		useExternalCerts(&options, externalCerts, *cfg.ExternalCertManagement.ServeMetrics)
+	}
+
 	mgr, err := ctrl.NewManager(kubeConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	certsReady := make(chan struct{})
-	if cfg.InternalCertManagement != nil && *cfg.InternalCertManagement.Enable {
+	switch {
+	case webhooksEnabled && cfg.InternalCertManagement != nil && *cfg.InternalCertManagement.Enable:
 		if err = cert.CertsManager(mgr, options.LeaderElectionNamespace, *cfg.InternalCertManagement.WebhookServiceName, *cfg.InternalCertManagement.WebhookSecretName, cfg.Webhook.CertDir, certsReady); err != nil {
 			setupLog.Error(err, "unable to setup cert rotation")
 			os.Exit(1)
 		}
-	} else {
+	case externalCerts != nil:
+		if err = externalCerts.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup the external certs")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("external-certs", externalCerts.Checker); err != nil {
+			setupLog.Error(err, "unable to set up the certs ready check")
+			os.Exit(1)
+		}
+	default:
 		close(certsReady)
 	}
 
+	// The defaults of the leaderworkersets are swapped by the reloads of the configuration.
+	lwsDefaults := &atomic.Pointer[configapi.LeaderWorkerSetDefaults]{}
+	lwsDefaults.Store(cfg.LeaderWorkerSetDefaults)
+	if configFile != "" {
+		reloader := config.NewReloader(scheme, configFile, cfg, func(cfg *configapi.Configuration) {
+			clientRateLimiter.Update(clientConnection(cfg, qps, burst))
+			setLogVerbosity(cfg, logLevel)
+			lwsDefaults.Store(cfg.LeaderWorkerSetDefaults)
+		})
+		reloader.Recorder = mgr.GetEventRecorderFor("lws-config")
+		if podName := os.Getenv("POD_NAME"); podName != "" {
+			reloader.EventObject = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: options.LeaderElectionNamespace}}
+		}
+		if err := mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to setup the configuration reload")
+			os.Exit(1)
+		}
+	}
+
+	metrics.Register(*cfg.Metrics.MaxTrackedLeaderWorkerSets)
+
+	var tracerProvider *sdktrace.TracerProvider
+	if cfg.Tracing != nil {
+		tracerProvider, err = tracing.NewProvider(context.Background(), cfg.Tracing)
+		if err != nil {
+			setupLog.Error(err, "unable to setup tracing")
+			os.Exit(1)
+		}
+		otel.SetTracerProvider(tracerProvider)
+	}
+
 	if err := controllers.SetupIndexes(mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "unable to setup indexes")
 	}
@@ -160,40 +237,73 @@ func main() {
 	// Cert won't be ready until manager starts, so start a goroutine here which
 	// will block until the cert is ready before setting up the controllers.
 	// Controllers who register after manager starts will start directly.
-	go setupControllers(mgr, certsReady)
+	stallDetector := health.NewStallDetector(cfg.Health.StalledReconcileTimeout.Duration)
+	go setupControllers(mgr, &cfg, lwsDefaults, stallDetector, certsReady, webhooksEnabled)
 
-	setupHealthzAndReadyzCheck(mgr)
+	setupHealthzAndReadyzCheck(mgr, stallDetector, certsReady, webhooksEnabled)
 	setupLog.Info("starting manager")
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	if tracerProvider != nil {
+		// Flush the spans which weren't exported yet.
+		if err := tracerProvider.Shutdown(context.Background()); err != nil {
+			setupLog.Error(err, "unable to shutdown tracing")
+		}
+	}
 
 }
-func setupControllers(mgr ctrl.Manager, certsReady chan struct{}) {
+func setupControllers(mgr ctrl.Manager, cfg *configapi.Configuration, lwsDefaults *atomic.Pointer[configapi.LeaderWorkerSetDefaults], stallDetector *health.StallDetector, certsReady chan struct{}, webhooksEnabled bool) {
 	// The controllers won't work until the webhooks are operating,
 	// and the webhook won't work until the certs are all in places.
 	setupLog.Info("waiting for the cert generation to complete")
 	<-certsReady
 	setupLog.Info("certs ready")
 
-	if err := controllers.NewLeaderWorkerSetReconciler(
+	var namespaceSelector labels.Selector
+	if cfg.Scope != nil && cfg.Scope.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cfg.Scope.NamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the namespace selector")
+			os.Exit(1)
+		}
+		namespaceSelector = selector
+	}
+
+	lwsController := controllers.NewLeaderWorkerSetReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		mgr.GetEventRecorderFor("leaderworkerset"),
-	).SetupWithManager(mgr); err != nil {
+	)
+	lwsController.WebhooksDisabled = !webhooksEnabled
+	lwsController.NamespaceSelector = namespaceSelector
+	lwsController.ControllerOptions = config.ControllerOptions(cfg.Controllers.LeaderWorkerSet)
+	lwsController.ControllerOptions.NewQueue = stallDetector.NewQueue
+	if err := lwsController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LeaderWorkerSet")
 		os.Exit(1)
 	}
 	// Set up pod reconciler.
 	podController := controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("leaderworkerset"))
+	podController.WebhooksDisabled = !webhooksEnabled
+	podController.NodeNotReadyGracePeriod = cfg.GroupRecreation.NodeNotReadyGracePeriod.Duration
+	podController.DisruptionPolicies = cfg.GroupRecreation.DisruptionPolicies
+	podController.NamespaceSelector = namespaceSelector
+	podController.ControllerOptions = config.ControllerOptions(cfg.Controllers.Pod)
+	podController.ControllerOptions.NewQueue = stallDetector.NewQueue
 	if err := podController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
 	}
-	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhooks.SetupLeaderWorkerSetWebhook(mgr); err != nil {
+	// Without the webhooks, the v2 LeaderWorkerSets can't be converted and only v1 can be used.
+	if webhooksEnabled {
+		if err := webhooks.SetupConversionWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "LeaderWorkerSet")
+			os.Exit(1)
+		}
+		if err := webhooks.SetupLeaderWorkerSetWebhook(mgr, lwsDefaults); err != nil {
 			setupLog.Error(err, "unable to create leaderworkerset webhook", "webhook", "LeaderWorkerSet")
 			os.Exit(1)
 		}
@@ -205,16 +315,67 @@ func setupControllers(mgr ctrl.Manager, certsReady chan struct{}) {
 	//+kubebuilder:scaffold:builder
 }
 
-func setupHealthzAndReadyzCheck(mgr ctrl.Manager) {
+// useExternalCerts serves the external certificates from the webhook server, and from the metrics server if serveMetrics is set.
+func useExternalCerts(options *ctrl.Options, certs *cert.ExternalCerts, serveMetrics bool) {
+	if server, ok := options.WebhookServer.(*webhook.DefaultServer); ok {
+		server.Options.TLSOpts = append(server.Options.TLSOpts, certs.TLSOpt)
+	}
+	if serveMetrics {
+		options.Metrics.TLSOpts = append(options.Metrics.TLSOpts, certs.TLSOpt)
+	}
+}
+
+// clientConnection returns the QPS and the burst of the clients, the flags override the configuration.
+func clientConnection(cfg *configapi.Configuration, qps float64, burst int) (float32, int) {
+	clientQPS, clientBurst := *cfg.ClientConnection.QPS, int(*cfg.ClientConnection.Burst)
+	if flagsSet["kube-api-qps"] {
+		clientQPS = float32(qps)
+	}
+	if flagsSet["kube-api-burst"] {
+		clientBurst = burst
+	}
+	return clientQPS, clientBurst
+}
+
+// setLogVerbosity applies the verbosity of the configuration to the log level, unless the --zap-log-level flag is set.
+func setLogVerbosity(cfg *configapi.Configuration, logLevel uzap.AtomicLevel) {
+	if flagsSet["zap-log-level"] || cfg.Logging == nil || cfg.Logging.Verbosity == nil {
+		return
+	}
+	logLevel.SetLevel(zapcore.Level(-*cfg.Logging.Verbosity))
+}
+
+// setupHealthzAndReadyzCheck sets up the liveness check of the stalled controllers, and the readiness
+// checks of the certificates, of the informers and of the webhook server.
+func setupHealthzAndReadyzCheck(mgr ctrl.Manager, stallDetector *health.StallDetector, certsReady chan struct{}, webhooksEnabled bool) {
 	defer setupLog.Info("both healthz and readyz check are finished and configured")
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err := mgr.AddHealthzCheck("controllers", stallDetector.Checker); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("cache", health.CacheSyncedChecker(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if !webhooksEnabled {
+		return
+	}
+	if err := mgr.AddReadyzCheck("certs", health.CertsReadyChecker(certsReady)); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("webhook", health.WebhookServerChecker(mgr.GetWebhookServer, certsReady)); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
 }
 
 func apply(configFile string,
@@ -225,7 +386,8 @@ func apply(configFile string,
 	leaderElectRetryPeriod time.Duration,
 	leaderElectResourceLock,
 	leaderElectionID string,
-	metricsAddr string) (ctrl.Options, configapi.Configuration, error) {
+	metricsAddr string,
+	featureGates string) (ctrl.Options, configapi.Configuration, error) {
 	namespace := utils.GetOperatorNamespace()
 
 	options, cfg, err := config.Load(scheme, configFile)
@@ -237,6 +399,15 @@ func apply(configFile string,
 		return options, cfg, err
 	}
 
+	if err := features.DefaultMutableFeatureGate.SetFromMap(cfg.FeatureGates); err != nil {
+		return options, cfg, err
+	}
+	if flagsSet["feature-gates"] {
+		if err := features.DefaultMutableFeatureGate.Set(featureGates); err != nil {
+			return options, cfg, err
+		}
+	}
+
 	if flagsSet["health-probe-bind-address"] {
 		options.HealthProbeBindAddress = probeAddr
 	}
@@ -29,6 +29,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"sigs.k8s.io/lws/pkg/features"
 )
 
 func TestApply(t *testing.T) {
@@ -61,6 +63,7 @@ internalCertManagement:
 		cmpopts.IgnoreUnexported(ctrl.Options{}),
 		cmpopts.IgnoreUnexported(webhook.DefaultServer{}),
 		cmpopts.IgnoreUnexported(ctrlcache.Options{}),
+		cmpopts.IgnoreFields(ctrlcache.Options{}, "ByObject", "DefaultTransform"),
 		cmpopts.IgnoreUnexported(net.ListenConfig{}),
 		cmpopts.IgnoreFields(ctrl.Options{}, "Scheme", "Logger", "Metrics", "WebhookServer"),
 	}
@@ -174,7 +177,8 @@ internalCertManagement:
 				tc.leaderElectRetryPeriod,
 				tc.leaderElectResourceLock,
 				tc.leaderElectionID,
-				"metrics-addr")
+				"metrics-addr",
+				"")
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -184,3 +188,59 @@ internalCertManagement:
 		})
 	}
 }
+
+func TestApplyFeatureGates(t *testing.T) {
+	testConfig := filepath.Join(t.TempDir(), "test_config.yaml")
+	if err := os.WriteFile(testConfig, []byte(`
+apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+featureGates:
+  NodeFailureGroupRecreation: false
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		featureGates string
+		flagtrack    map[string]bool
+		wantEnabled  bool
+		wantErr      bool
+	}{
+		{
+			name:        "configuration",
+			flagtrack:   map[string]bool{},
+			wantEnabled: false,
+		},
+		{
+			name:         "flag overrides the configuration",
+			featureGates: "NodeFailureGroupRecreation=true",
+			flagtrack:    map[string]bool{"feature-gates": true},
+			wantEnabled:  true,
+		},
+		{
+			name:         "unknown feature",
+			featureGates: "UnknownFeature=true",
+			flagtrack:    map[string]bool{"feature-gates": true},
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Restores the gate once the test is done.
+			features.SetFeatureGateDuringTest(t, features.NodeFailureGroupRecreation, true)
+			flagsSet = tc.flagtrack
+			_, _, err := apply(testConfig, "", false, 0, 0, 0, "", "", "metrics-addr", tc.featureGates)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := features.Enabled(features.NodeFailureGroupRecreation); got != tc.wantEnabled {
+				t.Errorf("unexpected NodeFailureGroupRecreation feature gate, want %t, got %t", tc.wantEnabled, got)
+			}
+		})
+	}
+}
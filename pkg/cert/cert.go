@@ -24,6 +24,7 @@ import (
 const (
 	validateWebhookConfName = "lws-validating-webhook-configuration"
 	mutatingWebhookConfName = "lws-mutating-webhook-configuration"
+	conversionCRDName       = "leaderworkersets.leaderworkerset.x-k8s.io"
 	caName                  = "lws-ca"
 	caOrg                   = "lws"
 )
@@ -31,6 +32,7 @@ const (
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=mutatingwebhookconfigurations,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=validatingwebhookconfigurations,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch;update
 
 // CertsManager creates certs for webhooks.
 func CertsManager(mgr ctrl.Manager, namespace string, configServiceName string, configSecretName string, webhookCertDir string, setupFinish chan struct{}) error {
@@ -56,6 +58,10 @@ func CertsManager(mgr ctrl.Manager, namespace string, configServiceName string,
 				Type: cert.Mutating,
 				Name: mutatingWebhookConfName,
 			},
+			{
+				Type: cert.CRDConversion,
+				Name: conversionCRDName,
+			},
 		},
 	})
 }
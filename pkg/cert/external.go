@@ -0,0 +1,275 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	"sigs.k8s.io/lws/pkg/metrics"
+)
+
+const (
+	// CertificateInvalid means the Secret doesn't hold a valid certificate, the previous one is still served.
+	CertificateInvalid = "CertificateInvalid"
+	// CertificateExpiring means the served certificate expires within the expiry warning threshold.
+	CertificateExpiring = "CertificateExpiring"
+
+	// refreshInterval is the period of the reads of the Secret.
+	refreshInterval = time.Minute
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+//+kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get
+
+// ExternalCerts serves the webhook certificates of a Secret, or of the Secret of a cert-manager Certificate,
+// and serves the rotated certificates as soon as they are read.
+type ExternalCerts struct {
+	namespace   string
+	cfg         configapi.ExternalCertManagement
+	setupFinish chan struct{}
+	finishOnce  sync.Once
+
+	reader   client.Reader
+	writer   client.Writer
+	recorder record.EventRecorder
+
+	cert atomic.Pointer[tls.Certificate]
+	// resourceVersion is the resource version of the last loaded Secret.
+	resourceVersion string
+	// expiryWarned is the resource version of the Secret whose expiry was warned about.
+	expiryWarned string
+}
+
+// NewExternalCerts returns the certificates configured by cfg in the namespace of the controller,
+// setupFinish is closed once the first valid certificate is read. It's created before the manager,
+// so that its TLSOpt can be set in the options of the manager, and needs SetupWithManager to be called.
+func NewExternalCerts(namespace string, cfg *configapi.ExternalCertManagement, setupFinish chan struct{}) *ExternalCerts {
+	return &ExternalCerts{namespace: namespace, cfg: *cfg, setupFinish: setupFinish}
+}
+
+// SetupWithManager reads the certificates with the clients of the manager once it's started.
+// The Secret is read from the API server, rather than from the cache, to not cache all the Secrets.
+func (e *ExternalCerts) SetupWithManager(mgr ctrl.Manager) error {
+	e.reader = mgr.GetAPIReader()
+	e.writer = mgr.GetClient()
+	e.recorder = mgr.GetEventRecorderFor("lws-cert")
+	return mgr.Add(e)
+}
+
+// Start reads the Secret periodically until the context is done.
+func (e *ExternalCerts) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("external-certs")
+	wait.UntilWithContext(ctrl.LoggerInto(ctx, log), e.refresh, refreshInterval)
+	return nil
+}
+
+// NeedLeaderElection returns false, all the replicas of the controller serve the webhooks.
+func (e *ExternalCerts) NeedLeaderElection() bool {
+	return false
+}
+
+// TLSOpt configures a TLS server to serve the certificate.
+func (e *ExternalCerts) TLSOpt(c *tls.Config) {
+	c.GetCertificate = e.GetCertificate
+}
+
+// GetCertificate returns the served certificate.
+func (e *ExternalCerts) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := e.cert.Load()
+	if cert == nil {
+		return nil, errors.New("the webhook certificate wasn't read yet")
+	}
+	return cert, nil
+}
+
+// Checker is a readiness check failing until a certificate is read and once it expires.
+func (e *ExternalCerts) Checker(*http.Request) error {
+	cert := e.cert.Load()
+	if cert == nil {
+		return errors.New("the webhook certificate wasn't read yet")
+	}
+	if time.Now().After(cert.Leaf.NotAfter) {
+		return fmt.Errorf("the webhook certificate expired at %s", cert.Leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// refresh reads the Secret, serves its certificate if it changed and is valid, and warns about
+// the expiry of the served certificate once per version of the Secret.
+func (e *ExternalCerts) refresh(ctx context.Context) {
+	log := ctrl.LoggerFrom(ctx)
+	secretName, err := e.secretName(ctx)
+	if err != nil {
+		log.Error(err, "Getting the name of the webhook certificate Secret")
+		return
+	}
+	var secret corev1.Secret
+	if err := e.reader.Get(ctx, types.NamespacedName{Namespace: e.namespace, Name: secretName}, &secret); err != nil {
+		log.Error(err, "Getting the webhook certificate Secret", "secret", secretName)
+		return
+	}
+	if secret.ResourceVersion != e.resourceVersion {
+		e.resourceVersion = secret.ResourceVersion
+		e.load(ctx, &secret)
+	}
+
+	cert := e.cert.Load()
+	if cert == nil || e.expiryWarned == secret.ResourceVersion {
+		return
+	}
+	if remaining := time.Until(cert.Leaf.NotAfter); remaining < e.cfg.ExpiryWarningThreshold.Duration {
+		e.expiryWarned = secret.ResourceVersion
+		e.recorder.Eventf(&secret, corev1.EventTypeWarning, CertificateExpiring, "The webhook certificate expires at %s", cert.Leaf.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// load serves the certificate of the Secret if it is valid, the previous certificate is kept otherwise.
+func (e *ExternalCerts) load(ctx context.Context, secret *corev1.Secret) {
+	log := ctrl.LoggerFrom(ctx)
+	cert, err := parseCertificate(secret)
+	if err != nil {
+		log.Error(err, "Rejected the invalid webhook certificate", "secret", secret.Name)
+		e.recorder.Eventf(secret, corev1.EventTypeWarning, CertificateInvalid, "The webhook certificate is invalid: %v", err)
+		return
+	}
+	if caBundle := secret.Data["ca.crt"]; len(caBundle) > 0 && *e.cfg.InjectCABundle {
+		if err := e.injectCABundle(ctx, caBundle); err != nil {
+			// The Secret is loaded again on the next refresh.
+			e.resourceVersion = ""
+			log.Error(err, "Injecting the CA bundle of the webhook certificate", "secret", secret.Name)
+			return
+		}
+	}
+	e.cert.Store(cert)
+	e.finishOnce.Do(func() { close(e.setupFinish) })
+	metrics.WebhookCertificateLoaded(cert.Leaf.NotAfter)
+	log.Info("Serving the webhook certificate", "secret", secret.Name, "notAfter", cert.Leaf.NotAfter)
+}
+
+// secretName returns the name of the configured Secret, or of the Secret of the configured Certificate.
+func (e *ExternalCerts) secretName(ctx context.Context) (string, error) {
+	if e.cfg.CertificateName == "" {
+		return e.cfg.SecretName, nil
+	}
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if err := e.reader.Get(ctx, types.NamespacedName{Namespace: e.namespace, Name: e.cfg.CertificateName}, certificate); err != nil {
+		return "", err
+	}
+	secretName, found, err := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	if err != nil {
+		return "", err
+	}
+	if !found || secretName == "" {
+		return "", fmt.Errorf("the Certificate %s has no spec.secretName", e.cfg.CertificateName)
+	}
+	return secretName, nil
+}
+
+// parseCertificate returns the certificate of the Secret, which must not be expired.
+func parseCertificate(secret *corev1.Secret) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, err
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, err
+		}
+	}
+	if time.Now().After(cert.Leaf.NotAfter) {
+		return nil, fmt.Errorf("the certificate expired at %s", cert.Leaf.NotAfter.Format(time.RFC3339))
+	}
+	return &cert, nil
+}
+
+// injectCABundle sets the CA bundle of the webhook configurations and of the conversion webhook of the CRD.
+func (e *ExternalCerts) injectCABundle(ctx context.Context, caBundle []byte) error {
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := e.reader.Get(ctx, types.NamespacedName{Name: validateWebhookConfName}, validating); err != nil {
+		return err
+	}
+	changed := false
+	for i := range validating.Webhooks {
+		if !bytes.Equal(validating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			validating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if changed {
+		if err := e.writer.Update(ctx, validating); err != nil {
+			return err
+		}
+	}
+
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := e.reader.Get(ctx, types.NamespacedName{Name: mutatingWebhookConfName}, mutating); err != nil {
+		return err
+	}
+	changed = false
+	for i := range mutating.Webhooks {
+		if !bytes.Equal(mutating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			mutating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if changed {
+		if err := e.writer.Update(ctx, mutating); err != nil {
+			return err
+		}
+	}
+
+	// The CRD is read as unstructured, the apiextensions types aren't in the scheme of the controller.
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	if err := e.reader.Get(ctx, types.NamespacedName{Name: conversionCRDName}, crd); err != nil {
+		return err
+	}
+	if strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+		return nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(caBundle)
+	if current, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle"); current == encoded {
+		return nil
+	}
+	if err := unstructured.SetNestedField(crd.Object, encoded, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+		return err
+	}
+	return e.writer.Update(ctx, crd)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+func TestExternalCertsRefresh(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	scheme.AddKnownTypeWithName(certificateGVK, &unstructured.Unstructured{})
+
+	validCert, validKey := makeCertificate(t, time.Now().Add(365*24*time.Hour))
+	expiringCert, expiringKey := makeCertificate(t, time.Now().Add(24*time.Hour))
+	expiredCert, expiredKey := makeCertificate(t, time.Now().Add(-time.Hour))
+	caBundle := []byte("ca")
+
+	secret := func(name string, cert, key []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "lws-system"},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       cert,
+				corev1.TLSPrivateKeyKey: key,
+				"ca.crt":                caBundle,
+			},
+		}
+	}
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName("lws-serving-cert")
+	certificate.SetNamespace("lws-system")
+	if err := unstructured.SetNestedField(certificate.Object, "lws-webhook-server-cert", "spec", "secretName"); err != nil {
+		t.Fatal(err)
+	}
+	webhookConfigurations := []client.Object{
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: validateWebhookConfName},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vleaderworkerset.kb.io"}},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: mutatingWebhookConfName},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mleaderworkerset.kb.io"}},
+		},
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: conversionCRDName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Conversion: &apiextensionsv1.CustomResourceConversion{
+					Strategy: apiextensionsv1.WebhookConverter,
+					Webhook:  &apiextensionsv1.WebhookConversion{ClientConfig: &apiextensionsv1.WebhookClientConfig{}},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		cfg          configapi.ExternalCertManagement
+		objects      []client.Object
+		wantReady    bool
+		wantEvents   []string
+		wantCABundle []byte
+	}{
+		"secret": {
+			cfg:       configapi.ExternalCertManagement{SecretName: "lws-webhook-server-cert"},
+			objects:   []client.Object{secret("lws-webhook-server-cert", validCert, validKey)},
+			wantReady: true,
+		},
+		"secret of a certificate": {
+			cfg:       configapi.ExternalCertManagement{CertificateName: "lws-serving-cert"},
+			objects:   []client.Object{certificate, secret("lws-webhook-server-cert", validCert, validKey)},
+			wantReady: true,
+		},
+		"missing secret": {
+			cfg: configapi.ExternalCertManagement{SecretName: "lws-webhook-server-cert"},
+		},
+		"expired certificate": {
+			cfg:        configapi.ExternalCertManagement{SecretName: "lws-webhook-server-cert"},
+			objects:    []client.Object{secret("lws-webhook-server-cert", expiredCert, expiredKey)},
+			wantEvents: []string{"Warning CertificateInvalid"},
+		},
+		"expiring certificate": {
+			cfg:        configapi.ExternalCertManagement{SecretName: "lws-webhook-server-cert"},
+			objects:    []client.Object{secret("lws-webhook-server-cert", expiringCert, expiringKey)},
+			wantReady:  true,
+			wantEvents: []string{"Warning CertificateExpiring"},
+		},
+		"injected CA bundle": {
+			cfg:          configapi.ExternalCertManagement{SecretName: "lws-webhook-server-cert", InjectCABundle: ptr.To(true)},
+			objects:      append([]client.Object{secret("lws-webhook-server-cert", validCert, validKey)}, webhookConfigurations...),
+			wantReady:    true,
+			wantCABundle: caBundle,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := tc.cfg
+			if cfg.InjectCABundle == nil {
+				cfg.InjectCABundle = ptr.To(false)
+			}
+			cfg.ExpiryWarningThreshold = &metav1.Duration{Duration: configapi.DefaultExpiryWarningThreshold}
+			var objects []client.Object
+			for _, obj := range tc.objects {
+				objects = append(objects, obj.DeepCopyObject().(client.Object))
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			setupFinish := make(chan struct{})
+			certs := NewExternalCerts("lws-system", &cfg, setupFinish)
+			certs.reader, certs.writer, certs.recorder = c, c, recorder
+
+			certs.refresh(context.Background())
+			// The unchanged Secret is neither loaded nor warned about again.
+			certs.refresh(context.Background())
+
+			if gotReady := certs.Checker(nil) == nil; gotReady != tc.wantReady {
+				t.Errorf("Unexpected readiness, want %t, got %t", tc.wantReady, gotReady)
+			}
+			select {
+			case <-setupFinish:
+				if !tc.wantReady {
+					t.Error("Unexpected closed setupFinish channel")
+				}
+			default:
+				if tc.wantReady {
+					t.Error("Expected the setupFinish channel to be closed")
+				}
+			}
+			close(recorder.Events)
+			// The messages hold the expiry time of the certificates, only the types and the reasons are compared.
+			var gotEvents []string
+			for event := range recorder.Events {
+				gotEvents = append(gotEvents, strings.Join(strings.SplitN(event, " ", 3)[:2], " "))
+			}
+			if diff := cmp.Diff(tc.wantEvents, gotEvents); diff != "" {
+				t.Errorf("Unexpected events (-want +got):\n%s", diff)
+			}
+			if tc.wantCABundle != nil {
+				validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+				if err := c.Get(context.Background(), client.ObjectKey{Name: validateWebhookConfName}, validating); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.wantCABundle, validating.Webhooks[0].ClientConfig.CABundle); diff != "" {
+					t.Errorf("Unexpected CA bundle of the validating webhook (-want +got):\n%s", diff)
+				}
+				mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+				if err := c.Get(context.Background(), client.ObjectKey{Name: mutatingWebhookConfName}, mutating); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.wantCABundle, mutating.Webhooks[0].ClientConfig.CABundle); diff != "" {
+					t.Errorf("Unexpected CA bundle of the mutating webhook (-want +got):\n%s", diff)
+				}
+				crd := &apiextensionsv1.CustomResourceDefinition{}
+				if err := c.Get(context.Background(), client.ObjectKey{Name: conversionCRDName}, crd); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.wantCABundle, crd.Spec.Conversion.Webhook.ClientConfig.CABundle); diff != "" {
+					t.Errorf("Unexpected CA bundle of the conversion webhook (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestExternalCertsRotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	firstCert, firstKey := makeCertificate(t, time.Now().Add(365*24*time.Hour))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "lws-webhook-server-cert", Namespace: "lws-system"},
+		Data:       map[string][]byte{corev1.TLSCertKey: firstCert, corev1.TLSPrivateKeyKey: firstKey},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	certs := NewExternalCerts("lws-system", &configapi.ExternalCertManagement{
+		SecretName:             "lws-webhook-server-cert",
+		InjectCABundle:         ptr.To(false),
+		ExpiryWarningThreshold: &metav1.Duration{Duration: time.Hour},
+	}, make(chan struct{}))
+	certs.reader, certs.writer, certs.recorder = c, c, record.NewFakeRecorder(10)
+
+	certs.refresh(context.Background())
+	first, err := certs.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondCert, secondKey := makeCertificate(t, time.Now().Add(2*365*24*time.Hour))
+	secret.Data = map[string][]byte{corev1.TLSCertKey: secondCert, corev1.TLSPrivateKeyKey: secondKey}
+	if err := c.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	certs.refresh(context.Background())
+	second, err := certs.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Leaf.NotAfter.After(first.Leaf.NotAfter) {
+		t.Errorf("Expected the rotated certificate to be served, got the certificate expiring at %s", second.Leaf.NotAfter)
+	}
+}
+
+// makeCertificate returns the PEM encoded self-signed certificate and key expiring at notAfter.
+func makeCertificate(t *testing.T, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "lws-webhook-service.lws-system.svc"},
+		DNSNames:     []string{"lws-webhook-service.lws-system.svc"},
+		NotBefore:    notAfter.Add(-2 * 365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
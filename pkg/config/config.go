@@ -21,14 +21,25 @@ import (
 	"fmt"
 	"os"
 
+	"golang.org/x/time/rate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 func fromFile(path string, scheme *runtime.Scheme, cfg *configapi.Configuration) error {
@@ -48,6 +59,8 @@ func fromFile(path string, scheme *runtime.Scheme, cfg *configapi.Configuration)
 // It only sets values in o if they are not already set and are present in cfg.
 func addTo(o *ctrl.Options, cfg *configapi.Configuration) {
 	addLeaderElectionTo(o, cfg)
+	AddCacheFiltersTo(&o.Cache)
+	addScopeTo(o, cfg)
 
 	if o.Metrics.BindAddress == "" && cfg.Metrics.BindAddress != "" {
 		o.Metrics.BindAddress = cfg.Metrics.BindAddress
@@ -80,6 +93,76 @@ func addTo(o *ctrl.Options, cfg *configapi.Configuration) {
 	}
 }
 
+// AddCacheFiltersTo restricts the cache to what the controllers use: the pods and statefulsets
+// labeled with the name of their leaderworkerset, the node conditions without the heavy parts
+// of the node status, and the objects without their managed fields.
+func AddCacheFiltersTo(o *cache.Options) {
+	if o.ByObject == nil {
+		o.ByObject = map[client.Object]cache.ByObject{}
+	}
+	leaderWorkerSetObjects, _ := labels.NewRequirement(leaderworkerset.SetNameLabelKey, selection.Exists, nil)
+	o.ByObject[&corev1.Pod{}] = cache.ByObject{Label: labels.NewSelector().Add(*leaderWorkerSetObjects)}
+	o.ByObject[&appsv1.StatefulSet{}] = cache.ByObject{Label: labels.NewSelector().Add(*leaderWorkerSetObjects)}
+	o.ByObject[&corev1.Node{}] = cache.ByObject{Transform: stripNodeStatus}
+	if o.DefaultTransform == nil {
+		o.DefaultTransform = cache.TransformStripManagedFields()
+	}
+}
+
+// stripNodeStatus drops the images and the volumes of the node status, only the node
+// conditions are used to recreate the groups of failed nodes.
+func stripNodeStatus(obj any) (any, error) {
+	if node, ok := obj.(*corev1.Node); ok {
+		node.ManagedFields = nil
+		node.Status.Images = nil
+		node.Status.VolumesInUse = nil
+		node.Status.VolumesAttached = nil
+	}
+	return obj, nil
+}
+
+// ControllerOptions returns the options of a controller from its configuration: its concurrency and
+// a rate limiter combining the overall token bucket with the per object exponential backoff.
+// The configuration is expected to be defaulted.
+func ControllerOptions(c *configapi.ControllerConfiguration) controller.Options {
+	var o controller.Options
+	if c == nil {
+		return o
+	}
+	if c.MaxConcurrentReconciles != nil {
+		o.MaxConcurrentReconciles = int(*c.MaxConcurrentReconciles)
+	}
+	if c.RateLimiter != nil && c.Backoff != nil {
+		o.RateLimiter = workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](c.Backoff.BaseDelay.Duration, c.Backoff.MaxDelay.Duration),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(*c.RateLimiter.QPS), int(*c.RateLimiter.Burst))},
+		)
+	}
+	return o
+}
+
+// addScopeTo restricts the cache to the namespaces and to the shard of the LeaderWorkerSets managed
+// by the controller. The namespace selector is applied by the controllers, it can't be cached.
+func addScopeTo(o *ctrl.Options, cfg *configapi.Configuration) {
+	if cfg.Scope == nil {
+		return
+	}
+	if o.Cache.DefaultNamespaces == nil && len(cfg.Scope.Namespaces) != 0 {
+		o.Cache.DefaultNamespaces = make(map[string]cache.Config, len(cfg.Scope.Namespaces))
+		for _, namespace := range cfg.Scope.Namespaces {
+			o.Cache.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	if cfg.Scope.Shard != "" {
+		if o.Cache.ByObject == nil {
+			o.Cache.ByObject = map[client.Object]cache.ByObject{}
+		}
+		o.Cache.ByObject[&leaderworkerset.LeaderWorkerSet{}] = cache.ByObject{
+			Label: labels.SelectorFromSet(labels.Set{leaderworkerset.ShardLabelKey: cfg.Scope.Shard}),
+		}
+	}
+}
+
 func addLeaderElectionTo(o *ctrl.Options, cfg *configapi.Configuration) {
 	if cfg.LeaderElection == nil {
 		// The source does not have any configuration; noop
@@ -134,23 +217,26 @@ func Encode(scheme *runtime.Scheme, cfg *configapi.Configuration) (string, error
 // Load returns a set of controller options and configuration from the given file, if the config file path is empty
 // it used the default configapi values.
 func Load(scheme *runtime.Scheme, configFile string) (ctrl.Options, configapi.Configuration, error) {
-	var err error
 	options := ctrl.Options{
 		Scheme: scheme,
 	}
+	cfg, err := load(scheme, configFile)
+	if err != nil {
+		return options, cfg, err
+	}
+	addTo(&options, &cfg)
+	return options, cfg, nil
+}
 
+// load reads, defaults and validates the configuration.
+func load(scheme *runtime.Scheme, configFile string) (configapi.Configuration, error) {
 	cfg := configapi.Configuration{}
 	if configFile == "" {
 		scheme.Default(&cfg)
 	} else {
-		err := fromFile(configFile, scheme, &cfg)
-		if err != nil {
-			return options, cfg, err
+		if err := fromFile(configFile, scheme, &cfg); err != nil {
+			return cfg, err
 		}
 	}
-	if err := validate(&cfg).ToAggregate(); err != nil {
-		return options, cfg, err
-	}
-	addTo(&options, &cfg)
-	return options, cfg, err
+	return cfg, validate(&cfg).ToAggregate()
 }
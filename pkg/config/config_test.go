@@ -27,17 +27,24 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 const (
@@ -198,6 +205,8 @@ webhook:
 		cmpopts.IgnoreUnexported(ctrl.Options{}),
 		cmpopts.IgnoreUnexported(webhook.DefaultServer{}),
 		cmpopts.IgnoreUnexported(ctrlcache.Options{}),
+		// The cache filters are checked by TestAddCacheFiltersTo.
+		cmpopts.IgnoreFields(ctrlcache.Options{}, "ByObject", "DefaultTransform"),
 		cmpopts.IgnoreUnexported(net.ListenConfig{}),
 		cmpopts.IgnoreFields(ctrl.Options{}, "Scheme", "Logger"),
 	}
@@ -212,6 +221,24 @@ webhook:
 		QPS:   ptr.To[float32](configapi.DefaultClientConnectionQPS),
 		Burst: ptr.To[int32](configapi.DefaultClientConnectionBurst),
 	}
+	defaultGroupRecreation := &configapi.GroupRecreation{
+		NodeNotReadyGracePeriod: &metav1.Duration{Duration: configapi.DefaultNodeNotReadyGracePeriod},
+	}
+	defaultControllerConfiguration := &configapi.ControllerConfiguration{
+		MaxConcurrentReconciles: ptr.To(configapi.DefaultMaxConcurrentReconciles),
+		RateLimiter: &configapi.RateLimiter{
+			QPS:   ptr.To(configapi.DefaultRateLimiterQPS),
+			Burst: ptr.To(configapi.DefaultRateLimiterBurst),
+		},
+		Backoff: &configapi.Backoff{
+			BaseDelay: &metav1.Duration{Duration: configapi.DefaultBackoffBaseDelay},
+			MaxDelay:  &metav1.Duration{Duration: configapi.DefaultBackoffMaxDelay},
+		},
+	}
+	defaultControllers := &configapi.Controllers{
+		LeaderWorkerSet: defaultControllerConfiguration,
+		Pod:             defaultControllerConfiguration,
+	}
 
 	testcases := []struct {
 		name              string
@@ -226,6 +253,8 @@ webhook:
 			wantConfiguration: configapi.Configuration{
 				InternalCertManagement: enableDefaultInternalCertManagement,
 				ClientConnection:       defaultClientConnection,
+				GroupRecreation:        defaultGroupRecreation,
+				Controllers:            defaultControllers,
 			},
 			wantOptions: ctrl.Options{
 				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
@@ -267,6 +296,8 @@ webhook:
 				},
 				InternalCertManagement: enableDefaultInternalCertManagement,
 				ClientConnection:       defaultClientConnection,
+				GroupRecreation:        defaultGroupRecreation,
+				Controllers:            defaultControllers,
 			},
 			wantOptions: ctrl.Options{
 				HealthProbeBindAddress: ":38081",
@@ -303,6 +334,8 @@ webhook:
 					WebhookSecretName:  ptr.To("lws-tenant-a-webhook-server-cert"),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -318,6 +351,8 @@ webhook:
 					Enable: ptr.To(false),
 				},
 				ClientConnection: defaultClientConnection,
+				GroupRecreation:  defaultGroupRecreation,
+				Controllers:      defaultControllers,
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -331,6 +366,8 @@ webhook:
 				},
 				InternalCertManagement: enableDefaultInternalCertManagement,
 				ClientConnection:       defaultClientConnection,
+				GroupRecreation:        defaultGroupRecreation,
+				Controllers:            defaultControllers,
 			},
 			wantOptions: ctrl.Options{
 				HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress,
@@ -366,6 +403,8 @@ webhook:
 					QPS:   ptr.To[float32](50),
 					Burst: ptr.To[int32](100),
 				},
+				GroupRecreation: defaultGroupRecreation,
+				Controllers:     defaultControllers,
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -409,6 +448,17 @@ func TestEncode(t *testing.T) {
 
 	defaultConfig := &configapi.Configuration{}
 	testScheme.Default(defaultConfig)
+	defaultControllerConfiguration := map[string]any{
+		"maxConcurrentReconciles": int64(configapi.DefaultMaxConcurrentReconciles),
+		"rateLimiter": map[string]any{
+			"qps":   int64(configapi.DefaultRateLimiterQPS),
+			"burst": int64(configapi.DefaultRateLimiterBurst),
+		},
+		"backoff": map[string]any{
+			"baseDelay": configapi.DefaultBackoffBaseDelay.String(),
+			"maxDelay":  configapi.DefaultBackoffMaxDelay.String(),
+		},
+	}
 
 	testcases := []struct {
 		name       string
@@ -441,12 +491,14 @@ func TestEncode(t *testing.T) {
 					"certDir": configapi.DefaultWebhookCertDir,
 				},
 				"metrics": map[string]any{
-					"bindAddress": configapi.DefaultMetricsBindAddress,
+					"bindAddress":                configapi.DefaultMetricsBindAddress,
+					"maxTrackedLeaderWorkerSets": int64(configapi.DefaultMaxTrackedLeaderWorkerSets),
 				},
 				"health": map[string]any{
-					"healthProbeBindAddress": configapi.DefaultHealthProbeBindAddress,
-					"readinessEndpointName":  configapi.DefaultReadinessEndpoint,
-					"livenessEndpointName":   configapi.DefaultLivenessEndpoint,
+					"healthProbeBindAddress":  configapi.DefaultHealthProbeBindAddress,
+					"readinessEndpointName":   configapi.DefaultReadinessEndpoint,
+					"livenessEndpointName":    configapi.DefaultLivenessEndpoint,
+					"stalledReconcileTimeout": configapi.DefaultStalledReconcileTimeout.String(),
 				},
 				"leaderElection": map[string]any{
 					"leaderElect":       true,
@@ -466,6 +518,13 @@ func TestEncode(t *testing.T) {
 					"burst": int64(configapi.DefaultClientConnectionBurst),
 					"qps":   int64(configapi.DefaultClientConnectionQPS),
 				},
+				"groupRecreation": map[string]any{
+					"nodeNotReadyGracePeriod": configapi.DefaultNodeNotReadyGracePeriod.String(),
+				},
+				"controllers": map[string]any{
+					"leaderWorkerSet": defaultControllerConfiguration,
+					"pod":             defaultControllerConfiguration,
+				},
 			},
 		},
 	}
@@ -486,3 +545,145 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestAddScopeTo(t *testing.T) {
+	testcases := []struct {
+		name           string
+		scope          *configapi.ControllerScope
+		wantNamespaces []string
+		wantLWSLabel   string
+	}{
+		{
+			name: "no scope",
+		},
+		{
+			name:           "namespaces",
+			scope:          &configapi.ControllerScope{Namespaces: []string{"team-a", "team-b"}},
+			wantNamespaces: []string{"team-a", "team-b"},
+		},
+		{
+			name:         "namespace selector and shard",
+			scope:        &configapi.ControllerScope{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}, Shard: "shard-a"},
+			wantLWSLabel: "leaderworkerset.sigs.k8s.io/shard=shard-a",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := ctrl.Options{}
+			addScopeTo(&options, &configapi.Configuration{Scope: tc.scope})
+			var gotNamespaces []string
+			for namespace := range options.Cache.DefaultNamespaces {
+				gotNamespaces = append(gotNamespaces, namespace)
+			}
+			if diff := cmp.Diff(tc.wantNamespaces, gotNamespaces, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("Unexpected cached namespaces (-want +got):\n%s", diff)
+			}
+			var gotLWSLabel string
+			for object, byObject := range options.Cache.ByObject {
+				if _, ok := object.(*leaderworkerset.LeaderWorkerSet); ok {
+					gotLWSLabel = byObject.Label.String()
+				}
+			}
+			if gotLWSLabel != tc.wantLWSLabel {
+				t.Errorf("Unexpected LeaderWorkerSet cache label selector, want %q, got %q", tc.wantLWSLabel, gotLWSLabel)
+			}
+		})
+	}
+}
+
+func TestAddCacheFiltersTo(t *testing.T) {
+	options := ctrlcache.Options{}
+	AddCacheFiltersTo(&options)
+
+	gotLabels := map[string]string{}
+	var nodeTransform toolscache.TransformFunc
+	for object, byObject := range options.ByObject {
+		switch object.(type) {
+		case *corev1.Pod:
+			gotLabels["Pod"] = byObject.Label.String()
+		case *appsv1.StatefulSet:
+			gotLabels["StatefulSet"] = byObject.Label.String()
+		case *corev1.Node:
+			nodeTransform = byObject.Transform
+		}
+	}
+	wantLabels := map[string]string{
+		"Pod":         "leaderworkerset.sigs.k8s.io/name",
+		"StatefulSet": "leaderworkerset.sigs.k8s.io/name",
+	}
+	if diff := cmp.Diff(wantLabels, gotLabels); diff != "" {
+		t.Errorf("Unexpected cache label selectors (-want +got):\n%s", diff)
+	}
+	if options.DefaultTransform == nil {
+		t.Error("Expected the managed fields to be stripped from the cached objects")
+	}
+	if nodeTransform == nil {
+		t.Fatal("Expected a transform of the cached nodes")
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "node",
+			Labels:        map[string]string{"topology": "a"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Status: corev1.NodeStatus{
+			Conditions:   []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Images:       []corev1.ContainerImage{{Names: []string{"image"}}},
+			VolumesInUse: []corev1.UniqueVolumeName{"volume"},
+		},
+	}
+	got, err := nodeTransform(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{"topology": "a"},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected cached node (-want +got):\n%s", diff)
+	}
+}
+
+func TestControllerOptions(t *testing.T) {
+	if diff := cmp.Diff(controller.Options{}, ControllerOptions(nil)); diff != "" {
+		t.Errorf("Unexpected options without configuration (-want +got):\n%s", diff)
+	}
+
+	options := ControllerOptions(&configapi.ControllerConfiguration{
+		MaxConcurrentReconciles: ptr.To[int32](10),
+		RateLimiter: &configapi.RateLimiter{
+			QPS:   ptr.To[float32](1000),
+			Burst: ptr.To[int32](100),
+		},
+		Backoff: &configapi.Backoff{
+			BaseDelay: &metav1.Duration{Duration: 100 * time.Millisecond},
+			MaxDelay:  &metav1.Duration{Duration: 300 * time.Millisecond},
+		},
+	})
+	if options.MaxConcurrentReconciles != 10 {
+		t.Errorf("Unexpected max concurrent reconciles, want 10, got %d", options.MaxConcurrentReconciles)
+	}
+	if options.RateLimiter == nil {
+		t.Fatal("Expected a rate limiter")
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "lws"}}
+	var gotDelays []time.Duration
+	for range 4 {
+		gotDelays = append(gotDelays, options.RateLimiter.When(request))
+	}
+	wantDelays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	if diff := cmp.Diff(wantDelays, gotDelays); diff != "" {
+		t.Errorf("Unexpected backoff of the requeues (-want +got):\n%s", diff)
+	}
+	options.RateLimiter.Forget(request)
+	if got := options.RateLimiter.When(request); got != 100*time.Millisecond {
+		t.Errorf("Unexpected delay once the backoff is reset, want 100ms, got %s", got)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+const (
+	// ConfigurationReloaded means the changes of the configuration file were applied.
+	ConfigurationReloaded = "ConfigurationReloaded"
+	// ConfigurationRejected means the changes of the configuration file were not applied,
+	// because the file is invalid or because some of the changes need a restart of the controller.
+	ConfigurationRejected = "ConfigurationRejected"
+)
+
+// reloadableFields are the fields of the configuration which are applied without restarting the controller.
+var reloadableFields = sets.New("clientConnection", "logging", "leaderWorkerSetDefaults")
+
+// Reloader watches the configuration file and applies its changes without restarting the controller.
+// Only the changes of the clientConnection, the logging and the leaderWorkerSetDefaults are applied, a file changing any other
+// field is rejected as a whole until the controller is restarted.
+type Reloader struct {
+	scheme  *runtime.Scheme
+	path    string
+	apply   func(*configapi.Configuration)
+	current configapi.Configuration
+	content []byte
+
+	// Recorder records the events of the reloads on EventObject, no events are recorded if either is nil.
+	Recorder    record.EventRecorder
+	EventObject runtime.Object
+}
+
+// NewReloader returns a reloader of the configuration file at path, loaded into cfg at startup.
+// apply is called with the new configuration once its changes are accepted.
+func NewReloader(scheme *runtime.Scheme, path string, cfg configapi.Configuration, apply func(*configapi.Configuration)) *Reloader {
+	content, _ := os.ReadFile(path)
+	return &Reloader{scheme: scheme, path: path, apply: apply, current: cfg, content: content}
+}
+
+// Start watches the configuration file until the context is done.
+func (r *Reloader) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// The directory is watched rather than the file, the files of the ConfigMap volumes
+	// are replaced by swapping a symlink.
+	if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+		return err
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("config-reloader")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			r.reload(ctrl.LoggerInto(ctx, log))
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error(err, "Watching the configuration file", "path", r.path)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, the configuration is reloaded by all the replicas of the controller.
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// reload loads the configuration file if its content changed, and applies it if its changes can be applied at runtime.
+func (r *Reloader) reload(ctx context.Context) {
+	log := ctrl.LoggerFrom(ctx)
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		// The file is missing while the symlink of the ConfigMap volume is swapped.
+		log.V(2).Info("Reading the configuration file", "path", r.path, "error", err.Error())
+		return
+	}
+	if bytes.Equal(content, r.content) {
+		return
+	}
+	r.content = content
+
+	cfg, err := load(r.scheme, r.path)
+	if err != nil {
+		log.Error(err, "Rejected the invalid configuration file", "path", r.path)
+		r.event(corev1.EventTypeWarning, ConfigurationRejected, fmt.Sprintf("The configuration file is invalid: %v", err))
+		return
+	}
+	current, err := Encode(r.scheme, &r.current)
+	if err != nil {
+		log.Error(err, "Encoding the configuration")
+		return
+	}
+	changed, err := Encode(r.scheme, &cfg)
+	if err != nil {
+		log.Error(err, "Encoding the configuration")
+		return
+	}
+	fields, err := restartFields(current, changed)
+	if err != nil {
+		log.Error(err, "Comparing the configurations")
+		return
+	}
+	if len(fields) > 0 {
+		log.Info("Rejected the configuration changes which need a restart", "fields", fields, "diff", cmp.Diff(current, changed))
+		r.event(corev1.EventTypeWarning, ConfigurationRejected, fmt.Sprintf("The changes of %s need a restart of the controller, the configuration was not reloaded", strings.Join(fields, ", ")))
+		return
+	}
+	if current == changed {
+		return
+	}
+	r.apply(&cfg)
+	r.current = cfg
+	log.Info("Reloaded the configuration", "diff", cmp.Diff(current, changed))
+	r.event(corev1.EventTypeNormal, ConfigurationReloaded, "Applied the changes of the configuration file")
+}
+
+func (r *Reloader) event(eventType, reason, message string) {
+	if r.Recorder != nil && r.EventObject != nil {
+		r.Recorder.Event(r.EventObject, eventType, reason, message)
+	}
+}
+
+// restartFields returns the sorted top level fields which differ between the encoded configurations
+// and can't be changed at runtime.
+func restartFields(current, changed string) ([]string, error) {
+	var currentFields, changedFields map[string]any
+	if err := yaml.Unmarshal([]byte(current), &currentFields); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal([]byte(changed), &changedFields); err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, field := range sets.List(sets.KeySet(currentFields).Union(sets.KeySet(changedFields))) {
+		if !reloadableFields.Has(field) && !reflect.DeepEqual(currentFields[field], changedFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// ClientRateLimiter is a token bucket rate limiter of the clients of the API server whose
+// QPS and burst can be changed at runtime. It's shared by all the clients of the controller.
+type ClientRateLimiter struct {
+	limiter *rate.Limiter
+}
+
+var _ flowcontrol.RateLimiter = &ClientRateLimiter{}
+
+// NewClientRateLimiter returns a rate limiter of qps queries per second with a burst of burst queries.
+func NewClientRateLimiter(qps float32, burst int) *ClientRateLimiter {
+	return &ClientRateLimiter{limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// Update changes the QPS and the burst of the rate limiter.
+func (l *ClientRateLimiter) Update(qps float32, burst int) {
+	l.limiter.SetLimit(rate.Limit(qps))
+	l.limiter.SetBurst(burst)
+}
+
+func (l *ClientRateLimiter) TryAccept() bool {
+	return l.limiter.Allow()
+}
+
+func (l *ClientRateLimiter) Accept() {
+	_ = l.limiter.Wait(context.Background())
+}
+
+func (l *ClientRateLimiter) Stop() {}
+
+func (l *ClientRateLimiter) QPS() float32 {
+	return float32(l.limiter.Limit())
+}
+
+func (l *ClientRateLimiter) Wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
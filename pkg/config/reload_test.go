@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+func TestReload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := configapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	initialConfig := `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+clientConnection:
+  qps: 50
+  burst: 100
+`
+
+	testCases := map[string]struct {
+		config           string
+		wantEvents       []string
+		wantClientConfig *configapi.ClientConnection
+	}{
+		"unchanged configuration": {
+			config: initialConfig,
+		},
+		"reloadable changes": {
+			config: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+clientConnection:
+  qps: 100
+  burst: 200
+logging:
+  verbosity: 3
+leaderWorkerSetDefaults:
+  maxSize: 16
+`,
+			wantClientConfig: &configapi.ClientConnection{
+				QPS:   ptr.To[float32](100),
+				Burst: ptr.To[int32](200),
+			},
+			wantEvents: []string{"Normal ConfigurationReloaded Applied the changes of the configuration file"},
+		},
+		"changes which need a restart": {
+			config: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+clientConnection:
+  qps: 100
+  burst: 200
+metrics:
+  maxTrackedLeaderWorkerSets: 10
+scope:
+  namespaces:
+  - team-a
+`,
+			wantEvents: []string{"Warning ConfigurationRejected The changes of metrics, scope need a restart of the controller, the configuration was not reloaded"},
+		},
+		"invalid configuration": {
+			config: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+logging:
+  verbosity: -1
+`,
+			wantEvents: []string{"Warning ConfigurationRejected The configuration file is invalid: logging.verbosity: Invalid value: -1: must be between 0 and 2147483647, inclusive"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "controller_manager_config.yaml")
+			if err := os.WriteFile(path, []byte(initialConfig), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, cfg, err := Load(scheme, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var applied *configapi.Configuration
+			reloader := NewReloader(scheme, path, cfg, func(cfg *configapi.Configuration) {
+				applied = cfg
+			})
+			recorder := record.NewFakeRecorder(10)
+			reloader.Recorder = recorder
+			reloader.EventObject = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "lws-controller-manager", Namespace: "lws-system"}}
+
+			if err := os.WriteFile(path, []byte(tc.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			reloader.reload(context.Background())
+			// A second event of the same content is ignored.
+			reloader.reload(context.Background())
+
+			var gotClientConfig *configapi.ClientConnection
+			if applied != nil {
+				gotClientConfig = applied.ClientConnection
+				if diff := cmp.Diff(applied, &reloader.current); diff != "" {
+					t.Errorf("Unexpected current configuration (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.wantClientConfig, gotClientConfig); diff != "" {
+				t.Errorf("Unexpected applied client connection (-want +got):\n%s", diff)
+			}
+			close(recorder.Events)
+			var gotEvents []string
+			for event := range recorder.Events {
+				gotEvents = append(gotEvents, event)
+			}
+			if diff := cmp.Diff(tc.wantEvents, gotEvents); diff != "" {
+				t.Errorf("Unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	limiter := NewClientRateLimiter(10, 1)
+	if !limiter.TryAccept() {
+		t.Error("Expected the first query to be accepted")
+	}
+	if limiter.TryAccept() {
+		t.Error("Expected the query past the burst to be throttled")
+	}
+	limiter.Update(1000, 5)
+	if got := limiter.QPS(); got != 1000 {
+		t.Errorf("Unexpected QPS after the update, want 1000, got %v", got)
+	}
+}
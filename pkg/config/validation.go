@@ -17,22 +17,265 @@ limitations under the License.
 package config
 
 import (
+	"math"
 	"strings"
 
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
 	"k8s.io/utils/ptr"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 var (
-	internalCertManagementPath = field.NewPath("internalCertManagement")
+	internalCertManagementPath  = field.NewPath("internalCertManagement")
+	externalCertManagementPath  = field.NewPath("externalCertManagement")
+	metricsPath                 = field.NewPath("metrics")
+	healthPath                  = field.NewPath("health")
+	tracingPath                 = field.NewPath("tracing")
+	groupRecreationPath         = field.NewPath("groupRecreation")
+	scopePath                   = field.NewPath("scope")
+	controllersPath             = field.NewPath("controllers")
+	loggingPath                 = field.NewPath("logging")
+	leaderWorkerSetDefaultsPath = field.NewPath("leaderWorkerSetDefaults")
+
+	disruptionReasons = sets.New(
+		configapi.PreemptionDisruptionReason,
+		configapi.EvictionDisruptionReason,
+		configapi.TaintManagerDeletionDisruptionReason,
+	)
+	disruptionActions = sets.New(
+		configapi.RecreateDisruptionAction,
+		configapi.DelayDisruptionAction,
+		configapi.WaitForCapacityDisruptionAction,
+	)
+	restartPolicies   = sets.New(leaderworkerset.RecreateGroupOnPodRestart, leaderworkerset.NoneRestartPolicy)
+	startupPolicies   = sets.New(leaderworkerset.LeaderCreatedStartupPolicy, leaderworkerset.LeaderReadyStartupPolicy)
+	subdomainPolicies = sets.New(leaderworkerset.SubdomainShared, leaderworkerset.SubdomainUniquePerReplica)
 )
 
 func validate(c *configapi.Configuration) field.ErrorList {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateInternalCertManagement(c)...)
+	allErrs = append(allErrs, validateExternalCertManagement(c)...)
+	allErrs = append(allErrs, validateMetrics(c)...)
+	allErrs = append(allErrs, validateHealth(c)...)
+	allErrs = append(allErrs, tracingapi.ValidateTracingConfiguration(c.Tracing, nil, tracingPath)...)
+	allErrs = append(allErrs, validateGroupRecreation(c)...)
+	allErrs = append(allErrs, validateScope(c)...)
+	allErrs = append(allErrs, validateControllers(c)...)
+	allErrs = append(allErrs, validateLogging(c)...)
+	allErrs = append(allErrs, validateLeaderWorkerSetDefaults(c)...)
+	return allErrs
+}
+
+func validateExternalCertManagement(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	e := c.ExternalCertManagement
+	if e == nil {
+		return allErrs
+	}
+	if c.InternalCertManagement != nil && ptr.Deref(c.InternalCertManagement.Enable, false) {
+		allErrs = append(allErrs, field.Forbidden(externalCertManagementPath, "must not be set when internalCertManagement is enabled"))
+	}
+	switch {
+	case e.SecretName == "" && e.CertificateName == "":
+		allErrs = append(allErrs, field.Required(externalCertManagementPath, "one of secretName and certificateName must be set"))
+	case e.SecretName != "" && e.CertificateName != "":
+		allErrs = append(allErrs, field.Invalid(externalCertManagementPath.Child("certificateName"), e.CertificateName, "must not be set with secretName"))
+	}
+	if e.SecretName != "" {
+		if errs := apimachineryvalidation.IsDNS1123Subdomain(e.SecretName); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(externalCertManagementPath.Child("secretName"), e.SecretName, strings.Join(errs, ",")))
+		}
+	}
+	if e.CertificateName != "" {
+		if errs := apimachineryvalidation.IsDNS1123Subdomain(e.CertificateName); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(externalCertManagementPath.Child("certificateName"), e.CertificateName, strings.Join(errs, ",")))
+		}
+	}
+	if e.ExpiryWarningThreshold != nil && e.ExpiryWarningThreshold.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(externalCertManagementPath.Child("expiryWarningThreshold"), e.ExpiryWarningThreshold.Duration.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateLeaderWorkerSetDefaults(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.LeaderWorkerSetDefaults == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateLeaderWorkerSetPolicy(&c.LeaderWorkerSetDefaults.LeaderWorkerSetPolicy, leaderWorkerSetDefaultsPath)...)
+	for i, override := range c.LeaderWorkerSetDefaults.NamespaceOverrides {
+		overridePath := leaderWorkerSetDefaultsPath.Child("namespaceOverrides").Index(i)
+		if override.NamespaceSelector == nil {
+			allErrs = append(allErrs, field.Required(overridePath.Child("namespaceSelector"), ""))
+		} else {
+			allErrs = append(allErrs, metav1validation.ValidateLabelSelector(override.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, overridePath.Child("namespaceSelector"))...)
+		}
+		allErrs = append(allErrs, validateLeaderWorkerSetPolicy(&override.LeaderWorkerSetPolicy, overridePath)...)
+	}
+	return allErrs
+}
+
+func validateLeaderWorkerSetPolicy(p *configapi.LeaderWorkerSetPolicy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if p.ExclusiveTopology != "" {
+		for _, msg := range apimachineryvalidation.IsQualifiedName(p.ExclusiveTopology) {
+			allErrs = append(allErrs, field.Invalid(path.Child("exclusiveTopology"), p.ExclusiveTopology, msg))
+		}
+	}
+	if p.RestartPolicy != "" && !restartPolicies.Has(p.RestartPolicy) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("restartPolicy"), p.RestartPolicy, sets.List(restartPolicies)))
+	}
+	if p.StartupPolicy != "" && !startupPolicies.Has(p.StartupPolicy) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("startupPolicy"), p.StartupPolicy, sets.List(startupPolicies)))
+	}
+	if p.SubdomainPolicy != "" && !subdomainPolicies.Has(p.SubdomainPolicy) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("subdomainPolicy"), p.SubdomainPolicy, sets.List(subdomainPolicies)))
+	}
+	if p.MaxSurge != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(p.MaxSurge, 100, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxSurge"), p.MaxSurge.String(), err.Error()))
+		} else if value < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxSurge"), p.MaxSurge.String(), "must be greater than or equal to 0"))
+		}
+	}
+	if p.MaxSize != nil && *p.MaxSize < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxSize"), *p.MaxSize, apimachineryvalidation.InclusiveRangeError(1, math.MaxInt32)))
+	}
+	if p.MaxReplicas != nil && *p.MaxReplicas < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxReplicas"), *p.MaxReplicas, apimachineryvalidation.InclusiveRangeError(0, math.MaxInt32)))
+	}
+	return allErrs
+}
+
+func validateLogging(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Logging == nil {
+		return allErrs
+	}
+	if verbosity := c.Logging.Verbosity; verbosity != nil && *verbosity < 0 {
+		allErrs = append(allErrs, field.Invalid(loggingPath.Child("verbosity"), *verbosity, apimachineryvalidation.InclusiveRangeError(0, math.MaxInt32)))
+	}
+	return allErrs
+}
+
+func validateControllers(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Controllers == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validateControllerConfiguration(c.Controllers.LeaderWorkerSet, controllersPath.Child("leaderWorkerSet"))...)
+	allErrs = append(allErrs, validateControllerConfiguration(c.Controllers.Pod, controllersPath.Child("pod"))...)
+	return allErrs
+}
+
+func validateControllerConfiguration(c *configapi.ControllerConfiguration, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if c == nil {
+		return allErrs
+	}
+	if maxConcurrentReconciles := c.MaxConcurrentReconciles; maxConcurrentReconciles != nil && *maxConcurrentReconciles <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxConcurrentReconciles"), *maxConcurrentReconciles, apimachineryvalidation.InclusiveRangeError(1, math.MaxInt32)))
+	}
+	if rateLimiter := c.RateLimiter; rateLimiter != nil {
+		if qps := rateLimiter.QPS; qps != nil && *qps <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("rateLimiter", "qps"), *qps, "must be greater than 0"))
+		}
+		if burst := rateLimiter.Burst; burst != nil && *burst <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("rateLimiter", "burst"), *burst, apimachineryvalidation.InclusiveRangeError(1, math.MaxInt32)))
+		}
+	}
+	if backoff := c.Backoff; backoff != nil {
+		if baseDelay := backoff.BaseDelay; baseDelay != nil && baseDelay.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("backoff", "baseDelay"), baseDelay.Duration.String(), "must be greater than 0"))
+		}
+		if maxDelay := backoff.MaxDelay; maxDelay != nil && backoff.BaseDelay != nil && maxDelay.Duration < backoff.BaseDelay.Duration {
+			allErrs = append(allErrs, field.Invalid(path.Child("backoff", "maxDelay"), maxDelay.Duration.String(), "must be greater than or equal to baseDelay"))
+		}
+	}
+	return allErrs
+}
+
+func validateScope(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.Scope == nil {
+		return allErrs
+	}
+	namespaces := sets.New[string]()
+	for i, namespace := range c.Scope.Namespaces {
+		if errs := apimachineryvalidation.IsDNS1123Label(namespace); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(scopePath.Child("namespaces").Index(i), namespace, strings.Join(errs, ",")))
+		} else if namespaces.Has(namespace) {
+			allErrs = append(allErrs, field.Duplicate(scopePath.Child("namespaces").Index(i), namespace))
+		}
+		namespaces.Insert(namespace)
+	}
+	if c.Scope.NamespaceSelector != nil {
+		if len(c.Scope.Namespaces) != 0 {
+			allErrs = append(allErrs, field.Forbidden(scopePath.Child("namespaceSelector"), "may not be set along with namespaces"))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(c.Scope.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, scopePath.Child("namespaceSelector"))...)
+	}
+	if shard := c.Scope.Shard; shard != "" {
+		if errs := apimachineryvalidation.IsValidLabelValue(shard); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(scopePath.Child("shard"), shard, strings.Join(errs, ",")))
+		}
+	}
+	return allErrs
+}
+
+func validateGroupRecreation(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.GroupRecreation == nil {
+		return allErrs
+	}
+	if gracePeriod := c.GroupRecreation.NodeNotReadyGracePeriod; gracePeriod != nil && gracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(groupRecreationPath.Child("nodeNotReadyGracePeriod"), gracePeriod.Duration.String(), apimachineryvalidation.InclusiveRangeError(0, math.MaxInt64)))
+	}
+	reasons := sets.New[configapi.DisruptionReason]()
+	for i, policy := range c.GroupRecreation.DisruptionPolicies {
+		policyPath := groupRecreationPath.Child("disruptionPolicies").Index(i)
+		if !disruptionReasons.Has(policy.Reason) {
+			allErrs = append(allErrs, field.NotSupported(policyPath.Child("reason"), policy.Reason, sets.List(disruptionReasons)))
+		} else if reasons.Has(policy.Reason) {
+			allErrs = append(allErrs, field.Duplicate(policyPath.Child("reason"), policy.Reason))
+		}
+		reasons.Insert(policy.Reason)
+		if !disruptionActions.Has(policy.Action) {
+			allErrs = append(allErrs, field.NotSupported(policyPath.Child("action"), policy.Action, sets.List(disruptionActions)))
+		}
+		switch {
+		case policy.Action == configapi.DelayDisruptionAction && policy.Delay == nil:
+			allErrs = append(allErrs, field.Required(policyPath.Child("delay"), "must be set with the Delay action"))
+		case policy.Action == configapi.DelayDisruptionAction && policy.Delay.Duration <= 0:
+			allErrs = append(allErrs, field.Invalid(policyPath.Child("delay"), policy.Delay.Duration.String(), "must be greater than 0"))
+		case policy.Action != configapi.DelayDisruptionAction && policy.Delay != nil:
+			allErrs = append(allErrs, field.Forbidden(policyPath.Child("delay"), "may only be set with the Delay action"))
+		}
+	}
+	return allErrs
+}
+
+func validateMetrics(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if maxTracked := c.Metrics.MaxTrackedLeaderWorkerSets; maxTracked != nil && *maxTracked < 0 {
+		allErrs = append(allErrs, field.Invalid(metricsPath.Child("maxTrackedLeaderWorkerSets"), *maxTracked, apimachineryvalidation.InclusiveRangeError(0, math.MaxInt32)))
+	}
+	return allErrs
+}
+
+func validateHealth(c *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if timeout := c.Health.StalledReconcileTimeout; timeout != nil && timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(healthPath.Child("stalledReconcileTimeout"), timeout.Duration.String(), "must be greater than 0"))
+	}
 	return allErrs
 }
 
@@ -18,13 +18,18 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
 	"k8s.io/utils/ptr"
 
 	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 func TestValidate(t *testing.T) {
@@ -68,6 +73,377 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"negative .metrics.maxTrackedLeaderWorkerSets": {
+			cfg: &configapi.Configuration{
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{
+						MaxTrackedLeaderWorkerSets: ptr.To[int32](-1),
+					},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "metrics.maxTrackedLeaderWorkerSets",
+				},
+			},
+		},
+		"zero .metrics.maxTrackedLeaderWorkerSets": {
+			cfg: &configapi.Configuration{
+				ControllerManager: configapi.ControllerManager{
+					Metrics: configapi.ControllerMetrics{
+						MaxTrackedLeaderWorkerSets: ptr.To[int32](0),
+					},
+				},
+			},
+		},
+		"negative .groupRecreation.nodeNotReadyGracePeriod": {
+			cfg: &configapi.Configuration{
+				GroupRecreation: &configapi.GroupRecreation{
+					NodeNotReadyGracePeriod: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "groupRecreation.nodeNotReadyGracePeriod",
+				},
+			},
+		},
+		"zero .groupRecreation.nodeNotReadyGracePeriod": {
+			cfg: &configapi.Configuration{
+				GroupRecreation: &configapi.GroupRecreation{
+					NodeNotReadyGracePeriod: &metav1.Duration{},
+				},
+			},
+		},
+		"valid .groupRecreation.disruptionPolicies": {
+			cfg: &configapi.Configuration{
+				GroupRecreation: &configapi.GroupRecreation{
+					DisruptionPolicies: []configapi.DisruptionPolicy{
+						{Reason: configapi.PreemptionDisruptionReason, Action: configapi.WaitForCapacityDisruptionAction},
+						{Reason: configapi.EvictionDisruptionReason, Action: configapi.DelayDisruptionAction, Delay: &metav1.Duration{Duration: time.Minute}},
+						{Reason: configapi.TaintManagerDeletionDisruptionReason, Action: configapi.RecreateDisruptionAction},
+					},
+				},
+			},
+		},
+		"invalid .groupRecreation.disruptionPolicies": {
+			cfg: &configapi.Configuration{
+				GroupRecreation: &configapi.GroupRecreation{
+					DisruptionPolicies: []configapi.DisruptionPolicy{
+						{Reason: "Unknown", Action: configapi.RecreateDisruptionAction},
+						{Reason: configapi.EvictionDisruptionReason, Action: "Unknown"},
+						{Reason: configapi.EvictionDisruptionReason, Action: configapi.DelayDisruptionAction},
+						{Reason: configapi.PreemptionDisruptionReason, Action: configapi.DelayDisruptionAction, Delay: &metav1.Duration{}},
+						{Reason: configapi.TaintManagerDeletionDisruptionReason, Action: configapi.WaitForCapacityDisruptionAction, Delay: &metav1.Duration{Duration: time.Minute}},
+					},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "groupRecreation.disruptionPolicies[0].reason",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "groupRecreation.disruptionPolicies[1].action",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "groupRecreation.disruptionPolicies[2].reason",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "groupRecreation.disruptionPolicies[2].delay",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "groupRecreation.disruptionPolicies[3].delay",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "groupRecreation.disruptionPolicies[4].delay",
+				},
+			},
+		},
+		"valid .scope": {
+			cfg: &configapi.Configuration{
+				Scope: &configapi.ControllerScope{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "inference"}},
+					Shard:             "shard-a",
+				},
+			},
+		},
+		"invalid .scope": {
+			cfg: &configapi.Configuration{
+				Scope: &configapi.ControllerScope{
+					Namespaces:        []string{"team-a", "Team_B", "team-a"},
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "inference?"}},
+					Shard:             "shard a",
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "scope.namespaces[1]",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "scope.namespaces[2]",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "scope.namespaceSelector",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "scope.namespaceSelector.matchLabels",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "scope.shard",
+				},
+			},
+		},
+		"valid .controllers": {
+			cfg: &configapi.Configuration{
+				Controllers: &configapi.Controllers{
+					Pod: &configapi.ControllerConfiguration{
+						MaxConcurrentReconciles: ptr.To[int32](10),
+						RateLimiter: &configapi.RateLimiter{
+							QPS:   ptr.To[float32](50),
+							Burst: ptr.To[int32](200),
+						},
+						Backoff: &configapi.Backoff{
+							BaseDelay: &metav1.Duration{Duration: 100 * time.Millisecond},
+							MaxDelay:  &metav1.Duration{Duration: time.Minute},
+						},
+					},
+				},
+			},
+		},
+		"invalid .controllers": {
+			cfg: &configapi.Configuration{
+				Controllers: &configapi.Controllers{
+					LeaderWorkerSet: &configapi.ControllerConfiguration{
+						MaxConcurrentReconciles: ptr.To[int32](0),
+						RateLimiter: &configapi.RateLimiter{
+							QPS:   ptr.To[float32](0),
+							Burst: ptr.To[int32](-1),
+						},
+					},
+					Pod: &configapi.ControllerConfiguration{
+						Backoff: &configapi.Backoff{
+							BaseDelay: &metav1.Duration{Duration: time.Second},
+							MaxDelay:  &metav1.Duration{Duration: time.Millisecond},
+						},
+					},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "controllers.leaderWorkerSet.maxConcurrentReconciles",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "controllers.leaderWorkerSet.rateLimiter.qps",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "controllers.leaderWorkerSet.rateLimiter.burst",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "controllers.pod.backoff.maxDelay",
+				},
+			},
+		},
+		"negative .logging.verbosity": {
+			cfg: &configapi.Configuration{
+				Logging: &configapi.Logging{
+					Verbosity: ptr.To[int32](-1),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "logging.verbosity",
+				},
+			},
+		},
+		"valid .leaderWorkerSetDefaults": {
+			cfg: &configapi.Configuration{
+				LeaderWorkerSetDefaults: &configapi.LeaderWorkerSetDefaults{
+					LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+						ExclusiveTopology: "cloud.google.com/gke-nodepool",
+						RestartPolicy:     leaderworkerset.NoneRestartPolicy,
+						StartupPolicy:     leaderworkerset.LeaderReadyStartupPolicy,
+						SubdomainPolicy:   leaderworkerset.SubdomainUniquePerReplica,
+						MaxSurge:          ptr.To(intstr.FromString("25%")),
+						MaxSize:           ptr.To[int32](16),
+					},
+					NamespaceOverrides: []configapi.LeaderWorkerSetPolicyOverride{{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "research"}},
+						LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+							MaxReplicas: ptr.To[int32](4),
+						},
+					}},
+				},
+			},
+		},
+		"invalid .leaderWorkerSetDefaults": {
+			cfg: &configapi.Configuration{
+				LeaderWorkerSetDefaults: &configapi.LeaderWorkerSetDefaults{
+					LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+						ExclusiveTopology: "-invalid",
+						RestartPolicy:     leaderworkerset.DeprecatedDefaultRestartPolicy,
+						StartupPolicy:     "LeaderScheduled",
+						SubdomainPolicy:   "Unique",
+						MaxSurge:          ptr.To(intstr.FromInt32(-1)),
+						MaxSize:           ptr.To[int32](0),
+					},
+					NamespaceOverrides: []configapi.LeaderWorkerSetPolicyOverride{{
+						LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+							MaxSurge:    ptr.To(intstr.FromString("all")),
+							MaxReplicas: ptr.To[int32](-1),
+						},
+					}},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "leaderWorkerSetDefaults.exclusiveTopology",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "leaderWorkerSetDefaults.restartPolicy",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "leaderWorkerSetDefaults.startupPolicy",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "leaderWorkerSetDefaults.subdomainPolicy",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "leaderWorkerSetDefaults.maxSurge",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "leaderWorkerSetDefaults.maxSize",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "leaderWorkerSetDefaults.namespaceOverrides[0].namespaceSelector",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "leaderWorkerSetDefaults.namespaceOverrides[0].maxSurge",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "leaderWorkerSetDefaults.namespaceOverrides[0].maxReplicas",
+				},
+			},
+		},
+		"invalid .tracing": {
+			cfg: &configapi.Configuration{
+				Tracing: &tracingapi.TracingConfiguration{
+					Endpoint:               ptr.To("https://otel-collector:4317"),
+					SamplingRatePerMillion: ptr.To[int32](2000000),
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "tracing.samplingRatePerMillion",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "tracing.endpoint",
+				},
+			},
+		},
+		"valid .tracing": {
+			cfg: &configapi.Configuration{
+				Tracing: &tracingapi.TracingConfiguration{
+					Endpoint:               ptr.To("otel-collector.observability:4317"),
+					SamplingRatePerMillion: ptr.To[int32](100),
+				},
+			},
+		},
+		"invalid .health.stalledReconcileTimeout": {
+			cfg: &configapi.Configuration{
+				ControllerManager: configapi.ControllerManager{
+					Health: configapi.ControllerHealth{
+						StalledReconcileTimeout: &metav1.Duration{},
+					},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "health.stalledReconcileTimeout",
+				},
+			},
+		},
+		"valid .externalCertManagement": {
+			cfg: &configapi.Configuration{
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(false),
+				},
+				ExternalCertManagement: &configapi.ExternalCertManagement{
+					SecretName:             "webhook-sec",
+					ExpiryWarningThreshold: &metav1.Duration{Duration: 24 * time.Hour},
+				},
+			},
+		},
+		"invalid .externalCertManagement": {
+			cfg: &configapi.Configuration{
+				InternalCertManagement: &configapi.InternalCertManagement{
+					Enable: ptr.To(true),
+				},
+				ExternalCertManagement: &configapi.ExternalCertManagement{
+					SecretName:             "webhook-sec",
+					CertificateName:        "Webhook_Cert",
+					ExpiryWarningThreshold: &metav1.Duration{},
+				},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "externalCertManagement",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "externalCertManagement.certificateName",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "externalCertManagement.certificateName",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "externalCertManagement.expiryWarningThreshold",
+				},
+			},
+		},
+		"missing .externalCertManagement secret": {
+			cfg: &configapi.Configuration{
+				ExternalCertManagement: &configapi.ExternalCertManagement{},
+			},
+			wantErr: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "externalCertManagement",
+				},
+			},
+		},
 		"valid .internalCertManagement": {
 			cfg: &configapi.Configuration{
 				InternalCertManagement: &configapi.InternalCertManagement{
@@ -18,17 +18,23 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
@@ -36,11 +42,15 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/tracing"
 	"sigs.k8s.io/lws/pkg/utils"
 	controllerutils "sigs.k8s.io/lws/pkg/utils/controller"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
@@ -53,6 +63,14 @@ type LeaderWorkerSetReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
+	// WebhooksDisabled renders the mutations of the pod webhook into the leader StatefulSet.
+	WebhooksDisabled bool
+	// NamespaceSelector restricts the reconciler to the leaderworkersets of the namespaces matching it.
+	NamespaceSelector labels.Selector
+	// ControllerOptions are the concurrency and the rate limiter of the controller.
+	ControllerOptions controller.Options
+
+	readyGroups *readyGroups
 }
 
 var (
@@ -71,6 +89,13 @@ const (
 	GroupsProgressing = "GroupsProgressing"
 	GroupsUpdating    = "GroupsUpdating"
 	CreatingRevision  = "CreatingRevision"
+	// GroupRestarted Event reason used when a group is recreated on the request of the restart-groups annotation.
+	GroupRestarted = "GroupRestarted"
+	// FailedRestart Event reason used when a group requested by the restart-groups annotation can't be recreated.
+	FailedRestart = "FailedRestart"
+	// FieldsConflict Event reason used when the fields of a resource owned by the lws controller
+	// were changed by other managers, and are overridden.
+	FieldsConflict = "FieldsConflict"
 )
 
 func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme, record record.EventRecorder) *LeaderWorkerSetReconciler {
@@ -78,6 +103,8 @@ func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme,
 		Client: client,
 		Scheme: scheme,
 		Record: record,
+
+		readyGroups: newReadyGroups(),
 	}
 }
 
@@ -98,10 +125,27 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// Get leaderworkerset object
 	lws := &leaderworkerset.LeaderWorkerSet{}
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, lws); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.ClearLeaderWorkerSetMetrics(req.NamespacedName)
+			for _, leader := range r.readyGroups.forget(req.NamespacedName) {
+				failDeletedGroupSpan(ctx, &leader, time.Time{}, time.Time{}, time.Now())
+			}
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	log := ctrl.LoggerFrom(ctx).WithValues("leaderworkerset", klog.KObj(lws))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, span := tracing.Tracer().Start(ctx, "LeaderWorkerSetReconciler.Reconcile", trace.WithAttributes(
+		attribute.String("namespace", lws.Namespace),
+		attribute.String("leaderworkerset", lws.Name),
+	))
+	defer span.End()
+
+	if r.WebhooksDisabled {
+		if rejected, err := r.rejectUnsupportedSettings(ctx, lws); rejected || err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	leaderSts, err := r.getLeaderStatefulSet(ctx, lws)
 	if err != nil {
@@ -112,7 +156,8 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// Handles two cases:
 	// Case 1: Upgrading the LWS controller from a version that doesn't support controller revision
 	// Case 2: Creating the controller revision for a newly created LWS object
-	revision, err := r.getOrCreateRevisionIfNonExist(ctx, leaderSts, lws, r.Record)
+	collisionCount := ptr.Deref(lws.Status.CollisionCount, 0)
+	revision, err := r.getOrCreateRevisionIfNonExist(ctx, leaderSts, lws, &collisionCount, r.Record)
 	if err != nil {
 		log.Error(err, "Creating controller revision")
 		return ctrl.Result{}, err
@@ -125,7 +170,7 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	lwsUpdated := updatedRevision != nil
 	if lwsUpdated {
-		revision, err = revisionutils.CreateRevision(ctx, r.Client, updatedRevision, lws)
+		revision, err = revisionutils.CreateRevision(ctx, r.Client, updatedRevision, lws, &collisionCount)
 		if err != nil {
 			log.Error(err, "Creating revision for updated LWS")
 			return ctrl.Result{}, err
@@ -154,21 +199,34 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		r.Record.Eventf(lws, corev1.EventTypeNormal, GroupsUpdating, fmt.Sprintf("Updating replicas %d to %d", *leaderSts.Spec.UpdateStrategy.RollingUpdate.Partition, partition))
 	}
 
-	// Create headless service if it does not exist.
-	if err := r.reconcileHeadlessServices(ctx, lws); err != nil {
+	// The groups run the revision of the leader statefulset until the first rolling update is done,
+	// for leaderworkersets created before the revisions were reported in the status.
+	currentRevisionKey := lws.Status.CurrentRevision
+	if currentRevisionKey == "" && leaderSts != nil {
+		currentRevisionKey = revisionutils.GetRevisionKey(leaderSts)
+	}
+
+	// Create the headless services if they do not exist, and delete the ones no group uses anymore.
+	if err := r.reconcileHeadlessServices(ctx, lws, currentRevisionKey, revisionutils.GetRevisionKey(revision)); err != nil {
 		log.Error(err, "Creating headless service.")
 		r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate,
 			fmt.Sprintf("Failed to create headless service for error: %v", err))
 		return ctrl.Result{}, err
 	}
 
-	updateDone, err := r.updateStatus(ctx, lws, revisionutils.GetRevisionKey(revision))
+	if err := r.restartRequestedGroups(ctx, lws); err != nil {
+		log.Error(err, "Restarting the requested groups")
+		return ctrl.Result{}, err
+	}
+
+	updateDone, err := r.updateStatus(ctx, lws, currentRevisionKey, revisionutils.GetRevisionKey(revision), collisionCount)
 	if err != nil {
 		if apierrors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	metrics.ReportLeaderWorkerSetStatus(req.NamespacedName, *lws.Spec.Replicas, lws.Status.Replicas, lws.Status.ReadyReplicas, lws.Status.UpdatedReplicas, partition)
 
 	if updateDone {
 		if err := revisionutils.TruncateRevisions(ctx, r.Client, lws, revisionutils.GetRevisionKey(revision)); err != nil {
@@ -179,20 +237,144 @@ func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return ctrl.Result{}, nil
 }
 
-func (r *LeaderWorkerSetReconciler) reconcileHeadlessServices(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
-	if lws.Spec.NetworkConfig == nil || *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainShared {
-		if err := controllerutils.CreateHeadlessServiceIfNotExists(ctx, r.Client, r.Scheme, lws, lws.Name, map[string]string{leaderworkerset.SetNameLabelKey: lws.Name}, lws); err != nil {
+// reconcileHeadlessServices reconciles the shared headless service, and deletes the headless services of the
+// subdomain policies no group uses anymore. The subdomain of a group follows the revision of its leader pod,
+// so when the subdomain policy changes the groups are moved to the new subdomains by the rolling update, and
+// the services of the previous subdomain policy are kept until the rolling update is done. The per replica
+// headless services are reconciled by the pod controller, along with the worker statefulsets of their groups.
+func (r *LeaderWorkerSetReconciler) reconcileHeadlessServices(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, currentRevisionKey, updateRevisionKey string) error {
+	policies, err := r.subdomainPoliciesInUse(ctx, lws, currentRevisionKey, updateRevisionKey)
+	if err != nil {
+		return err
+	}
+	// Without the pod webhook, the leader pods are always addressed through the shared headless service.
+	if policies.Has(leaderworkerset.SubdomainShared) || r.WebhooksDisabled {
+		selector := map[string]string{leaderworkerset.SetNameLabelKey: lws.Name}
+		if !policies.Has(leaderworkerset.SubdomainShared) {
+			selector[leaderworkerset.WorkerIndexLabelKey] = "0"
+		}
+		if err := controllerutils.ReconcileHeadlessService(ctx, r.Client, r.Scheme, r.Record, lws, lws.Name, selector, lws); err != nil {
 			return err
 		}
+	} else if err := controllerutils.DeleteHeadlessService(ctx, r.Client, lws.Namespace, lws.Name, lws); err != nil {
+		return err
+	}
+	if policies.Has(leaderworkerset.SubdomainUniquePerReplica) {
+		return nil
+	}
+	// The per replica headless services are controlled by the leader pods of their groups.
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		owner := metav1.GetControllerOf(service)
+		if owner == nil || owner.Kind != "Pod" || service.DeletionTimestamp != nil {
+			continue
+		}
+		ctrl.LoggerFrom(ctx).V(2).Info("Deleting per replica headless service.", "service", service.Name)
+		if err := r.Delete(ctx, service, client.Preconditions{UID: &service.UID}); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subdomainPoliciesInUse returns the subdomain policies of the groups: the policy of the update revision and,
+// until the rolling update is done, the policy of the current revision.
+func (r *LeaderWorkerSetReconciler) subdomainPoliciesInUse(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, currentRevisionKey, updateRevisionKey string) (sets.Set[leaderworkerset.SubdomainPolicy], error) {
+	policies := sets.New(subdomainPolicy(lws))
+	if currentRevisionKey == "" || currentRevisionKey == updateRevisionKey {
+		return policies, nil
+	}
+	currentRevision, err := revisionutils.GetRevision(ctx, r.Client, lws, currentRevisionKey)
+	if err != nil {
+		return nil, err
+	}
+	if currentRevision == nil {
+		// The policy of the groups on the current revision is unknown, the services of both policies are kept.
+		return policies.Insert(leaderworkerset.SubdomainShared, leaderworkerset.SubdomainUniquePerReplica), nil
+	}
+	currentLws, err := revisionutils.ApplyRevision(lws, currentRevision)
+	if err != nil {
+		return nil, err
+	}
+	return policies.Insert(subdomainPolicy(currentLws)), nil
+}
+
+// subdomainPolicy returns the subdomain policy of the leaderworkerset, which is Shared if unset.
+func subdomainPolicy(lws *leaderworkerset.LeaderWorkerSet) leaderworkerset.SubdomainPolicy {
+	if lws.Spec.NetworkConfig == nil || lws.Spec.NetworkConfig.SubdomainPolicy == nil {
+		return leaderworkerset.SubdomainShared
+	}
+	return *lws.Spec.NetworkConfig.SubdomainPolicy
+}
+
+// restartRequestedGroups removes the restart-groups annotation, then recreates the groups it listed.
+// The annotation is removed first with an optimistic lock, so a value written in the meantime is kept for
+// the next reconcile and a group is never restarted twice for the same request.
+// Groups out of range, or whose leader pod is already gone or being deleted, are skipped.
+func (r *LeaderWorkerSetReconciler) restartRequestedGroups(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	value, found := lws.Annotations[leaderworkerset.RestartGroupsAnnotationKey]
+	if !found {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	indexes, err := utils.ParseGroupIndexes(value)
+	if err != nil {
+		// The annotation is validated by the webhook, an invalid value is dropped rather than retried.
+		log.Error(err, "Parsing the groups to restart", "annotation", value)
+	}
+	patch := client.MergeFromWithOptions(lws.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	delete(lws.Annotations, leaderworkerset.RestartGroupsAnnotationKey)
+	if err := r.Patch(ctx, lws, patch); err != nil {
+		return err
+	}
+	var errs []error
+	for _, index := range indexes {
+		if index >= int(*lws.Spec.Replicas) {
+			log.V(2).Info("Skipping the restart of a group out of range", "group", index)
+			continue
+		}
+		if err := r.restartGroup(ctx, lws, index); err != nil {
+			// The request is already removed from the annotation, the failure is reported rather than retried.
+			r.Record.AnnotatedEventf(lws, map[string]string{
+				leaderworkerset.GroupIndexLabelKey: strconv.Itoa(index),
+			}, corev1.EventTypeWarning, FailedRestart, fmt.Sprintf("Failed to restart group %d: %v", index, err))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restartGroup deletes the leader pod of the group at index to recreate the group.
+func (r *LeaderWorkerSetReconciler) restartGroup(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, index int) error {
+	var leader corev1.Pod
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: fmt.Sprintf("%s-%d", lws.Name, index)}, &leader); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if leader.DeletionTimestamp != nil || leader.Labels[leaderworkerset.SetNameLabelKey] != lws.Name {
 		return nil
 	}
+	if err := recreateGroup(ctx, r.Client, lws, &leader, metrics.RestartRequestedReason); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Record.AnnotatedEventf(lws, map[string]string{
+		leaderworkerset.GroupIndexLabelKey: strconv.Itoa(index),
+	}, corev1.EventTypeNormal, GroupRestarted, fmt.Sprintf("Deleted leader pod %s to restart group %d", leader.Name, index))
 	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&leaderworkerset.LeaderWorkerSet{}).
+	b := ctrl.NewControllerManagedBy(mgr)
+	if r.NamespaceSelector != nil {
+		b = b.WithEventFilter(namespaceSelectorPredicate(mgr.GetClient(), r.NamespaceSelector)).
+			WatchesMetadata(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.leaderWorkerSetsInNamespace), builder.WithPredicates(namespaceLabelsChanged()))
+	}
+	return b.For(&leaderworkerset.LeaderWorkerSet{}).
+		WithOptions(r.ControllerOptions).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Watches(&appsv1.StatefulSet{},
@@ -208,6 +390,16 @@ func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func SetupIndexes(indexer client.FieldIndexer) error {
+	// The pods are looked up by node when a node becomes NotReady.
+	if err := indexer.IndexField(context.Background(), &corev1.Pod{}, podNodeNameKey, func(rawObj client.Object) []string {
+		pod := rawObj.(*corev1.Pod)
+		if pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	}); err != nil {
+		return err
+	}
 	return indexer.IndexField(context.Background(), &appsv1.StatefulSet{}, lwsOwnerKey, func(rawObj client.Object) []string {
 		// grab the statefulSet object, extract the owner...
 		statefulSet := rawObj.(*appsv1.StatefulSet)
@@ -314,6 +506,12 @@ func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context,
 	}
 
 	// Case 5:
+	// The rolling update is paused, the Partition is kept until it's resumed.
+	if lws.Annotations[leaderworkerset.RolloutPausedAnnotationKey] == "true" {
+		return partition, wantReplicas(lwsUnreadyReplicas), nil
+	}
+
+	// Case 6:
 	// Calculating the Partition during rolling update, no leaderWorkerSet updates happens.
 
 	rollingStep, err := intstr.GetScaledValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, int(lwsReplicas), false)
@@ -333,7 +531,7 @@ func (r *LeaderWorkerSetReconciler) SSAWithStatefulset(ctx context.Context, lws
 	log := ctrl.LoggerFrom(ctx)
 
 	// construct the statefulset apply configuration
-	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, partition, replicas, revisionKey)
+	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, partition, replicas, revisionKey, r.WebhooksDisabled)
 	if err != nil {
 		log.Error(err, "Constructing StatefulSet apply configuration.")
 		return err
@@ -380,12 +578,16 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 	}
 
 	updateStatus := false
-	readyCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndReadyCount := 0, 0, 0, 0, 0
+	var readyLeaders []corev1.Pod
+	workerStatefulSetsCreation := map[string]time.Time{}
+	readyCount, availableCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndReadyCount := 0, 0, 0, 0, 0, 0
+	// readyNonBurstCount excludes the groups being recreated, whose leader pod may still be ready.
+	readyNonBurstCount := 0
 	noWorkerSts := *lws.Spec.LeaderWorkerTemplate.Size == 1
 
 	// Iterate through all leaderPods.
 	for _, pod := range leaderPodList.Items {
-		index, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		index, err := leaderGroupIndex(pod)
 		if err != nil {
 			return false, false, err
 		}
@@ -404,10 +606,21 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 			}
 		}
 
+		if !noWorkerSts {
+			workerStatefulSetsCreation[pod.Name] = sts.CreationTimestamp.Time
+		}
+
 		var ready, updated bool
 		if (noWorkerSts || statefulsetutils.StatefulsetReady(sts)) && podutils.PodRunningAndReady(pod) {
 			ready = true
 			readyCount++
+			readyLeaders = append(readyLeaders, pod)
+			if noWorkerSts || sts.Status.AvailableReplicas == *sts.Spec.Replicas {
+				availableCount++
+			}
+			if index < int(*lws.Spec.Replicas) && pod.DeletionTimestamp == nil {
+				readyNonBurstCount++
+			}
 		}
 		if (noWorkerSts || revisionutils.GetRevisionKey(&sts) == revisionKey) && revisionutils.GetRevisionKey(&pod) == revisionKey {
 			updated = true
@@ -426,11 +639,18 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		}
 	}
 
+	r.reportReadyGroups(ctx, lws, leaderPodList.Items, readyLeaders, workerStatefulSetsCreation)
+
 	if lws.Status.ReadyReplicas != int32(readyCount) {
 		lws.Status.ReadyReplicas = int32(readyCount)
 		updateStatus = true
 	}
 
+	if lws.Status.AvailableReplicas != int32(availableCount) {
+		lws.Status.AvailableReplicas = int32(availableCount)
+		updateStatus = true
+	}
+
 	if lws.Status.UpdatedReplicas != int32(updatedCount) {
 		lws.Status.UpdatedReplicas = int32(updatedCount)
 		updateStatus = true
@@ -450,16 +670,32 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetProgressing))
 	}
 
+	groupsReady := fmt.Sprintf(", with %d groups ready of total %d groups", readyCount, int(*lws.Spec.Replicas))
 	updateCondition := setConditions(lws, conditions)
 	// if condition changed, record events
 	if updateCondition {
-		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, conditions[0].Message+fmt.Sprintf(", with %d groups ready of total %d groups", readyCount, int(*lws.Spec.Replicas)))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, conditions[0].Message+groupsReady)
+	}
+	if readyNonBurstCount == int(*lws.Spec.Replicas) {
+		// The groups recreated after a failure are all ready again, whether or not a rolling update is in progress.
+		notDegraded := metav1.Condition{
+			Type:    string(leaderworkerset.LeaderWorkerSetDegraded),
+			Status:  metav1.ConditionFalse,
+			Reason:  "AllGroupsReady",
+			Message: "All replicas are ready",
+		}
+		if setCondition(lws, notDegraded) {
+			r.Record.Eventf(lws, corev1.EventTypeNormal, notDegraded.Reason, notDegraded.Message+groupsReady)
+			updateCondition = true
+		}
 	}
 	return updateStatus || updateCondition, updateDone, nil
 }
 
 // Updates status and condition of LeaderWorkerSet and returns whether or not an update actually occurred.
-func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, revisionKey string) (bool, error) {
+// currentRevisionKey is the revision the groups ran before the rolling update, if any, and updateRevisionKey
+// the revision of the latest leaderWorkerTemplate.
+func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, currentRevisionKey, updateRevisionKey string, collisionCount int32) (bool, error) {
 	updateStatus := false
 	log := ctrl.LoggerFrom(ctx)
 
@@ -494,12 +730,36 @@ func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leade
 		updateStatus = true
 	}
 
+	if r.WebhooksDisabled {
+		// The leaderworkersets with unsupported settings are not reconciled, see rejectUnsupportedSettings.
+		updateStatus = setUnsupportedSettingsCondition(lws, nil) || updateStatus
+	}
+
+	if lws.Status.ObservedGeneration != lws.Generation {
+		lws.Status.ObservedGeneration = lws.Generation
+		updateStatus = true
+	}
+
+	if ptr.Deref(lws.Status.CollisionCount, 0) != collisionCount {
+		lws.Status.CollisionCount = ptr.To(collisionCount)
+		updateStatus = true
+	}
+
 	// check if an update is needed
-	updateConditions, updateDone, err := r.updateConditions(ctx, lws, revisionKey)
+	updateConditions, updateDone, err := r.updateConditions(ctx, lws, updateRevisionKey)
 	if err != nil {
 		return false, err
 	}
 
+	if currentRevisionKey == "" || updateDone {
+		currentRevisionKey = updateRevisionKey
+	}
+	if lws.Status.CurrentRevision != currentRevisionKey || lws.Status.UpdateRevision != updateRevisionKey {
+		lws.Status.CurrentRevision = currentRevisionKey
+		lws.Status.UpdateRevision = updateRevisionKey
+		updateStatus = true
+	}
+
 	if updateStatus || updateConditions {
 		if err := r.Status().Update(ctx, lws); err != nil {
 			if !apierrors.IsConflict(err) {
@@ -511,6 +771,28 @@ func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leade
 	return updateDone, nil
 }
 
+// rejectUnsupportedSettings reports the settings of the leaderworkerset which can't be honored without the pod
+// webhook in the UnsupportedWithoutWebhooks condition and in an event once per generation, and returns whether
+// there are any. The groups of such a leaderworkerset are not created, rather than created without the settings.
+func (r *LeaderWorkerSetReconciler) rejectUnsupportedSettings(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	settings := webhooklessUnsupportedSettings(lws)
+	if len(settings) == 0 {
+		return false, nil
+	}
+	conditionChanged := setUnsupportedSettingsCondition(lws, settings)
+	if !conditionChanged && lws.Status.ObservedGeneration == lws.Generation {
+		return true, nil
+	}
+	for _, setting := range settings {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, UnsupportedWithoutWebhooks, unsupportedSettingsMessage(setting))
+	}
+	lws.Status.ObservedGeneration = lws.Generation
+	if err := r.Status().Update(ctx, lws); err != nil {
+		return true, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
 // iterateReplicas will iterate the leader pods together with corresponding worker statefulsets
 // to check the replica state, and return two values and an error in the end:
 //   - The first value represents the number of continuous ready replicas ranging from the last index to 0,
@@ -528,9 +810,7 @@ func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *le
 
 	// Get a sorted leader pod list matches with the following sorted statefulsets one by one, which means
 	// the leader pod and the corresponding worker statefulset has the same index.
-	sortedPods := utils.SortByIndex(func(pod corev1.Pod) (int, error) {
-		return strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
-	}, leaderPodList.Items, int(stsReplicas))
+	sortedPods := utils.SortByIndex(leaderGroupIndex, leaderPodList.Items, int(stsReplicas))
 
 	stsSelector := client.MatchingLabels(map[string]string{
 		leaderworkerset.SetNameLabelKey: lws.Name,
@@ -596,7 +876,7 @@ func (r *LeaderWorkerSetReconciler) getLeaderStatefulSet(ctx context.Context, lw
 	return sts, nil
 }
 
-func (r *LeaderWorkerSetReconciler) getOrCreateRevisionIfNonExist(ctx context.Context, sts *appsv1.StatefulSet, lws *leaderworkerset.LeaderWorkerSet, recorder record.EventRecorder) (*appsv1.ControllerRevision, error) {
+func (r *LeaderWorkerSetReconciler) getOrCreateRevisionIfNonExist(ctx context.Context, sts *appsv1.StatefulSet, lws *leaderworkerset.LeaderWorkerSet, collisionCount *int32, recorder record.EventRecorder) (*appsv1.ControllerRevision, error) {
 	revisionKey := ""
 	if sts != nil {
 		// Uses the hash in the leader sts to avoid detecting update in the case where LWS controller is upgraded from a version where
@@ -610,7 +890,7 @@ func (r *LeaderWorkerSetReconciler) getOrCreateRevisionIfNonExist(ctx context.Co
 	if err != nil {
 		return nil, err
 	}
-	newRevision, err := revisionutils.CreateRevision(ctx, r.Client, revision, lws)
+	newRevision, err := revisionutils.CreateRevision(ctx, r.Client, revision, lws, collisionCount)
 	if err == nil {
 		message := fmt.Sprintf("Creating revision with key %s for a newly created LeaderWorkerSet", revision.Labels[leaderworkerset.RevisionKey])
 		if revisionKey != "" {
@@ -638,14 +918,35 @@ func (r *LeaderWorkerSetReconciler) getUpdatedRevision(ctx context.Context, sts
 	return nil, nil
 }
 
-// constructLeaderStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
-func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32, revisionKey string) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
+// constructLeaderStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet,
+// webhooksDisabled renders the mutations of the pod webhook into the pod template.
+func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32, revisionKey string, webhooksDisabled bool) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	var podTemplateSpec corev1.PodTemplateSpec
 	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
 		podTemplateSpec = *lws.Spec.LeaderWorkerTemplate.LeaderTemplate.DeepCopy()
 	} else {
 		podTemplateSpec = *lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
 	}
+	podAnnotations := make(map[string]string)
+	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
+	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+	}
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
+		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
+		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
+			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
+		}
+	}
+
+	if lws.Spec.NetworkConfig != nil && *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
+		podAnnotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
+	}
+	if webhooksDisabled {
+		if err := renderLeaderPodTemplate(lws, &podTemplateSpec, podAnnotations); err != nil {
+			return nil, err
+		}
+	}
 	// construct pod template spec configuration
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTemplateSpec)
 	if err != nil {
@@ -662,21 +963,6 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 		leaderworkerset.SetNameLabelKey:     lws.Name,
 		leaderworkerset.RevisionKey:         revisionKey,
 	})
-	podAnnotations := make(map[string]string)
-	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
-	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
-		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
-	}
-	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
-		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
-			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
-		}
-	}
-
-	if lws.Spec.NetworkConfig != nil && *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
-		podAnnotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
-	}
 	podTemplateApplyConfiguration.WithAnnotations(podAnnotations)
 
 	// construct statefulset apply configuration
@@ -704,6 +990,19 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 	return statefulSetConfig, nil
 }
 
+// leaderGroupIndex returns the group index of a leader pod. Without the pod webhook, the group index label
+// is patched once the pod is created, so it falls back to the pod ordinal.
+func leaderGroupIndex(pod corev1.Pod) (int, error) {
+	if index, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]; found {
+		return strconv.Atoi(index)
+	}
+	_, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	if ordinal == -1 {
+		return 0, fmt.Errorf("parsing pod ordinal for pod %s", pod.Name)
+	}
+	return ordinal, nil
+}
+
 func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) metav1.Condition {
 	var condtype, reason, message string
 	switch conditionType {
@@ -23,11 +23,18 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
@@ -404,7 +411,7 @@ func TestLeaderStatefulSetApplyConfig(t *testing.T) {
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, *tc.lws.Spec.Replicas, tc.revisionKey)
+			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, *tc.lws.Spec.Replicas, tc.revisionKey, false)
 			if err != nil {
 				t.Errorf("failed with error: %s", err.Error())
 			}
@@ -535,3 +542,308 @@ func TestSetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSubdomainPoliciesInUse(t *testing.T) {
+	sharedLws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+		SubdomainPolicy(leaderworkerset.SubdomainShared).Obj()
+	uniqueLws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+		SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica).Obj()
+
+	client := fake.NewClientBuilder().Build()
+	sharedRevision, err := revisionutils.NewRevision(context.TODO(), client, sharedLws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(context.TODO(), sharedRevision); err != nil {
+		t.Fatal(err)
+	}
+	uniqueRevision, err := revisionutils.NewRevision(context.TODO(), client, uniqueLws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(context.TODO(), uniqueRevision); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		lws                *leaderworkerset.LeaderWorkerSet
+		currentRevisionKey string
+		updateRevisionKey  string
+		want               []leaderworkerset.SubdomainPolicy
+	}{
+		{
+			name:               "rolling update done",
+			lws:                uniqueLws,
+			currentRevisionKey: revisionutils.GetRevisionKey(uniqueRevision),
+			updateRevisionKey:  revisionutils.GetRevisionKey(uniqueRevision),
+			want:               []leaderworkerset.SubdomainPolicy{leaderworkerset.SubdomainUniquePerReplica},
+		},
+		{
+			name:              "no current revision",
+			lws:               sharedLws,
+			updateRevisionKey: revisionutils.GetRevisionKey(sharedRevision),
+			want:              []leaderworkerset.SubdomainPolicy{leaderworkerset.SubdomainShared},
+		},
+		{
+			name:               "rolling update from Shared to UniquePerReplica",
+			lws:                uniqueLws,
+			currentRevisionKey: revisionutils.GetRevisionKey(sharedRevision),
+			updateRevisionKey:  revisionutils.GetRevisionKey(uniqueRevision),
+			want:               []leaderworkerset.SubdomainPolicy{leaderworkerset.SubdomainShared, leaderworkerset.SubdomainUniquePerReplica},
+		},
+		{
+			name:               "rolling update from UniquePerReplica to Shared",
+			lws:                sharedLws,
+			currentRevisionKey: revisionutils.GetRevisionKey(uniqueRevision),
+			updateRevisionKey:  revisionutils.GetRevisionKey(sharedRevision),
+			want:               []leaderworkerset.SubdomainPolicy{leaderworkerset.SubdomainShared, leaderworkerset.SubdomainUniquePerReplica},
+		},
+		{
+			name:               "current revision not found",
+			lws:                sharedLws,
+			currentRevisionKey: "missing",
+			updateRevisionKey:  revisionutils.GetRevisionKey(sharedRevision),
+			want:               []leaderworkerset.SubdomainPolicy{leaderworkerset.SubdomainShared, leaderworkerset.SubdomainUniquePerReplica},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &LeaderWorkerSetReconciler{Client: client}
+			got, err := r.subdomainPoliciesInUse(context.TODO(), tc.lws, tc.currentRevisionKey, tc.updateRevisionKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, sets.List(got)); diff != "" {
+				t.Errorf("Unexpected subdomain policies (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileHeadlessServicesWithoutWebhooks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(leaderworkerset.AddToScheme(scheme))
+	sharedLws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+		SubdomainPolicy(leaderworkerset.SubdomainShared).Obj()
+	uniqueLws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+		WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+		SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica).Obj()
+
+	tests := []struct {
+		name         string
+		lws          *leaderworkerset.LeaderWorkerSet
+		wantSelector map[string]string
+	}{
+		{
+			name:         "shared subdomain",
+			lws:          sharedLws,
+			wantSelector: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+		{
+			name: "unique subdomain per replica, only the leaders are addressed through the shared service",
+			lws:  uniqueLws,
+			wantSelector: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			revision, err := revisionutils.NewRevision(context.TODO(), client, tc.lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Create(context.TODO(), revision); err != nil {
+				t.Fatal(err)
+			}
+			r := &LeaderWorkerSetReconciler{Client: client, Scheme: scheme, Record: record.NewFakeRecorder(10), WebhooksDisabled: true}
+			revisionKey := revisionutils.GetRevisionKey(revision)
+			if err := r.reconcileHeadlessServices(context.TODO(), tc.lws, revisionKey, revisionKey); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var service corev1.Service
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: "test-sample", Namespace: "default"}, &service); err != nil {
+				t.Fatalf("Getting the shared headless service: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantSelector, service.Spec.Selector); diff != "" {
+				t.Errorf("Unexpected selector (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateConditionsClearsDegraded(t *testing.T) {
+	leaderPod := func(name string, ready bool, deleted bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.RevisionKey:         "old",
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if ready {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		}
+		if deleted {
+			pod.Finalizers = []string{"test"}
+			pod.DeletionTimestamp = ptr.To(metav1.Now())
+		}
+		return pod
+	}
+	tests := []struct {
+		name         string
+		leaderPods   []*corev1.Pod
+		wantDegraded metav1.ConditionStatus
+		wantEvents   []string
+	}{
+		{
+			name:         "all groups ready during a rolling update",
+			leaderPods:   []*corev1.Pod{leaderPod("test-sample-0", true, false), leaderPod("test-sample-1", true, false)},
+			wantDegraded: metav1.ConditionFalse,
+			wantEvents:   []string{"Normal AllGroupsReady All replicas are ready, with 2 groups ready of total 2 groups"},
+		},
+		{
+			name:         "group not ready",
+			leaderPods:   []*corev1.Pod{leaderPod("test-sample-0", true, false), leaderPod("test-sample-1", false, false)},
+			wantDegraded: metav1.ConditionTrue,
+		},
+		{
+			name:         "group being recreated",
+			leaderPods:   []*corev1.Pod{leaderPod("test-sample-0", true, false), leaderPod("test-sample-1", true, true)},
+			wantDegraded: metav1.ConditionTrue,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(2).Size(1).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj()
+			// The rolling update is already reported, only the Degraded condition may change.
+			lws.Status.Conditions = []metav1.Condition{
+				{Type: string(leaderworkerset.LeaderWorkerSetDegraded), Status: metav1.ConditionTrue, Reason: "OOMKilled"},
+				{Type: string(leaderworkerset.LeaderWorkerSetProgressing), Status: metav1.ConditionTrue, Reason: "GroupsProgressing"},
+				{Type: string(leaderworkerset.LeaderWorkerSetUpdateInProgress), Status: metav1.ConditionTrue, Reason: "GroupsUpdating"},
+			}
+			builder := fake.NewClientBuilder()
+			for _, pod := range tc.leaderPods {
+				builder = builder.WithObjects(pod)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := NewLeaderWorkerSetReconciler(builder.Build(), nil, recorder)
+			if _, _, err := r.updateConditions(context.TODO(), lws, "new"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			degraded := meta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetDegraded))
+			if degraded == nil || degraded.Status != tc.wantDegraded {
+				t.Errorf("Unexpected Degraded condition, want status %s, got %v", tc.wantDegraded, degraded)
+			}
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if diff := cmp.Diff(tc.wantEvents, events); diff != "" {
+				t.Errorf("Unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRestartRequestedGroups(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(leaderworkerset.AddToScheme(scheme))
+	leaderPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		concurrentValue *string
+		wantErr         bool
+		wantAnnotations map[string]string
+		wantPods        []string
+		wantEvents      []string
+	}{
+		{
+			name:       "requested groups restarted once the annotation is removed",
+			wantPods:   []string{"test-sample-1"},
+			wantEvents: []string{"Normal GroupRestarted Deleted leader pod test-sample-0 to restart group 0 map[leaderworkerset.sigs.k8s.io/group-index:0]"},
+		},
+		{
+			name:            "annotation changed in the meantime",
+			concurrentValue: ptr.To("0,1"),
+			wantErr:         true,
+			wantAnnotations: map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,1"},
+			wantPods:        []string{"test-sample-0", "test-sample-1"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(2).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+				Annotation(map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,5"}).Obj()
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(lws, leaderPod("test-sample-0"), leaderPod("test-sample-1")).Build()
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-sample"}, lws); err != nil {
+				t.Fatal(err)
+			}
+			if tc.concurrentValue != nil {
+				concurrent := lws.DeepCopy()
+				concurrent.Annotations[leaderworkerset.RestartGroupsAnnotationKey] = *tc.concurrentValue
+				if err := k8sClient.Update(context.TODO(), concurrent); err != nil {
+					t.Fatal(err)
+				}
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := NewLeaderWorkerSetReconciler(k8sClient, scheme, recorder)
+			if err := r.restartRequestedGroups(context.TODO(), lws); (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got leaderworkerset.LeaderWorkerSet
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-sample"}, &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, got.Annotations); diff != "" {
+				t.Errorf("Unexpected annotations (-want +got):\n%s", diff)
+			}
+			var pods corev1.PodList
+			if err := k8sClient.List(context.TODO(), &pods); err != nil {
+				t.Fatal(err)
+			}
+			var podNames []string
+			for _, pod := range pods.Items {
+				podNames = append(podNames, pod.Name)
+			}
+			if diff := cmp.Diff(tc.wantPods, podNames); diff != "" {
+				t.Errorf("Unexpected pods (-want +got):\n%s", diff)
+			}
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if diff := cmp.Diff(tc.wantEvents, events); diff != "" {
+				t.Errorf("Unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// namespaceSelectorPredicate filters the namespaced objects to the ones of the namespaces whose labels
+// match the selector, the cluster scoped objects aren't filtered. Only the metadata of the namespaces is cached.
+func namespaceSelectorPredicate(c client.Reader, selector labels.Selector) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		if object.GetNamespace() == "" {
+			return true
+		}
+		namespace := metav1.PartialObjectMetadata{}
+		namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		if err := c.Get(context.Background(), types.NamespacedName{Name: object.GetNamespace()}, &namespace); err != nil {
+			return false
+		}
+		return selector.Matches(labels.Set(namespace.Labels))
+	})
+}
+
+// namespaceLabelsChanged filters the namespace updates changing their labels.
+func namespaceLabelsChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+	}
+}
+
+// leaderWorkerSetsInNamespace returns the requests of the leaderworkersets of the namespace,
+// to reconcile them once the namespace is selected.
+func (r *LeaderWorkerSetReconciler) leaderWorkerSetsInNamespace(ctx context.Context, namespace client.Object) []reconcile.Request {
+	var lwsList leaderworkerset.LeaderWorkerSetList
+	if err := r.List(ctx, &lwsList, client.InNamespace(namespace.GetName())); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Listing the leaderworkersets of namespace", "namespace", namespace.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(lwsList.Items))
+	for _, lws := range lwsList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&lws)})
+	}
+	return requests
+}
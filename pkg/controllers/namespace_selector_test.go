@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestNamespaceSelectorPredicate(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "inference"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "training"}}},
+	).Build()
+	selector := labels.SelectorFromSet(labels.Set{"team": "inference"})
+
+	tests := []struct {
+		name   string
+		object client.Object
+		want   bool
+	}{
+		{
+			name:   "object of a selected namespace",
+			object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "team-a"}},
+			want:   true,
+		},
+		{
+			name:   "object of another namespace",
+			object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "team-b"}},
+		},
+		{
+			name:   "object of a missing namespace",
+			object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "team-c"}},
+		},
+		{
+			name:   "cluster scoped object",
+			object: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}},
+			want:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := namespaceSelectorPredicate(c, selector).Generic(event.GenericEvent{Object: tc.object}); got != tc.want {
+				t.Errorf("unexpected result, want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNamespaceLabelsChanged(t *testing.T) {
+	namespace := func(labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: labels}}
+	}
+	tests := []struct {
+		name         string
+		oldNamespace *corev1.Namespace
+		newNamespace *corev1.Namespace
+		want         bool
+	}{
+		{
+			name:         "label added",
+			oldNamespace: namespace(nil),
+			newNamespace: namespace(map[string]string{"team": "inference"}),
+			want:         true,
+		},
+		{
+			name:         "label changed",
+			oldNamespace: namespace(map[string]string{"team": "training"}),
+			newNamespace: namespace(map[string]string{"team": "inference"}),
+			want:         true,
+		},
+		{
+			name:         "labels unchanged",
+			oldNamespace: namespace(map[string]string{"team": "inference"}),
+			newNamespace: namespace(map[string]string{"team": "inference"}),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := namespaceLabelsChanged().Update(event.UpdateEvent{ObjectOld: tc.oldNamespace, ObjectNew: tc.newNamespace}); got != tc.want {
+				t.Errorf("unexpected result, want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
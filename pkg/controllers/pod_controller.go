@@ -21,12 +21,19 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
@@ -35,11 +42,20 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/tracing"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
 	controllerutils "sigs.k8s.io/lws/pkg/utils/controller"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
@@ -47,11 +63,58 @@ import (
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
+const (
+	// GroupRecreated Event reason used when a group is recreated after one of its pods failed.
+	GroupRecreated = "GroupRecreated"
+	// GroupRecreationDeferred Event reason used when the recreation of a group is deferred by the
+	// disruption policy of one of its pods.
+	GroupRecreationDeferred = "GroupRecreationDeferred"
+
+	// The annotations of the GroupRecreated events, for the tools processing them.
+	failedPodEventAnnotationKey     = "leaderworkerset.sigs.k8s.io/failed-pod"
+	failureReasonEventAnnotationKey = "leaderworkerset.sigs.k8s.io/failure-reason"
+	failedNodeEventAnnotationKey    = "leaderworkerset.sigs.k8s.io/failed-node"
+
+	// nodeNotReadyReason is the failure reason of a pod whose node has been NotReady for longer than the grace period.
+	nodeNotReadyReason = "NodeNotReady"
+
+	podNodeNameKey = "spec.nodeName"
+
+	// The annotations of a leader pod whose group recreation is deferred by a disruption policy:
+	// the reason of the DisruptionTarget condition of the disrupted pod, and when the recreation was deferred.
+	recreationDeferredReasonAnnotationKey = "leaderworkerset.sigs.k8s.io/recreation-deferred-reason"
+	recreationDeferredSinceAnnotationKey  = "leaderworkerset.sigs.k8s.io/recreation-deferred-since"
+)
+
+// disruptionReasons maps the reasons of the DisruptionTarget condition to the reasons of the disruption policies.
+var disruptionReasons = map[string]configapi.DisruptionReason{
+	podutils.PreemptionByScheduler:  configapi.PreemptionDisruptionReason,
+	podutils.EvictionByEvictionAPI:  configapi.EvictionDisruptionReason,
+	podutils.DeletionByTaintManager: configapi.TaintManagerDeletionDisruptionReason,
+}
+
 // PodReconciler reconciles a LeaderWorkerSet object
 type PodReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
+	// WebhooksDisabled makes the reconciler do the mutations of the pod webhook.
+	WebhooksDisabled bool
+	// NodeNotReadyGracePeriod is how long the node of a pod can be NotReady before the pod
+	// is treated as failed by the restart policy, zero disables it.
+	NodeNotReadyGracePeriod time.Duration
+	// DisruptionPolicies configure how the group of a disrupted worker pod is recreated,
+	// the groups are recreated immediately by default.
+	DisruptionPolicies []configapi.DisruptionPolicy
+	// NamespaceSelector restricts the reconciler to the pods of the namespaces matching it.
+	NamespaceSelector labels.Selector
+	// ControllerOptions are the concurrency and the rate limiter of the controller.
+	ControllerOptions controller.Options
+
+	// apiReader reads the worker statefulsets missing from the cache.
+	apiReader client.Reader
+	// conflicts are the generations of the worker statefulsets whose conflicts were reported.
+	conflicts reportedConflicts
 }
 
 func NewPodReconciler(client client.Client, schema *runtime.Scheme, record record.EventRecorder) *PodReconciler {
@@ -63,19 +126,34 @@ func NewPodReconciler(client client.Client, schema *runtime.Scheme, record recor
 //+kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
 
-func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	var pod corev1.Pod
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The worker statefulset of a leader pod has the name of the pod.
+			r.conflicts.forget(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	log := ctrl.LoggerFrom(ctx).WithValues("pod", klog.KObj(&pod))
 	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, span := tracing.Tracer().Start(tracing.ContextWithGroup(ctx, &pod), "PodReconciler.Reconcile", trace.WithAttributes(
+		attribute.String("namespace", pod.Namespace),
+		attribute.String("pod", pod.Name),
+	))
+	defer span.End()
 
 	// get the leaderWorkerSet name
 	lwsName := pod.Labels[leaderworkerset.SetNameLabelKey]
 	if lwsName == "" {
 		return ctrl.Result{}, errors.New("leaderworkerset.sigs.k8s.io/name label is unexpected missing")
 	}
+	if r.WebhooksDisabled {
+		if err := r.mutatePod(ctx, &pod); err != nil {
+			log.Error(err, "Patching pod labels")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
 	if _, exist := pod.Labels[leaderworkerset.WorkerIndexLabelKey]; !exist {
 		return ctrl.Result{}, errors.New("leaderworkerset.sigs.k8s.io/worker-index label is unexpected missing")
 	}
@@ -85,21 +163,32 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		// If lws not found, it's mostly because deleted, ignore the error as Pods will be GCed finally.
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	leaderDeleted, err := r.handleRestartPolicy(ctx, pod, leaderWorkerSet)
+	leaderDeleted, requeueAfter, err := r.handleRestartPolicy(ctx, pod, leaderWorkerSet)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if leaderDeleted {
 		return ctrl.Result{}, nil
 	}
+	if requeueAfter > 0 {
+		// The pod is checked again once the grace period of its NotReady node is over,
+		// or once the deferred recreation of its group is due.
+		defer func() {
+			if err == nil && result.IsZero() {
+				result = ctrl.Result{RequeueAfter: requeueAfter}
+			}
+		}()
+	}
 
 	// worker pods' reconciliation is only done to handle restart policy
 	if !podutils.LeaderPod(pod) {
 		return ctrl.Result{}, nil
 	}
 
-	if leaderWorkerSet.Spec.NetworkConfig != nil && *leaderWorkerSet.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
-		if err := controllerutils.CreateHeadlessServiceIfNotExists(ctx, r.Client, r.Scheme, &leaderWorkerSet, pod.Name, map[string]string{leaderworkerset.SetNameLabelKey: leaderWorkerSet.Name, leaderworkerset.GroupIndexLabelKey: pod.Labels[leaderworkerset.GroupIndexLabelKey]}, &pod); err != nil {
+	// The subdomain of the group follows the revision of its leader pod, the per replica headless services
+	// of the groups which moved to the shared subdomain are deleted by the leaderworkerset controller.
+	if uniquePerReplicaSubdomain(&pod) {
+		if err := controllerutils.ReconcileHeadlessService(ctx, r.Client, r.Scheme, r.Record, &leaderWorkerSet, pod.Name, map[string]string{leaderworkerset.SetNameLabelKey: leaderWorkerSet.Name, leaderworkerset.GroupIndexLabelKey: pod.Labels[leaderworkerset.GroupIndexLabelKey]}, &pod); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
@@ -122,6 +211,49 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.V(2).Info("defer the creation of the worker statefulset because leader pod is not ready.")
 		return ctrl.Result{}, nil
 	}
+	// if exclusive placement is enabled but leader pod is not scheduled, don't create the worker sts
+	topologyKey := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+	exclusive := topologyKey != ""
+	var topologyValue string
+	if exclusive {
+		// check if the leader pod is scheduled.
+		if pod.Spec.NodeName == "" {
+			log.V(2).Info(fmt.Sprintf("Pod %q is not scheduled yet", pod.Name))
+			return ctrl.Result{}, nil
+		}
+		if topologyValue, err = r.topologyValueFromPod(ctx, &pod, topologyKey); err != nil {
+			log.Error(err, "getting topology from leader pod")
+			return ctrl.Result{}, err
+		}
+	}
+
+	workerSts, err := r.getWorkerStatefulSet(ctx, types.NamespacedName{Name: pod.Name, Namespace: leaderWorkerSet.Namespace})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.applyWorkerStatefulSet(ctx, pod, leaderWorkerSet, workerSts, topologyKey, topologyValue)
+}
+
+// getWorkerStatefulSet returns the worker statefulset, or nil if it doesn't exist. A statefulset missing from
+// the cache is looked up on the API server, the cache may not have observed its creation yet.
+func (r *PodReconciler) getWorkerStatefulSet(ctx context.Context, key types.NamespacedName) (*appsv1.StatefulSet, error) {
+	var workerSts appsv1.StatefulSet
+	err := r.Get(ctx, key, &workerSts)
+	if apierrors.IsNotFound(err) {
+		err = r.apiReader.Get(ctx, key, &workerSts)
+	}
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &workerSts, nil
+}
+
+// applyWorkerStatefulSet renders the worker statefulset of the leader pod from the revision of the leader pod,
+// and applies it when it differs from the existing worker statefulset, so that the drift of the fields owned by
+// the lws controller is reverted. The revision of a leader pod never changes, so re-applying the worker
+// statefulset doesn't roll the group.
+func (r *PodReconciler) applyWorkerStatefulSet(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet, workerSts *appsv1.StatefulSet, topologyKey, topologyValue string) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
 	revision, err := revisionutils.GetRevision(ctx, r.Client, &leaderWorkerSet, revisionutils.GetRevisionKey(&pod))
 	if err != nil {
 		log.Error(err, "Getting lws revisions")
@@ -129,24 +261,15 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 	if revision == nil {
 		log.V(2).Info(fmt.Sprintf("Revision has not been created yet, requeing reconciler for pod %s", pod.Name))
-		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+		// The pod is requeued with the backoff of the controller rate limiter.
+		return ctrl.Result{Requeue: true}, nil
 	}
-	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, leaderWorkerSet, revision)
+	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, leaderWorkerSet, revision, r.WebhooksDisabled)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-
-	// if exclusive placement is enabled but leader pod is not scheduled, don't create the worker sts
-	if topologyKey, found := leaderWorkerSet.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
-		// check if the leader pod is scheduled.
-		if pod.Spec.NodeName == "" {
-			log.V(2).Info(fmt.Sprintf("Pod %q is not scheduled yet", pod.Name))
-			return ctrl.Result{}, nil
-		}
-		if err := r.setNodeSelectorForWorkerPods(ctx, &pod, statefulSet, topologyKey); err != nil {
-			log.Error(err, "setting node selector for worker pods")
-			return ctrl.Result{}, err
-		}
+	if topologyKey != "" {
+		setNodeSelectorForWorkerPods(statefulSet, topologyKey, topologyValue)
 	}
 
 	if err := setControllerReferenceWithStatefulSet(&pod, statefulSet, r.Scheme); err != nil {
@@ -154,85 +277,414 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, nil
 	}
 
+	exists := workerSts != nil
+	var generation int64
+	if exists {
+		upToDate, err := workerStatefulSetUpToDate(workerSts, statefulSet)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if upToDate {
+			log.V(2).Info("Worker statefulset is up to date.")
+			return ctrl.Result{}, nil
+		}
+		generation = workerSts.Generation
+	}
+	if err := r.serverSideApply(ctx, &leaderWorkerSet, statefulSet, generation); err != nil {
+		if !exists {
+			r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, fmt.Sprintf("Failed to create worker statefulset for leader pod %s", pod.Name))
+		}
+		log.Error(err, "Using server side apply to update worker statefulset")
+		return ctrl.Result{}, err
+	}
+	if !exists {
+		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, GroupsProgressing, fmt.Sprintf("Created worker statefulset for leader pod %s", pod.Name))
+		metrics.WorkerStatefulSetCreated(time.Since(pod.CreationTimestamp.Time))
+	}
+	log.V(2).Info("Worker Reconcile completed.")
+	return ctrl.Result{}, nil
+}
+
+// serverSideApply creates or updates the worker statefulset with server side apply. The fields owned by
+// the lws controller which were changed by other managers are overridden, and reported in an event once
+// per generation of the worker statefulset, so that a manager which keeps changing them doesn't flood
+// the events of the leaderworkerset.
+func (r *PodReconciler) serverSideApply(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, statefulSet *appsapplyv1.StatefulSetApplyConfiguration, generation int64) error {
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
 	if err != nil {
-		return ctrl.Result{}, err
+		return err
 	}
 	workerStatefulSet := &unstructured.Unstructured{
 		Object: obj,
 	}
+	err = r.Patch(ctx, workerStatefulSet.DeepCopy(), client.Apply, client.FieldOwner(fieldManager))
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+	ctrl.LoggerFrom(ctx).Info("Overriding the conflicting fields of worker statefulset", "statefulset", workerStatefulSet.GetName(), "conflict", err.Error())
+	if r.conflicts.report(types.NamespacedName{Name: workerStatefulSet.GetName(), Namespace: workerStatefulSet.GetNamespace()}, generation) {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, FieldsConflict, fmt.Sprintf("Overriding the fields of worker statefulset %s changed by other managers", workerStatefulSet.GetName()))
+	}
+	return r.Patch(ctx, workerStatefulSet, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
 
-	var workerSts appsv1.StatefulSet
-	if err := r.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: leaderWorkerSet.Namespace}, &workerSts); err != nil {
-		if client.IgnoreNotFound(err) != nil {
-			return ctrl.Result{}, err
+// reportedConflicts remembers the generation of the worker statefulsets at their last reported conflict.
+type reportedConflicts struct {
+	sync.Mutex
+	generations map[types.NamespacedName]int64
+}
+
+// report returns whether a conflict on the generation of the worker statefulset is to be reported,
+// that is if no conflict was reported for this generation yet.
+func (c *reportedConflicts) report(sts types.NamespacedName, generation int64) bool {
+	c.Lock()
+	defer c.Unlock()
+	if reported, found := c.generations[sts]; found && reported == generation {
+		return false
+	}
+	if c.generations == nil {
+		c.generations = map[types.NamespacedName]int64{}
+	}
+	c.generations[sts] = generation
+	return true
+}
+
+// forget drops the generation of the worker statefulset, once its leader pod is deleted.
+func (c *reportedConflicts) forget(sts types.NamespacedName) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.generations, sts)
+}
+
+// workerStatefulSetUpToDate returns whether the spec, labels and annotations of the rendered worker statefulset
+// are set in the worker statefulset, in which case applying it changes nothing. The worker statefulset also has
+// the fields defaulted by the API server, and the labels and annotations of other managers, so the rendered
+// fields are looked up in it rather than compared to all of its fields.
+func workerStatefulSetUpToDate(workerSts *appsv1.StatefulSet, statefulSet *appsapplyv1.StatefulSetApplyConfiguration) (bool, error) {
+	rendered, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		return false, err
+	}
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workerSts)
+	if err != nil {
+		return false, err
+	}
+	for _, fields := range [][]string{{"spec"}, {"metadata", "labels"}, {"metadata", "annotations"}} {
+		renderedValue, _, _ := unstructured.NestedFieldNoCopy(rendered, fields...)
+		currentValue, _, _ := unstructured.NestedFieldNoCopy(current, fields...)
+		if !renderedValueSet(renderedValue, currentValue) {
+			return false, nil
 		}
-		if err = r.Create(ctx, workerStatefulSet); err != nil {
-			r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeWarning, FailedCreate, fmt.Sprintf("Failed to create worker statefulset for leader pod %s", pod.Name))
-			return ctrl.Result{}, client.IgnoreAlreadyExists(err)
+	}
+	return true, nil
+}
+
+// renderedValueSet returns whether the rendered value is set in the current one: the keys of the rendered maps
+// are set in the current maps, the rendered lists have as many items as the current lists with each item set in
+// the current one, and the other values are equal. An empty rendered value matches a missing current value.
+func renderedValueSet(rendered, current any) bool {
+	switch renderedValue := rendered.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		currentValue, ok := current.(map[string]any)
+		if !ok {
+			return current == nil && len(renderedValue) == 0
 		}
-		r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, GroupsProgressing, fmt.Sprintf("Created worker statefulset for leader pod %s", pod.Name))
+		for key, value := range renderedValue {
+			if !renderedValueSet(value, currentValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		currentValue, ok := current.([]any)
+		if !ok {
+			return current == nil && len(renderedValue) == 0
+		}
+		if len(renderedValue) != len(currentValue) {
+			return false
+		}
+		for i := range renderedValue {
+			if !renderedValueSet(renderedValue[i], currentValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(rendered, current)
 	}
-	log.V(2).Info("Worker Reconcile completed.")
-	return ctrl.Result{}, nil
 }
 
-func (r *PodReconciler) handleRestartPolicy(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, error) {
+// handleRestartPolicy recreates the group of the pod if the pod failed, it returns whether the leader pod
+// is deleted, and the time after which the pod has to be checked again: when the grace period of its NotReady
+// node is over, or when the deferred recreation of its group is due.
+func (r *PodReconciler) handleRestartPolicy(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
 	if leaderWorkerSet.Spec.LeaderWorkerTemplate.RestartPolicy != leaderworkerset.RecreateGroupOnPodRestart {
-		return false, nil
+		return false, 0, nil
 	}
-	// the leader pod will be deleted if the worker pod is deleted or any containes were restarted
-	if !podutils.ContainerRestarted(pod) && !podutils.PodDeleted(pod) {
-		return false, nil
+	nodeFailure, nodeGracePeriodLeft, err := r.nodeFailure(ctx, pod)
+	if err != nil {
+		return false, 0, err
 	}
+	// the leader pod will be deleted if the worker pod is deleted, any containes were restarted or its node failed
+	failed := podutils.ContainerRestarted(pod) || podutils.PodDeleted(pod) || nodeFailure != nil
 	var leader corev1.Pod
 	if !podutils.LeaderPod(pod) {
 		leaderPodName, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
 		if ordinal == -1 {
-			return false, fmt.Errorf("parsing pod name for pod %s", pod.Name)
+			return false, 0, fmt.Errorf("parsing pod name for pod %s", pod.Name)
 		}
 		if err := r.Get(ctx, types.NamespacedName{Name: leaderPodName, Namespace: pod.Namespace}, &leader); err != nil {
 			// If the error is not found, it is likely caused by the fact that the leader was deleted but the worker statefulset
 			// deletion hasn't deleted all the worker pods
-			return false, client.IgnoreNotFound(err)
+			return false, 0, client.IgnoreNotFound(err)
 		}
 		// Different revision key means that this pod will be deleted soon and alternative will be created with the matching key
 		if revisionutils.GetRevisionKey(&leader) != revisionutils.GetRevisionKey(&pod) {
-			return false, nil
+			return false, 0, nil
 		}
 	} else {
 		leader = pod
 	}
 	// if the leader pod is being deleted, we don't need to send deletion requests
 	if leader.DeletionTimestamp != nil {
-		return true, nil
+		return true, 0, nil
 	}
-	deletionOpt := metav1.DeletePropagationForeground
-	if err := r.Delete(ctx, &leader, &client.DeleteOptions{
-		PropagationPolicy: &deletionOpt,
+	if !failed {
+		leaderDeleted, recreationLeft, err := r.handleDeferredRecreation(ctx, leader, leaderWorkerSet)
+		if nodeGracePeriodLeft == 0 || (recreationLeft > 0 && recreationLeft < nodeGracePeriodLeft) {
+			return leaderDeleted, recreationLeft, err
+		}
+		return leaderDeleted, nodeGracePeriodLeft, err
+	}
+	// The container statuses of the failed pod are captured before the group is deleted with them.
+	failure := podutils.GetPodFailure(pod)
+	reason := metrics.PodDeletedReason
+	if podutils.ContainerRestarted(pod) {
+		reason = metrics.ContainerRestartedReason
+	}
+	eventAnnotations := map[string]string{}
+	if nodeFailure != nil && !podutils.PodDeleted(pod) && !podutils.ContainerRestarted(pod) {
+		failure = *nodeFailure
+		reason = metrics.NodeNotReadyReason
+		eventAnnotations[failedNodeEventAnnotationKey] = pod.Spec.NodeName
+	}
+	if podutils.DisruptionCondition(pod) != nil {
+		reason = metrics.PodDisruptedReason
+	}
+	groupIndex := leader.Labels[leaderworkerset.GroupIndexLabelKey]
+	message := fmt.Sprintf("Pod %s of group %s failed: %s", pod.Name, groupIndex, failure.Message)
+	eventAnnotations[leaderworkerset.GroupIndexLabelKey] = groupIndex
+	eventAnnotations[failedPodEventAnnotationKey] = pod.Name
+	eventAnnotations[failureReasonEventAnnotationKey] = failure.Reason
+	// The disruption of a worker pod may defer the recreation of its group, the recreation
+	// of a disrupted leader pod can't be deferred as the worker statefulset is deleted with it.
+	if policy := r.disruptionPolicy(pod); policy != nil && policy.Action != configapi.RecreateDisruptionAction && !podutils.LeaderPod(pod) {
+		if _, deferred := leader.Annotations[recreationDeferredReasonAnnotationKey]; deferred {
+			return false, 0, nil
+		}
+		if err := r.setDegradedCondition(ctx, &leaderWorkerSet, failure.Reason, message); err != nil {
+			return false, 0, err
+		}
+		if err := r.deferRecreation(ctx, &leader, podutils.DisruptionCondition(pod).Reason); err != nil {
+			return false, 0, err
+		}
+		deferral := "waiting for capacity to recreate the group"
+		if policy.Action == configapi.DelayDisruptionAction {
+			deferral = fmt.Sprintf("delaying the recreation of the group by %s", policy.Delay.Duration)
+		}
+		r.Record.AnnotatedEventf(&leaderWorkerSet, eventAnnotations, corev1.EventTypeWarning, GroupRecreationDeferred, fmt.Sprintf("%s, %s", message, deferral))
+		return false, 0, nil
+	}
+	// The condition is set before the leader pod is deleted, a failed patch is retried on the next
+	// reconcile which wouldn't get here anymore once the leader pod is terminating.
+	if err := r.setDegradedCondition(ctx, &leaderWorkerSet, failure.Reason, message); err != nil {
+		return false, 0, err
+	}
+	if err := recreateGroup(ctx, r.Client, &leaderWorkerSet, &leader, reason); err != nil {
+		return false, 0, err
+	}
+	// The failure is only reported once the group is actually recreated.
+	r.Record.AnnotatedEventf(&leaderWorkerSet, eventAnnotations, corev1.EventTypeWarning, GroupRecreated, fmt.Sprintf("%s, deleted leader pod %s to recreate the group", message, leader.Name))
+	return true, 0, nil
+}
+
+// disruptionPolicy returns the policy applying to the disruption of the pod, nil if the pod isn't disrupted
+// or if there is no policy for its disruption.
+func (r *PodReconciler) disruptionPolicy(pod corev1.Pod) *configapi.DisruptionPolicy {
+	condition := podutils.DisruptionCondition(pod)
+	if condition == nil {
+		return nil
+	}
+	return r.disruptionPolicyForReason(condition.Reason)
+}
+
+func (r *PodReconciler) disruptionPolicyForReason(disruptionTargetReason string) *configapi.DisruptionPolicy {
+	reason, ok := disruptionReasons[disruptionTargetReason]
+	if !ok {
+		return nil
+	}
+	for i := range r.DisruptionPolicies {
+		if r.DisruptionPolicies[i].Reason == reason {
+			return &r.DisruptionPolicies[i]
+		}
+	}
+	return nil
+}
+
+// deferRecreation records on the leader pod that the recreation of its group is deferred,
+// and when, the deferred recreation is then handled by handleDeferredRecreation.
+func (r *PodReconciler) deferRecreation(ctx context.Context, leader *corev1.Pod, disruptionTargetReason string) error {
+	patch := client.MergeFrom(leader.DeepCopy())
+	if leader.Annotations == nil {
+		leader.Annotations = map[string]string{}
+	}
+	leader.Annotations[recreationDeferredReasonAnnotationKey] = disruptionTargetReason
+	leader.Annotations[recreationDeferredSinceAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+	return r.Patch(ctx, leader, patch)
+}
+
+// handleDeferredRecreation recreates the group of the leader pod once its deferred recreation is due: after the
+// delay of the Delay action, or once all the pods of the group are scheduled for the WaitForCapacity action.
+// It returns whether the leader pod is deleted, and the time left before the recreation is due with the Delay action.
+func (r *PodReconciler) handleDeferredRecreation(ctx context.Context, leader corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	disruptionTargetReason, deferred := leader.Annotations[recreationDeferredReasonAnnotationKey]
+	if !deferred {
+		return false, 0, nil
+	}
+	// The group is recreated right away if the policy was removed since the recreation was deferred.
+	if policy := r.disruptionPolicyForReason(disruptionTargetReason); policy != nil {
+		switch policy.Action {
+		case configapi.DelayDisruptionAction:
+			if since, err := time.Parse(time.RFC3339, leader.Annotations[recreationDeferredSinceAnnotationKey]); err == nil {
+				if left := policy.Delay.Duration - time.Since(since); left > 0 {
+					return false, left, nil
+				}
+			}
+		case configapi.WaitForCapacityDisruptionAction:
+			scheduled, err := r.groupScheduled(ctx, leader)
+			if err != nil || !scheduled {
+				return false, 0, err
+			}
+		}
+	}
+	if err := recreateGroup(ctx, r.Client, &leaderWorkerSet, &leader, metrics.PodDisruptedReason); err != nil {
+		return false, 0, err
+	}
+	groupIndex := leader.Labels[leaderworkerset.GroupIndexLabelKey]
+	eventAnnotations := map[string]string{
+		leaderworkerset.GroupIndexLabelKey: groupIndex,
+		failureReasonEventAnnotationKey:    disruptionTargetReason,
+	}
+	r.Record.AnnotatedEventf(&leaderWorkerSet, eventAnnotations, corev1.EventTypeWarning, GroupRecreated, fmt.Sprintf("Group %s was disrupted (%s), deleted leader pod %s to recreate the group", groupIndex, disruptionTargetReason, leader.Name))
+	return true, 0, nil
+}
+
+// groupScheduled returns whether all the pods of the group of the leader pod exist and are scheduled.
+func (r *PodReconciler) groupScheduled(ctx context.Context, leader corev1.Pod) (bool, error) {
+	size, err := strconv.Atoi(leader.Annotations[leaderworkerset.SizeAnnotationKey])
+	if err != nil {
+		return false, fmt.Errorf("parsing the size of the group of leader pod %s: %w", leader.Name, err)
+	}
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(leader.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:    leader.Labels[leaderworkerset.SetNameLabelKey],
+		leaderworkerset.GroupIndexLabelKey: leader.Labels[leaderworkerset.GroupIndexLabelKey],
 	}); err != nil {
 		return false, err
 	}
-	r.Record.Eventf(&leaderWorkerSet, corev1.EventTypeNormal, "RecreateGroupOnPodRestart", fmt.Sprintf("Worker pod %s failed, deleted leader pod %s to recreate group %s", pod.Name, leader.Name, leader.Labels[leaderworkerset.GroupIndexLabelKey]))
-	return true, nil
+	scheduled := 0
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && podutils.PodScheduled(pod) {
+			scheduled++
+		}
+	}
+	return scheduled >= size, nil
 }
 
-func (r *PodReconciler) setNodeSelectorForWorkerPods(ctx context.Context, pod *corev1.Pod, sts *appsapplyv1.StatefulSetApplyConfiguration, topologyKey string) error {
+// nodeFailure returns the failure of the pod if its node has been NotReady or unreachable for longer than
+// the grace period. Otherwise, it returns the time left before the pod is treated as failed, zero if the node is ready.
+func (r *PodReconciler) nodeFailure(ctx context.Context, pod corev1.Pod) (*podutils.PodFailure, time.Duration, error) {
+	if r.NodeNotReadyGracePeriod == 0 || pod.Spec.NodeName == "" || !features.Enabled(features.NodeFailureGroupRecreation) {
+		return nil, 0, nil
+	}
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node); err != nil {
+		// The pods of a deleted node are deleted by the pod garbage collector.
+		return nil, 0, client.IgnoreNotFound(err)
+	}
+	readyCondition := nodeReadyCondition(&node)
+	if readyCondition == nil || readyCondition.Status == corev1.ConditionTrue {
+		return nil, 0, nil
+	}
+	notReadyFor := time.Since(readyCondition.LastTransitionTime.Time)
+	if notReadyFor < r.NodeNotReadyGracePeriod {
+		return nil, r.NodeNotReadyGracePeriod - notReadyFor, nil
+	}
+	state := "NotReady"
+	if readyCondition.Status == corev1.ConditionUnknown {
+		state = "unreachable"
+	}
+	return &podutils.PodFailure{
+		Reason:  nodeNotReadyReason,
+		Message: fmt.Sprintf("node %s has been %s for more than %s", node.Name, state, r.NodeNotReadyGracePeriod),
+	}, 0, nil
+}
 
-	log := ctrl.LoggerFrom(ctx)
-	topologyValue, err := r.topologyValueFromPod(ctx, pod, topologyKey)
-	if err != nil {
-		log.Error(err, "getting topology from leader pod")
+func nodeReadyCondition(node *corev1.Node) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// recreateGroup deletes the leader pod of the group, the worker statefulset it owns is deleted with it
+// and the leader statefulset recreates the whole group. The foreground propagation makes sure the workers
+// are gone before the leader pod is recreated.
+func recreateGroup(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet, leader *corev1.Pod, reason metrics.GroupRecreationReason) error {
+	deletionOpt := metav1.DeletePropagationForeground
+	if err := c.Delete(ctx, leader, &client.DeleteOptions{
+		PropagationPolicy: &deletionOpt,
+	}); err != nil {
 		return err
 	}
+	metrics.GroupRecreated(client.ObjectKeyFromObject(lws), reason)
+	return nil
+}
+
+// setDegradedCondition sets the Degraded condition of the leaderworkerset for the failure of a group.
+// The optimistic lock keeps the conditions set by the leaderworkerset controller in the meantime.
+func (r *PodReconciler) setDegradedCondition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, reason, message string) error {
+	patch := client.MergeFromWithOptions(lws.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	meta.SetStatusCondition(&lws.Status.Conditions, metav1.Condition{
+		Type:    string(leaderworkerset.LeaderWorkerSetDegraded),
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	return r.Status().Patch(ctx, lws, patch)
+}
 
+// setNodeSelectorForWorkerPods sets the node selector of the worker pods to the topology of the leader pod.
+func setNodeSelectorForWorkerPods(sts *appsapplyv1.StatefulSetApplyConfiguration, topologyKey, topologyValue string) {
+	if sts.Spec == nil {
+		sts.WithSpec(appsapplyv1.StatefulSetSpec())
+	}
+	if sts.Spec.Template == nil {
+		sts.Spec.WithTemplate(coreapplyv1.PodTemplateSpec())
+	}
+	if sts.Spec.Template.Spec == nil {
+		sts.Spec.Template.WithSpec(coreapplyv1.PodSpec())
+	}
 	// set node selector for worker pods, if worker pods already scheduled to different topology value
 	// the following applying logic will automatically update it to match the leader pods, so we don't
 	// need to verify if they have the same topology value
 	sts.Spec.Template.Spec.WithNodeSelector(map[string]string{
 		topologyKey: topologyValue,
 	})
-	return nil
 }
 
 func (r *PodReconciler) topologyValueFromPod(ctx context.Context, pod *corev1.Pod, topologyKey string) (string, error) {
@@ -279,13 +731,37 @@ func setControllerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1
 	return nil
 }
 
-// constructWorkerStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
-func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws leaderworkerset.LeaderWorkerSet, currentRevision *appsv1.ControllerRevision) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
+// constructWorkerStatefulSetApplyConfiguration constructs the applied configuration for the worker StatefulSet,
+// webhooksDisabled renders the mutations of the pod webhook into the pod template.
+func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws leaderworkerset.LeaderWorkerSet, currentRevision *appsv1.ControllerRevision, webhooksDisabled bool) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	currentLws, err := revisionutils.ApplyRevision(&lws, currentRevision)
 	if err != nil {
 		return nil, err
 	}
 	podTemplateSpec := *currentLws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+	podAnnotations := make(map[string]string)
+	// Everything rendered into the template comes from the revision or the leader pod, so that changes to the
+	// live leaderworkerset don't roll the existing groups.
+	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*currentLws.Spec.LeaderWorkerTemplate.Size))
+	podAnnotations[leaderworkerset.LeaderPodNameAnnotationKey] = leaderPod.Name
+	if leaderPod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = leaderPod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+	}
+	if subGroupPolicy := currentLws.Spec.LeaderWorkerTemplate.SubGroupPolicy; subGroupPolicy != nil && subGroupPolicy.SubGroupSize != nil {
+		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*subGroupPolicy.SubGroupSize))
+		if leaderPod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
+			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = leaderPod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
+		}
+	}
+	if traceContext := leaderPod.Annotations[leaderworkerset.TraceContextAnnotationKey]; traceContext != "" {
+		podAnnotations[leaderworkerset.TraceContextAnnotationKey] = traceContext
+	}
+	acceleratorutils.AddTPUAnnotations(leaderPod, podAnnotations)
+	if webhooksDisabled {
+		if err := renderWorkerPodTemplate(&leaderPod, currentLws, &podTemplateSpec, podAnnotations); err != nil {
+			return nil, err
+		}
+	}
 	// construct pod template spec configuration
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTemplateSpec)
 	if err != nil {
@@ -309,29 +785,16 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 	}
 
 	podTemplateApplyConfiguration.WithLabels(labelMap)
-	podAnnotations := make(map[string]string)
-	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
-	podAnnotations[leaderworkerset.LeaderPodNameAnnotationKey] = leaderPod.Name
-	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
-		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
-	}
-	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
-		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
-			podAnnotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
-		}
-	}
-	acceleratorutils.AddTPUAnnotations(leaderPod, podAnnotations)
 	podTemplateApplyConfiguration.WithAnnotations(podAnnotations)
-	serviceName := leaderPod.Name
-	if lws.Spec.NetworkConfig == nil || *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainShared {
-		serviceName = lws.Name
+	serviceName := lws.Name
+	if uniquePerReplicaSubdomain(&leaderPod) {
+		serviceName = leaderPod.Name
 	}
 	// construct statefulset apply configuration
 	statefulSetConfig := appsapplyv1.StatefulSet(leaderPod.Name, leaderPod.Namespace).
 		WithSpec(appsapplyv1.StatefulSetSpec().
 			WithServiceName(serviceName).
-			WithReplicas(*lws.Spec.LeaderWorkerTemplate.Size - 1).
+			WithReplicas(*currentLws.Spec.LeaderWorkerTemplate.Size - 1).
 			WithPodManagementPolicy(appsv1.ParallelPodManagement).
 			WithTemplate(&podTemplateApplyConfiguration).
 			WithOrdinals(appsapplyv1.StatefulSetOrdinals().WithStart(1)).
@@ -341,8 +804,17 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 	return statefulSetConfig, nil
 }
 
+// uniquePerReplicaSubdomain returns true if the group of the leader pod is addressed through its own headless
+// service, that is if the revision of the leader pod has the UniquePerReplica subdomain policy. Without the pod
+// webhook the leader pod can't join the subdomain of its group, it is addressed through the shared headless
+// service, which then only selects the leader pods.
+func uniquePerReplicaSubdomain(leaderPod *corev1.Pod) bool {
+	return leaderPod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] == string(leaderworkerset.SubdomainUniquePerReplica)
+}
+
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	r.apiReader = mgr.GetAPIReader()
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).
 		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
 			if pod, ok := object.(*corev1.Pod); ok {
@@ -353,6 +825,55 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				_, exist := statefulSet.Labels[leaderworkerset.SetNameLabelKey]
 				return exist
 			}
-			return false
-		})).Owns(&appsv1.StatefulSet{}).Complete(r)
+			if service, ok := object.(*corev1.Service); ok {
+				_, exist := service.Labels[leaderworkerset.SetNameLabelKey]
+				return exist
+			}
+			_, isNode := object.(*corev1.Node)
+			return isNode
+		})).Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		WithOptions(r.ControllerOptions)
+	if r.NamespaceSelector != nil {
+		b = b.WithEventFilter(namespaceSelectorPredicate(mgr.GetClient(), r.NamespaceSelector))
+	}
+	if r.NodeNotReadyGracePeriod > 0 && features.Enabled(features.NodeFailureGroupRecreation) {
+		b = b.Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.podsOnNode), builder.WithPredicates(nodeReadinessChanged()))
+	}
+	return b.Complete(r)
+}
+
+// podsOnNode returns the requests of the leaderworkerset pods running on the node.
+func (r *PodReconciler) podsOnNode(ctx context.Context, node client.Object) []reconcile.Request {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.MatchingFields{podNodeNameKey: node.GetName()}, client.HasLabels{leaderworkerset.SetNameLabelKey}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Listing the pods of node", "node", node.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&pod)})
+	}
+	return requests
+}
+
+// nodeReadinessChanged filters the node updates changing the status of the Ready condition.
+func nodeReadinessChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+			newNode, newOk := e.ObjectNew.(*corev1.Node)
+			if !oldOk || !newOk {
+				return false
+			}
+			oldCondition, newCondition := nodeReadyCondition(oldNode), nodeReadyCondition(newNode)
+			if oldCondition == nil || newCondition == nil {
+				return oldCondition != newCondition
+			}
+			return oldCondition.Status != newCondition.Status
+		},
+	}
 }
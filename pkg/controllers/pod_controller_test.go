@@ -19,17 +19,27 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	"sigs.k8s.io/lws/test/wrappers"
 )
@@ -49,11 +59,9 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 		pod                   *corev1.Pod
 		lws                   *leaderworkerset.LeaderWorkerSet
 		wantStatefulSetConfig *appsapplyv1.StatefulSetApplyConfiguration
-		revision              *appsv1.ControllerRevision
 	}{
 		{
-			name:     "1 replica, size 1, exclusive placement disabled",
-			revision: updateRevision,
+			name: "1 replica, size 1, exclusive placement disabled",
 			pod: &corev1.Pod{
 				ObjectMeta: v1.ObjectMeta{
 					Name:      "test-sample",
@@ -126,8 +134,7 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 			},
 		},
 		{
-			name:     "1 replica, size 2, exclusive placement enabled",
-			revision: updateRevision,
+			name: "1 replica, size 2, exclusive placement enabled",
 			pod: &corev1.Pod{
 				ObjectMeta: v1.ObjectMeta{
 					Name:      "test-sample",
@@ -139,6 +146,9 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
 						leaderworkerset.RevisionKey:             updateRevisionKey,
 					},
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+					},
 				},
 			},
 			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
@@ -203,8 +213,7 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 			},
 		},
 		{
-			name:     "1 replica, size 2, subgroupsize 2, exclusive placement enabled",
-			revision: updateRevision,
+			name: "1 replica, size 2, subgroupsize 2, exclusive placement enabled",
 			pod: &corev1.Pod{
 				ObjectMeta: v1.ObjectMeta{
 					Name:      "test-sample",
@@ -216,6 +225,9 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
 						leaderworkerset.RevisionKey:             updateRevisionKey,
 					},
+					Annotations: map[string]string{
+						leaderworkerset.SubGroupExclusiveKeyAnnotationKey: "topologyKey",
+					},
 				},
 			},
 			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
@@ -280,11 +292,90 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "1 replica, size 2, exclusive placement enabled after the leader pod was created",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.WorkerIndexLabelKey:     "0",
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      "1",
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             updateRevisionKey,
+					},
+				},
+			},
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+				Annotation(map[string]string{
+					"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+				}).Size(2).Obj(),
+			wantStatefulSetConfig: &appsapplyv1.StatefulSetApplyConfiguration{
+				TypeMetaApplyConfiguration: metaapplyv1.TypeMetaApplyConfiguration{
+					Kind:       ptr.To[string]("StatefulSet"),
+					APIVersion: ptr.To[string]("apps/v1"),
+				},
+				ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+					Name:      ptr.To[string]("test-sample"),
+					Namespace: ptr.To[string]("default"),
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:         "test-sample",
+						leaderworkerset.GroupIndexLabelKey:      "1",
+						leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						leaderworkerset.RevisionKey:             updateRevisionKey,
+					},
+				},
+				Spec: &appsapplyv1.StatefulSetSpecApplyConfiguration{
+					Replicas: ptr.To[int32](1),
+					Selector: &metaapplyv1.LabelSelectorApplyConfiguration{
+						MatchLabels: map[string]string{
+							leaderworkerset.SetNameLabelKey:         "test-sample",
+							leaderworkerset.GroupIndexLabelKey:      "1",
+							leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+						},
+					},
+					Template: &coreapplyv1.PodTemplateSpecApplyConfiguration{
+						ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+							Labels: map[string]string{
+								leaderworkerset.SetNameLabelKey:         "test-sample",
+								leaderworkerset.GroupIndexLabelKey:      "1",
+								leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+								leaderworkerset.RevisionKey:             updateRevisionKey,
+							},
+							Annotations: map[string]string{
+								"leaderworkerset.sigs.k8s.io/size":        "2",
+								"leaderworkerset.sigs.k8s.io/leader-name": "test-sample",
+							},
+						},
+						Spec: &coreapplyv1.PodSpecApplyConfiguration{
+							Containers: []coreapplyv1.ContainerApplyConfiguration{
+								{
+									Name:      ptr.To[string]("leader"),
+									Image:     ptr.To[string]("nginx:1.14.2"),
+									Ports:     []coreapplyv1.ContainerPortApplyConfiguration{{ContainerPort: ptr.To[int32](8080), Protocol: ptr.To[corev1.Protocol](corev1.ProtocolTCP)}},
+									Resources: &coreapplyv1.ResourceRequirementsApplyConfiguration{},
+								},
+							},
+						},
+					},
+					Ordinals:            &appsapplyv1.StatefulSetOrdinalsApplyConfiguration{Start: ptr.To[int32](1)},
+					ServiceName:         ptr.To[string]("test-sample"),
+					PodManagementPolicy: ptr.To[appsv1.PodManagementPolicyType](appsv1.ParallelPodManagement),
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			statefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(*tc.pod, *tc.lws, tc.revision)
+			revision, err := revisionutils.NewRevision(context.TODO(), client, tc.lws, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			statefulSetConfig, err := constructWorkerStatefulSetApplyConfiguration(*tc.pod, *tc.lws, revision, false)
 			if err != nil {
 				t.Errorf("failed with error %s", err.Error())
 			}
@@ -294,3 +385,380 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkerStatefulSetUpToDate(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Replica(1).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Size(2).Obj()
+	revision, err := revisionutils.NewRevision(context.TODO(), client, lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaderPod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      "0",
+				leaderworkerset.GroupUniqueHashLabelKey: "test-key",
+				leaderworkerset.RevisionKey:             revisionutils.GetRevisionKey(revision),
+			},
+		},
+	}
+	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws, revision, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// defaultedStatefulSet is the rendered worker statefulset with some of the fields defaulted by the API server.
+	defaultedStatefulSet := func() *appsv1.StatefulSet {
+		var sts appsv1.StatefulSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rendered, &sts); err != nil {
+			t.Fatal(err)
+		}
+		sts.Generation = 1
+		sts.Spec.RevisionHistoryLimit = ptr.To[int32](10)
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
+		sts.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+		sts.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		sts.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+		return &sts
+	}
+
+	tests := []struct {
+		name         string
+		update       func(*appsv1.StatefulSet)
+		wantUpToDate bool
+	}{
+		{
+			name:         "defaulted fields",
+			wantUpToDate: true,
+		},
+		{
+			name: "labels and annotations of other managers",
+			update: func(sts *appsv1.StatefulSet) {
+				sts.Labels["other"] = "label"
+				sts.Annotations = map[string]string{"other": "annotation"}
+			},
+			wantUpToDate: true,
+		},
+		{
+			name: "changed label",
+			update: func(sts *appsv1.StatefulSet) {
+				sts.Labels[leaderworkerset.GroupUniqueHashLabelKey] = "other-key"
+			},
+		},
+		{
+			name: "changed replicas",
+			update: func(sts *appsv1.StatefulSet) {
+				sts.Spec.Replicas = ptr.To[int32](2)
+			},
+		},
+		{
+			name: "changed image",
+			update: func(sts *appsv1.StatefulSet) {
+				sts.Spec.Template.Spec.Containers[0].Image = "changed"
+			},
+		},
+		{
+			name: "removed pod annotation",
+			update: func(sts *appsv1.StatefulSet) {
+				delete(sts.Spec.Template.Annotations, leaderworkerset.SizeAnnotationKey)
+			},
+		},
+		{
+			name: "added container",
+			update: func(sts *appsv1.StatefulSet) {
+				sts.Spec.Template.Spec.Containers = append(sts.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar", Image: "busybox"})
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sts := defaultedStatefulSet()
+			if tc.update != nil {
+				tc.update(sts)
+			}
+			upToDate, err := workerStatefulSetUpToDate(sts, statefulSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upToDate != tc.wantUpToDate {
+				t.Errorf("unexpected result, want %t, got %t", tc.wantUpToDate, upToDate)
+			}
+		})
+	}
+}
+
+func TestReportedConflicts(t *testing.T) {
+	var conflicts reportedConflicts
+	sts := types.NamespacedName{Name: "test-sample-0", Namespace: "default"}
+	other := types.NamespacedName{Name: "test-sample-1", Namespace: "default"}
+	for _, step := range []struct {
+		sts        types.NamespacedName
+		generation int64
+		forget     bool
+		want       bool
+	}{
+		{sts: sts, generation: 1, want: true},
+		{sts: sts, generation: 1, want: false},
+		{sts: other, generation: 1, want: true},
+		{sts: sts, generation: 2, want: true},
+		{sts: sts, generation: 2, want: false},
+		{sts: sts, forget: true},
+		{sts: sts, generation: 2, want: true},
+	} {
+		if step.forget {
+			conflicts.forget(step.sts)
+			continue
+		}
+		if got := conflicts.report(step.sts, step.generation); got != step.want {
+			t.Errorf("unexpected report of the conflict on generation %d of %s, want %t, got %t", step.generation, step.sts, step.want, got)
+		}
+	}
+}
+
+func TestNodeFailure(t *testing.T) {
+	now := time.Now()
+	node := func(status corev1.ConditionStatus, since time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: "node-a"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             status,
+				LastTransitionTime: v1.NewTime(now.Add(-since)),
+			}}},
+		}
+	}
+	pod := corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-a"}}
+
+	tests := []struct {
+		name            string
+		gracePeriod     time.Duration
+		featureDisabled bool
+		pod             corev1.Pod
+		node            *corev1.Node
+		wantFailure     *podutils.PodFailure
+		wantRequeueLeft bool
+	}{
+		{
+			name:        "ready node",
+			gracePeriod: time.Minute,
+			pod:         pod,
+			node:        node(corev1.ConditionTrue, time.Hour),
+		},
+		{
+			name:            "NotReady node within the grace period",
+			gracePeriod:     time.Minute,
+			pod:             pod,
+			node:            node(corev1.ConditionFalse, 10*time.Second),
+			wantRequeueLeft: true,
+		},
+		{
+			name:        "NotReady node past the grace period",
+			gracePeriod: time.Minute,
+			pod:         pod,
+			node:        node(corev1.ConditionFalse, 2*time.Minute),
+			wantFailure: &podutils.PodFailure{Reason: "NodeNotReady", Message: "node node-a has been NotReady for more than 1m0s"},
+		},
+		{
+			name:        "unreachable node past the grace period",
+			gracePeriod: time.Minute,
+			pod:         pod,
+			node:        node(corev1.ConditionUnknown, 2*time.Minute),
+			wantFailure: &podutils.PodFailure{Reason: "NodeNotReady", Message: "node node-a has been unreachable for more than 1m0s"},
+		},
+		{
+			name:        "disabled grace period",
+			pod:         pod,
+			node:        node(corev1.ConditionFalse, time.Hour),
+			gracePeriod: 0,
+		},
+		{
+			name:            "disabled NodeFailureGroupRecreation feature",
+			gracePeriod:     time.Minute,
+			featureDisabled: true,
+			pod:             pod,
+			node:            node(corev1.ConditionFalse, time.Hour),
+		},
+		{
+			name:        "unscheduled pod",
+			gracePeriod: time.Minute,
+			node:        node(corev1.ConditionFalse, time.Hour),
+		},
+		{
+			name:        "deleted node",
+			gracePeriod: time.Minute,
+			pod:         corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-b"}},
+			node:        node(corev1.ConditionFalse, time.Hour),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.featureDisabled {
+				features.SetFeatureGateDuringTest(t, features.NodeFailureGroupRecreation, false)
+			}
+			r := &PodReconciler{
+				Client:                  fake.NewClientBuilder().WithObjects(tc.node).Build(),
+				NodeNotReadyGracePeriod: tc.gracePeriod,
+			}
+			failure, requeueLeft, err := r.nodeFailure(context.TODO(), tc.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantFailure, failure); diff != "" {
+				t.Errorf("unexpected failure (-want +got):\n%s", diff)
+			}
+			if (requeueLeft > 0) != tc.wantRequeueLeft || requeueLeft > tc.gracePeriod {
+				t.Errorf("unexpected grace period left %s", requeueLeft)
+			}
+		})
+	}
+}
+
+func TestNodeReadinessChanged(t *testing.T) {
+	node := func(status corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}}}
+	}
+	tests := []struct {
+		name    string
+		oldNode *corev1.Node
+		newNode *corev1.Node
+		want    bool
+	}{
+		{
+			name:    "node becoming NotReady",
+			oldNode: node(corev1.ConditionTrue),
+			newNode: node(corev1.ConditionFalse),
+			want:    true,
+		},
+		{
+			name:    "NotReady node becoming unreachable",
+			oldNode: node(corev1.ConditionFalse),
+			newNode: node(corev1.ConditionUnknown),
+			want:    true,
+		},
+		{
+			name:    "heartbeat of a ready node",
+			oldNode: node(corev1.ConditionTrue),
+			newNode: node(corev1.ConditionTrue),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nodeReadinessChanged().Update(event.UpdateEvent{ObjectOld: tc.oldNode, ObjectNew: tc.newNode}); got != tc.want {
+				t.Errorf("unexpected result, want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleDeferredRecreation(t *testing.T) {
+	now := time.Now()
+	makePod := func(name, workerIndex string, scheduled bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  "0",
+					leaderworkerset.WorkerIndexLabelKey: workerIndex,
+				},
+				Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "3"},
+			},
+		}
+		if scheduled {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}}
+		}
+		return pod
+	}
+	deferredLeader := func(reason string, since time.Time) *corev1.Pod {
+		leader := makePod("test-sample-0", "0", true)
+		leader.Annotations[recreationDeferredReasonAnnotationKey] = reason
+		leader.Annotations[recreationDeferredSinceAnnotationKey] = since.UTC().Format(time.RFC3339)
+		return leader
+	}
+	policies := []configapi.DisruptionPolicy{
+		{Reason: configapi.PreemptionDisruptionReason, Action: configapi.WaitForCapacityDisruptionAction},
+		{Reason: configapi.EvictionDisruptionReason, Action: configapi.DelayDisruptionAction, Delay: &v1.Duration{Duration: time.Minute}},
+	}
+
+	tests := []struct {
+		name              string
+		leader            *corev1.Pod
+		workers           []*corev1.Pod
+		wantLeaderDeleted bool
+		wantRequeue       bool
+	}{
+		{
+			name:   "recreation not deferred",
+			leader: makePod("test-sample-0", "0", true),
+		},
+		{
+			name:        "delay not over",
+			leader:      deferredLeader(podutils.EvictionByEvictionAPI, now.Add(-10*time.Second)),
+			wantRequeue: true,
+		},
+		{
+			name:              "delay over",
+			leader:            deferredLeader(podutils.EvictionByEvictionAPI, now.Add(-2*time.Minute)),
+			wantLeaderDeleted: true,
+		},
+		{
+			name:    "waiting for capacity",
+			leader:  deferredLeader(podutils.PreemptionByScheduler, now),
+			workers: []*corev1.Pod{makePod("test-sample-0-1", "1", true), makePod("test-sample-0-2", "2", false)},
+		},
+		{
+			name:    "waiting for the replacement of the disrupted pod",
+			leader:  deferredLeader(podutils.PreemptionByScheduler, now),
+			workers: []*corev1.Pod{makePod("test-sample-0-1", "1", true)},
+		},
+		{
+			name:              "capacity available",
+			leader:            deferredLeader(podutils.PreemptionByScheduler, now),
+			workers:           []*corev1.Pod{makePod("test-sample-0-1", "1", true), makePod("test-sample-0-2", "2", true)},
+			wantLeaderDeleted: true,
+		},
+		{
+			name:              "policy removed",
+			leader:            deferredLeader(podutils.DeletionByTaintManager, now),
+			wantLeaderDeleted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithObjects(tc.leader)
+			for _, worker := range tc.workers {
+				builder.WithObjects(worker)
+			}
+			r := &PodReconciler{
+				Client:             builder.Build(),
+				Record:             record.NewFakeRecorder(10),
+				DisruptionPolicies: policies,
+			}
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Obj()
+			leaderDeleted, requeueAfter, err := r.handleDeferredRecreation(context.TODO(), *tc.leader, *lws)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if leaderDeleted != tc.wantLeaderDeleted {
+				t.Errorf("unexpected leader deletion, want %t, got %t", tc.wantLeaderDeleted, leaderDeleted)
+			}
+			if (requeueAfter > 0) != tc.wantRequeue {
+				t.Errorf("unexpected requeue after %s", requeueAfter)
+			}
+			var leader corev1.Pod
+			err = r.Get(context.TODO(), client.ObjectKeyFromObject(tc.leader), &leader)
+			if gotDeleted := apierrors.IsNotFound(err); gotDeleted != tc.wantLeaderDeleted {
+				t.Errorf("unexpected leader pod, want deleted %t, got error %v", tc.wantLeaderDeleted, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/tracing"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
+)
+
+// When the webhooks are disabled, the mutations of the pod webhook are done by the controllers instead:
+// everything that is known when the StatefulSets are constructed is rendered into their pod templates,
+// and the values that depend on the pod ordinal are patched onto the pods as labels and annotations,
+// which the environment variables read through the downward API. The pods are created with a scheduling
+// gate, which is removed by the same patch, so that the pods are neither scheduled nor started before.
+//
+// A few settings can't be expressed this way, see webhooklessUnsupportedSettings.
+
+const (
+	// UnsupportedWithoutWebhooks Event reason used when a leaderworkerset relies on a setting
+	// that needs the pod webhook.
+	UnsupportedWithoutWebhooks = "UnsupportedWithoutWebhooks"
+
+	// podMutationSchedulingGate holds the pods which weren't mutated by the pod webhook until the pod controller
+	// patched them.
+	podMutationSchedulingGate = "leaderworkerset.sigs.k8s.io/pod-mutation"
+)
+
+// renderLeaderPodTemplate renders the pod webhook mutations into the pod template of the leader StatefulSet.
+// The leader pods are addressed through the shared headless service, since the StatefulSet controller sets
+// the subdomain of every pod to the service name of the StatefulSet.
+func renderLeaderPodTemplate(lws *leaderworkerset.LeaderWorkerSet, template *corev1.PodTemplateSpec, annotations map[string]string) error {
+	size := *lws.Spec.LeaderWorkerTemplate.Size
+	leaderNameRef := fmt.Sprintf("$(%s)", leaderworkerset.LwsLeaderName)
+
+	if topologyKey, found := annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
+		setLeaderExclusiveAntiAffinity(&template.Spec, topologyKey)
+	}
+	if acceleratorutils.PodRequestsTPUs(template.Spec) {
+		acceleratorutils.AddTPUTemplateVariables(&template.Spec)
+	}
+
+	leaderName := corev1.EnvVar{
+		Name: leaderworkerset.LwsLeaderName,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		},
+	}
+	podutils.AddLWSTemplateVariables(&template.Spec, leaderName, fmt.Sprintf("%s.%s.%s", leaderNameRef, lws.Name, lws.Namespace), size)
+	addSchedulingGate(&template.Spec)
+	return nil
+}
+
+// renderWorkerPodTemplate renders the pod webhook mutations into the pod template of the worker StatefulSet
+// of the given leader pod.
+func renderWorkerPodTemplate(leaderPod *corev1.Pod, lws *leaderworkerset.LeaderWorkerSet, template *corev1.PodTemplateSpec, annotations map[string]string) error {
+	size := *lws.Spec.LeaderWorkerTemplate.Size
+
+	if topologyKey, found := annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
+		pod := &corev1.Pod{Spec: template.Spec}
+		podutils.SetExclusiveAffinities(pod, leaderPod.Labels[leaderworkerset.GroupUniqueHashLabelKey], topologyKey, leaderworkerset.GroupUniqueHashLabelKey)
+		template.Spec = pod.Spec
+	}
+	if acceleratorutils.PodRequestsTPUs(template.Spec) {
+		acceleratorutils.AddTPUTemplateVariables(&template.Spec)
+	}
+
+	leaderName := corev1.EnvVar{
+		Name:  leaderworkerset.LwsLeaderName,
+		Value: leaderPod.Name,
+	}
+	podutils.AddLWSTemplateVariables(&template.Spec, leaderName, fmt.Sprintf("%s.%s.%s", leaderPod.Name, lws.Name, lws.Namespace), size)
+	addSchedulingGate(&template.Spec)
+	return nil
+}
+
+// addSchedulingGate adds the pod mutation scheduling gate to the pod spec if it is missing.
+func addSchedulingGate(spec *corev1.PodSpec) {
+	if !slices.ContainsFunc(spec.SchedulingGates, isPodMutationSchedulingGate) {
+		spec.SchedulingGates = append(spec.SchedulingGates, corev1.PodSchedulingGate{Name: podMutationSchedulingGate})
+	}
+}
+
+func isPodMutationSchedulingGate(gate corev1.PodSchedulingGate) bool {
+	return gate.Name == podMutationSchedulingGate
+}
+
+// setLeaderExclusiveAntiAffinity keeps the leader pod away from the topology domains of the other groups, with
+// the same outcome as the affinities of SetExclusiveAffinities. The unique key of the group is only known once
+// the leader pod is created, so the leader avoids the other leader pods, and the pods of the other groups are kept
+// away by their own anti-affinities, which are symmetric: the group labels are patched onto the leader pod before
+// its scheduling gate is removed. The workers follow their leader through the node selector of the worker
+// StatefulSet and the exclusive affinities rendered into its template.
+func setLeaderExclusiveAntiAffinity(spec *corev1.PodSpec, topologyKey string) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.PodAntiAffinity == nil {
+		spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	for _, term := range spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey == topologyKey {
+			return
+		}
+	}
+	spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      leaderworkerset.GroupUniqueHashLabelKey,
+					Operator: metav1.LabelSelectorOpExists,
+				},
+				{
+					Key:      leaderworkerset.WorkerIndexLabelKey,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{"0"},
+				},
+			}},
+			TopologyKey: topologyKey,
+		})
+}
+
+// webhooklessUnsupportedSettings returns the settings of the leaderworkerset that can't be honored without the
+// pod webhook. The exclusive placement of the subgroups needs pod affinities keyed on the subgroup of every pod,
+// which neither the pod template of a StatefulSet nor a patch of the pod can express, pod affinities being immutable.
+func webhooklessUnsupportedSettings(lws *leaderworkerset.LeaderWorkerSet) []string {
+	var settings []string
+	if _, found := lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; found {
+		settings = append(settings, fmt.Sprintf("annotation %s", leaderworkerset.SubGroupExclusiveKeyAnnotationKey))
+	}
+	return settings
+}
+
+// setUnsupportedSettingsCondition reports the settings ignored without the pod webhook in the UnsupportedWithoutWebhooks
+// condition of the leaderworkerset, and returns whether the condition changed.
+func setUnsupportedSettingsCondition(lws *leaderworkerset.LeaderWorkerSet, settings []string) bool {
+	conditionType := string(leaderworkerset.LeaderWorkerSetUnsupportedWithoutWebhooks)
+	if len(settings) == 0 {
+		if meta.FindStatusCondition(lws.Status.Conditions, conditionType) == nil {
+			return false
+		}
+		return meta.SetStatusCondition(&lws.Status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "AllSettingsSupported",
+			Message: "All settings are supported without webhooks",
+		})
+	}
+	return meta.SetStatusCondition(&lws.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  UnsupportedWithoutWebhooks,
+		Message: unsupportedSettingsMessage(strings.Join(settings, "; ")),
+	})
+}
+
+// unsupportedSettingsMessage returns the message reporting that the leaderworkerset isn't reconciled
+// because of the given settings.
+func unsupportedSettingsMessage(settings string) string {
+	return fmt.Sprintf("Not creating the groups, %s is not supported when webhooks are disabled", settings)
+}
+
+// mutatePod patches the labels that depend on the pod ordinal onto a pod that wasn't mutated by the pod webhook,
+// the trace context of the group onto a new leader pod, and the annotations read by the TPU environment variables
+// onto a pod which is still held by the pod mutation scheduling gate, removing the gate.
+func (r *PodReconciler) mutatePod(ctx context.Context, pod *corev1.Pod) error {
+	labels, err := podMutationLabels(pod)
+	if err != nil {
+		return err
+	}
+	gated := slices.ContainsFunc(pod.Spec.SchedulingGates, isPodMutationSchedulingGate)
+	if len(labels) == 0 && !gated {
+		return nil
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	for key, value := range labels {
+		pod.Labels[key] = value
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	if _, newLeader := labels[leaderworkerset.GroupUniqueHashLabelKey]; newLeader {
+		if traceContext := tracing.NewGroupTraceContext(ctx); traceContext != "" {
+			pod.Annotations[leaderworkerset.TraceContextAnnotationKey] = traceContext
+		}
+	}
+	if gated {
+		annotations, err := podMutationTPUAnnotations(pod)
+		if err != nil {
+			return err
+		}
+		for key, value := range annotations {
+			pod.Annotations[key] = value
+		}
+		pod.Spec.SchedulingGates = slices.DeleteFunc(pod.Spec.SchedulingGates, isPodMutationSchedulingGate)
+	}
+	return r.Patch(ctx, pod, patch)
+}
+
+// podMutationTPUAnnotations returns the annotations read by the TPU environment variables of the pod, with the
+// values the pod webhook would have injected. The group labels must be set on the pod. The leader pods are always
+// addressed through the shared headless service, since their subdomain is the service name of the leader
+// StatefulSet, while the workers are addressed through the headless service of their group with the
+// UniquePerReplica subdomain policy.
+func podMutationTPUAnnotations(pod *corev1.Pod) (map[string]string, error) {
+	if !acceleratorutils.PodRequestsTPUs(pod.Spec) {
+		return nil, nil
+	}
+	size, err := strconv.Atoi(pod.Annotations[leaderworkerset.SizeAnnotationKey])
+	if err != nil {
+		return nil, err
+	}
+	lwsName := pod.Labels[leaderworkerset.SetNameLabelKey]
+	leaderName := pod.Annotations[leaderworkerset.LeaderPodNameAnnotationKey]
+	tpuPod := pod.DeepCopy()
+	if podutils.LeaderPod(*pod) {
+		leaderName = pod.Name
+		if uniquePerReplicaSubdomain(pod) {
+			tpuPod.Spec.Subdomain = pod.Name
+		}
+	}
+	annotations, err := acceleratorutils.TPUAnnotations(tpuPod, size)
+	if err != nil || annotations == nil {
+		return annotations, err
+	}
+	if tpuPod.Spec.Subdomain != lwsName {
+		acceleratorutils.SetTPUWorkerHostName(annotations, fmt.Sprintf("%s.%s", leaderName, tpuPod.Spec.Subdomain), fmt.Sprintf("%s.%s", leaderName, lwsName))
+	}
+	return annotations, nil
+}
+
+// podMutationLabels returns the labels the pod webhook would have added to the pod, and the pod is missing.
+func podMutationLabels(pod *corev1.Pod) (map[string]string, error) {
+	_, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	if ordinal == -1 {
+		return nil, fmt.Errorf("parsing pod ordinal for pod %s", pod.Name)
+	}
+	labels := map[string]string{}
+	if _, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]; !found {
+		// Only the pods of the leader StatefulSet have the worker index in their template.
+		labels[leaderworkerset.WorkerIndexLabelKey] = fmt.Sprint(ordinal)
+	}
+	leader := pod.Labels[leaderworkerset.WorkerIndexLabelKey] == "0"
+	if leader {
+		if _, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]; !found {
+			labels[leaderworkerset.GroupIndexLabelKey] = fmt.Sprint(ordinal)
+		}
+		if _, found := pod.Labels[leaderworkerset.GroupUniqueHashLabelKey]; !found {
+			labels[leaderworkerset.GroupUniqueHashLabelKey] = podutils.GenGroupUniqueKey(pod.Namespace, pod.Name)
+		}
+	}
+
+	subGroupSize, found := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
+	if !found || pod.Labels[leaderworkerset.SubGroupIndexLabelKey] != "" {
+		return labels, nil
+	}
+	if leader {
+		// The leader pod always lands on SubGroup 0.
+		labels[leaderworkerset.SubGroupIndexLabelKey] = "0"
+		labels[leaderworkerset.SubGroupUniqueHashLabelKey] = podutils.GenGroupUniqueKey(pod.Name, "0")
+		return labels, nil
+	}
+	subGroupSizeInt, err := strconv.Atoi(subGroupSize)
+	if err != nil {
+		return nil, err
+	}
+	podCount, err := strconv.Atoi(pod.Annotations[leaderworkerset.SizeAnnotationKey])
+	if err != nil {
+		return nil, err
+	}
+	subGroupIndex := podutils.GetSubGroupIndex(podCount, subGroupSizeInt, ordinal)
+	labels[leaderworkerset.SubGroupIndexLabelKey] = subGroupIndex
+	labels[leaderworkerset.SubGroupUniqueHashLabelKey] = podutils.GenGroupUniqueKey(pod.Annotations[leaderworkerset.LeaderPodNameAnnotationKey], subGroupIndex)
+	return labels, nil
+}
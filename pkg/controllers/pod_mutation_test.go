@@ -0,0 +1,542 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+func tpuEnv(name, annotation string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name:      name,
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['leaderworkerset.sigs.k8s.io/" + annotation + "']"}},
+	}
+}
+
+func TestRenderLeaderPodTemplate(t *testing.T) {
+	leaderNameEnv := corev1.EnvVar{
+		Name:      leaderworkerset.LwsLeaderName,
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+	}
+	tests := []struct {
+		name             string
+		lws              *leaderworkerset.LeaderWorkerSet
+		annotations      map[string]string
+		wantEnv          []corev1.EnvVar
+		wantAntiAffinity *corev1.PodAntiAffinity
+	}{
+		{
+			name: "leader address and group size",
+			lws:  wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(2).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj(),
+			wantEnv: []corev1.EnvVar{
+				leaderNameEnv,
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "$(LWS_LEADER_NAME).test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "2"},
+			},
+		},
+		{
+			name:        "exclusive placement",
+			lws:         wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(2).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj(),
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "topologyKey"},
+			wantEnv: []corev1.EnvVar{
+				leaderNameEnv,
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "$(LWS_LEADER_NAME).test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "2"},
+			},
+			wantAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      leaderworkerset.GroupUniqueHashLabelKey,
+							Operator: metav1.LabelSelectorOpExists,
+						},
+						{
+							Key:      leaderworkerset.WorkerIndexLabelKey,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"0"},
+						},
+					}},
+					TopologyKey: "topologyKey",
+				}},
+			},
+		},
+		{
+			name: "leader requesting TPUs",
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(3).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).Obj(),
+			wantEnv: []corev1.EnvVar{
+				leaderNameEnv,
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "$(LWS_LEADER_NAME).test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "3"},
+				tpuEnv("TPU_WORKER_HOSTNAMES", "tpu-worker-hostnames"),
+				tpuEnv("TPU_WORKER_ID", "tpu-worker-id"),
+				tpuEnv("TPU_NAME", "tpu-name"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			template := tc.lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+			if tc.lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+				template = tc.lws.Spec.LeaderWorkerTemplate.LeaderTemplate.DeepCopy()
+			}
+			if err := renderLeaderPodTemplate(tc.lws, template, tc.annotations); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]corev1.PodSchedulingGate{{Name: podMutationSchedulingGate}}, template.Spec.SchedulingGates); diff != "" {
+				t.Errorf("unexpected scheduling gates (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantEnv, template.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("unexpected env (-want, +got): %s", diff)
+			}
+			var gotAntiAffinity *corev1.PodAntiAffinity
+			if template.Spec.Affinity != nil {
+				gotAntiAffinity = template.Spec.Affinity.PodAntiAffinity
+			}
+			if diff := cmp.Diff(tc.wantAntiAffinity, gotAntiAffinity); diff != "" {
+				t.Errorf("unexpected anti affinity (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderWorkerPodTemplate(t *testing.T) {
+	leaderPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:      "1",
+				leaderworkerset.GroupUniqueHashLabelKey: "group-key",
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		lws          *leaderworkerset.LeaderWorkerSet
+		annotations  map[string]string
+		wantEnv      []corev1.EnvVar
+		wantAffinity bool
+	}{
+		{
+			name: "leader address and group size",
+			lws:  wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(2).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj(),
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsLeaderName, Value: "test-sample-1"},
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-1.test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "2"},
+			},
+		},
+		{
+			name:        "exclusive placement",
+			lws:         wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(2).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).Obj(),
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "topologyKey"},
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsLeaderName, Value: "test-sample-1"},
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-1.test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "2"},
+			},
+			wantAffinity: true,
+		},
+		{
+			name:        "leader and workers requesting TPUs",
+			lws:         wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(3).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).Obj(),
+			annotations: map[string]string{acceleratorutils.LeaderRequestsTPUsAnnotationKey: "true"},
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsLeaderName, Value: "test-sample-1"},
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-1.test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "3"},
+				tpuEnv("TPU_WORKER_HOSTNAMES", "tpu-worker-hostnames"),
+				tpuEnv("TPU_WORKER_ID", "tpu-worker-id"),
+				tpuEnv("TPU_NAME", "tpu-name"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			template := tc.lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+			if err := renderWorkerPodTemplate(leaderPod, tc.lws, template, tc.annotations); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]corev1.PodSchedulingGate{{Name: podMutationSchedulingGate}}, template.Spec.SchedulingGates); diff != "" {
+				t.Errorf("unexpected scheduling gates (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantEnv, template.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("unexpected env (-want, +got): %s", diff)
+			}
+			gotAffinity := template.Spec.Affinity != nil && template.Spec.Affinity.PodAffinity != nil
+			if gotAffinity != tc.wantAffinity {
+				t.Errorf("expected affinity %t, got %t", tc.wantAffinity, gotAffinity)
+			}
+		})
+	}
+}
+
+func TestPodMutationLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		wantLabels  map[string]string
+		expectError bool
+	}{
+		{
+			name: "leader pod",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sample-1",
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+			}},
+			wantLabels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:      "1",
+				leaderworkerset.GroupUniqueHashLabelKey: podutils.GenGroupUniqueKey("default", "test-sample-1"),
+			},
+		},
+		{
+			name: "leader pod with subgroups",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-sample-1",
+				Namespace:   "default",
+				Labels:      map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+				Annotations: map[string]string{leaderworkerset.SubGroupSizeAnnotationKey: "2"},
+			}},
+			wantLabels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:         "1",
+				leaderworkerset.GroupUniqueHashLabelKey:    podutils.GenGroupUniqueKey("default", "test-sample-1"),
+				leaderworkerset.SubGroupIndexLabelKey:      "0",
+				leaderworkerset.SubGroupUniqueHashLabelKey: podutils.GenGroupUniqueKey("test-sample-1", "0"),
+			},
+		},
+		{
+			name: "mutated leader pod",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sample-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.WorkerIndexLabelKey:     "0",
+					leaderworkerset.GroupIndexLabelKey:      "1",
+					leaderworkerset.GroupUniqueHashLabelKey: "group-key",
+				},
+			}},
+			wantLabels: map[string]string{},
+		},
+		{
+			name: "worker pod with subgroups",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sample-1-3",
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.GroupIndexLabelKey: "1"},
+				Annotations: map[string]string{
+					leaderworkerset.SizeAnnotationKey:          "4",
+					leaderworkerset.SubGroupSizeAnnotationKey:  "2",
+					leaderworkerset.LeaderPodNameAnnotationKey: "test-sample-1",
+				},
+			}},
+			wantLabels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:        "3",
+				leaderworkerset.SubGroupIndexLabelKey:      "1",
+				leaderworkerset.SubGroupUniqueHashLabelKey: podutils.GenGroupUniqueKey("test-sample-1", "1"),
+			},
+		},
+		{
+			name: "pod name without ordinal",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-sample",
+				Namespace: "default",
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			labels, err := podMutationLabels(tc.pod)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("expected error %t, got %v", tc.expectError, err)
+			}
+			if diff := cmp.Diff(tc.wantLabels, labels); diff != "" {
+				t.Errorf("unexpected labels (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestWebhooklessUnsupportedSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		lws          *leaderworkerset.LeaderWorkerSet
+		wantSettings int
+	}{
+		{
+			name: "supported settings",
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(4).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpec()).SubGroupSize(2).SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica).
+				ExclusivePlacement().Obj(),
+		},
+		{
+			name: "subgroup exclusive placement",
+			lws: wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Size(4).WorkerTemplateSpec(wrappers.MakeWorkerPodSpec()).
+				SubGroupSize(2).Annotation(map[string]string{leaderworkerset.SubGroupExclusiveKeyAnnotationKey: "topologyKey"}).Obj(),
+			wantSettings: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := webhooklessUnsupportedSettings(tc.lws)
+			if len(settings) != tc.wantSettings {
+				t.Errorf("expected %d unsupported settings, got %v", tc.wantSettings, settings)
+			}
+		})
+	}
+}
+
+func TestPodMutationTPUAnnotations(t *testing.T) {
+	tpuPodSpec := func(subdomain string) corev1.PodSpec {
+		spec := wrappers.MakeWorkerPodSpecWithTPUResource()
+		acceleratorutils.AddTPUTemplateVariables(&spec)
+		spec.Subdomain = subdomain
+		return spec
+	}
+	tests := []struct {
+		name            string
+		pod             *corev1.Pod
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "leader pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sample-1",
+					Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+					Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "3"},
+				},
+				Spec: tpuPodSpec("test-sample"),
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1.test-sample,test-sample-1-1.test-sample,test-sample-1-2.test-sample",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "0",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+		{
+			name: "worker pod without a TPU leader",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-sample-1-2",
+					Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "2"},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:          "3",
+						leaderworkerset.LeaderPodNameAnnotationKey: "test-sample-1",
+					},
+				},
+				Spec: tpuPodSpec("test-sample"),
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1-1.test-sample,test-sample-1-2.test-sample",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "1",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+		{
+			name: "worker pod with subgroups",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-sample-1-3",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:       "test-sample",
+						leaderworkerset.WorkerIndexLabelKey:   "3",
+						leaderworkerset.SubGroupIndexLabelKey: "1",
+					},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:                "4",
+						leaderworkerset.SubGroupSizeAnnotationKey:        "2",
+						leaderworkerset.LeaderPodNameAnnotationKey:       "test-sample-1",
+						acceleratorutils.LeaderRequestsTPUsAnnotationKey: "true",
+					},
+				},
+				Spec: tpuPodSpec("test-sample"),
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1-2.test-sample,test-sample-1-3.test-sample",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "1",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+		{
+			name: "leader pod with unique subdomain per replica",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-sample-1",
+					Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:            "3",
+						leaderworkerset.SubdomainPolicyAnnotationKey: string(leaderworkerset.SubdomainUniquePerReplica),
+					},
+				},
+				Spec: tpuPodSpec("test-sample"),
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1.test-sample,test-sample-1-1.test-sample-1,test-sample-1-2.test-sample-1",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "0",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+		{
+			name: "worker pod with unique subdomain per replica",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-sample-1-1",
+					Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "1"},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:                "3",
+						leaderworkerset.LeaderPodNameAnnotationKey:       "test-sample-1",
+						acceleratorutils.LeaderRequestsTPUsAnnotationKey: "true",
+					},
+				},
+				Spec: tpuPodSpec("test-sample-1"),
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1.test-sample,test-sample-1-1.test-sample-1,test-sample-1-2.test-sample-1",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "1",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations, err := podMutationTPUAnnotations(tc.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMutatePod(t *testing.T) {
+	spec := wrappers.MakeLeaderPodSpecWithTPUResource()
+	acceleratorutils.AddTPUTemplateVariables(&spec)
+	spec.Subdomain = "test-sample"
+	spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: "other"}, {Name: podMutationSchedulingGate}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-sample-1",
+			Namespace:   "default",
+			Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+			Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "2"},
+		},
+		Spec: spec,
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(pod).Build()
+	r := &PodReconciler{Client: k8sClient, WebhooksDisabled: true}
+	if err := r.mutatePod(context.TODO(), pod.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	var got corev1.Pod
+	if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Labels[leaderworkerset.GroupUniqueHashLabelKey] == "" {
+		t.Errorf("expected the group unique key label to be patched, got labels %v", got.Labels)
+	}
+	if got.Annotations["leaderworkerset.sigs.k8s.io/tpu-worker-hostnames"] != "test-sample-1.test-sample,test-sample-1-1.test-sample" {
+		t.Errorf("unexpected TPU worker hostnames annotation, got annotations %v", got.Annotations)
+	}
+	if diff := cmp.Diff([]corev1.PodSchedulingGate{{Name: "other"}}, got.Spec.SchedulingGates); diff != "" {
+		t.Errorf("unexpected scheduling gates (-want, +got): %s", diff)
+	}
+}
+
+func TestSetUnsupportedSettingsCondition(t *testing.T) {
+	unsupported := metav1.Condition{
+		Type:    string(leaderworkerset.LeaderWorkerSetUnsupportedWithoutWebhooks),
+		Status:  metav1.ConditionTrue,
+		Reason:  UnsupportedWithoutWebhooks,
+		Message: unsupportedSettingsMessage("annotation a; annotation b"),
+	}
+	tests := []struct {
+		name        string
+		conditions  []metav1.Condition
+		settings    []string
+		wantChanged bool
+		wantStatus  metav1.ConditionStatus
+	}{
+		{
+			name: "all settings supported",
+		},
+		{
+			name:        "settings ignored",
+			settings:    []string{"annotation a", "annotation b"},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionTrue,
+		},
+		{
+			name:       "settings already reported",
+			conditions: []metav1.Condition{unsupported},
+			settings:   []string{"annotation a", "annotation b"},
+			wantStatus: metav1.ConditionTrue,
+		},
+		{
+			name:        "other settings ignored",
+			conditions:  []metav1.Condition{unsupported},
+			settings:    []string{"annotation a"},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionTrue,
+		},
+		{
+			name:        "settings no longer ignored",
+			conditions:  []metav1.Condition{unsupported},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := wrappers.BuildBasicLeaderWorkerSet("test-sample", "default").Obj()
+			lws.Status.Conditions = tc.conditions
+			if changed := setUnsupportedSettingsCondition(lws, tc.settings); changed != tc.wantChanged {
+				t.Errorf("expected the condition changed %t, got %t", tc.wantChanged, changed)
+			}
+			var status metav1.ConditionStatus
+			if condition := meta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetUnsupportedWithoutWebhooks)); condition != nil {
+				status = condition.Status
+			}
+			if status != tc.wantStatus {
+				t.Errorf("expected the condition status %q, got %q", tc.wantStatus, status)
+			}
+		})
+	}
+}
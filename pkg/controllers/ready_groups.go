@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/tracing"
+)
+
+// readyGroups remembers the leader pods of the groups of each leaderworkerset, to report each group
+// becoming ready only once, and to end the span of the traced groups deleted before becoming ready.
+type readyGroups struct {
+	sync.Mutex
+	groups map[types.NamespacedName]groupLeaders
+}
+
+// groupLeaders are the leader pods of the groups of a leaderworkerset at the previous call.
+type groupLeaders struct {
+	// all are the leader pods of every group, ready are the ones of the ready groups.
+	all, ready sets.Set[types.UID]
+	// pending are the leader pods of the traced groups which weren't ready since they were created.
+	pending map[types.UID]corev1.Pod
+}
+
+func newReadyGroups() *readyGroups {
+	return &readyGroups{groups: map[types.NamespacedName]groupLeaders{}}
+}
+
+// update returns the leader pods of the groups which weren't ready at the previous call for the
+// leaderworkerset, and the leader pods of the traced groups deleted, or being deleted, before they
+// were ever ready. Nothing is returned at the first call, e.g. after a restart of the manager, since
+// the groups may have been ready for long.
+func (g *readyGroups) update(lws types.NamespacedName, leaders, readyLeaders []corev1.Pod) (newlyReady, deleted []corev1.Pod) {
+	g.Lock()
+	defer g.Unlock()
+	previous, seen := g.groups[lws]
+	current := groupLeaders{all: sets.New[types.UID](), ready: sets.New[types.UID](), pending: map[types.UID]corev1.Pod{}}
+	for _, leader := range readyLeaders {
+		current.ready.Insert(leader.UID)
+		if seen && !previous.ready.Has(leader.UID) {
+			newlyReady = append(newlyReady, leader)
+		}
+	}
+	for _, leader := range leaders {
+		current.all.Insert(leader.UID)
+		if !seen || current.ready.Has(leader.UID) || leader.Annotations[leaderworkerset.TraceContextAnnotationKey] == "" {
+			continue
+		}
+		// The leader pods known at the previous call but not pending were ready since, or were
+		// already reported as deleted.
+		if _, pending := previous.pending[leader.UID]; !pending && previous.all.Has(leader.UID) {
+			continue
+		}
+		if leader.DeletionTimestamp != nil {
+			deleted = append(deleted, leader)
+			continue
+		}
+		current.pending[leader.UID] = corev1.Pod{ObjectMeta: leader.ObjectMeta}
+	}
+	for uid, leader := range previous.pending {
+		if !current.all.Has(uid) {
+			deleted = append(deleted, leader)
+		}
+	}
+	g.groups[lws] = current
+	return newlyReady, deleted
+}
+
+// forget removes the leader pods of the leaderworkerset, it returns the leader pods of its
+// traced groups which were never ready.
+func (g *readyGroups) forget(lws types.NamespacedName) []corev1.Pod {
+	g.Lock()
+	defer g.Unlock()
+	var pending []corev1.Pod
+	for _, leader := range g.groups[lws].pending {
+		pending = append(pending, leader)
+	}
+	delete(g.groups, lws)
+	return pending
+}
+
+// reportReadyGroups reports the groups which became ready in the metrics, and ends the span of
+// their lifecycle when they are traced. The span of the traced groups deleted before becoming
+// ready is ended with an error status.
+func (r *LeaderWorkerSetReconciler) reportReadyGroups(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leaders, readyLeaders []corev1.Pod, workerStatefulSetsCreation map[string]time.Time) {
+	log := ctrl.LoggerFrom(ctx)
+	now := time.Now()
+	newlyReady, deleted := r.readyGroups.update(client.ObjectKeyFromObject(lws), leaders, readyLeaders)
+	for _, leader := range newlyReady {
+		metrics.GroupReady(now.Sub(leader.CreationTimestamp.Time))
+		if leader.Annotations[leaderworkerset.TraceContextAnnotationKey] == "" {
+			continue
+		}
+		workersScheduled, err := r.workersScheduledTime(ctx, &leader)
+		if err != nil {
+			// The span is still worth reporting without the scheduling of the workers.
+			log.Error(err, "Fetching the worker pods of the group", "leader", leader.Name)
+		}
+		tracing.EndGroupSpan(ctx, &leader, workerStatefulSetsCreation[leader.Name], workersScheduled, now)
+	}
+	for _, leader := range deleted {
+		workersScheduled, err := r.workersScheduledTime(ctx, &leader)
+		if err != nil {
+			log.Error(err, "Fetching the worker pods of the group", "leader", leader.Name)
+		}
+		failDeletedGroupSpan(ctx, &leader, workerStatefulSetsCreation[leader.Name], workersScheduled, now)
+	}
+}
+
+// failDeletedGroupSpan ends the span of a group deleted before becoming ready, at the deletion
+// of its leader pod when it's known.
+func failDeletedGroupSpan(ctx context.Context, leader *corev1.Pod, workerStatefulSetCreated, workersScheduled, now time.Time) {
+	deleted := now
+	if leader.DeletionTimestamp != nil {
+		deleted = leader.DeletionTimestamp.Time
+	}
+	tracing.FailGroupSpan(ctx, leader, workerStatefulSetCreated, workersScheduled, deleted,
+		fmt.Sprintf("Group %s was deleted before becoming ready", leader.Labels[leaderworkerset.GroupIndexLabelKey]))
+}
+
+// workersScheduledTime returns when the last worker pod of the group of the leader pod was scheduled.
+func (r *LeaderWorkerSetReconciler) workersScheduledTime(ctx context.Context, leader *corev1.Pod) (time.Time, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(leader.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:    leader.Labels[leaderworkerset.SetNameLabelKey],
+		leaderworkerset.GroupIndexLabelKey: leader.Labels[leaderworkerset.GroupIndexLabelKey],
+	}); err != nil {
+		return time.Time{}, err
+	}
+	var scheduled time.Time
+	for _, pod := range pods.Items {
+		if pod.Name == leader.Name {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue && condition.LastTransitionTime.After(scheduled) {
+				scheduled = condition.LastTransitionTime.Time
+			}
+		}
+	}
+	return scheduled, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestReadyGroups(t *testing.T) {
+	lws := types.NamespacedName{Namespace: "default", Name: "test-sample"}
+	leader := func(uid string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: uid, UID: types.UID(uid)}}
+	}
+	groups := newReadyGroups()
+
+	steps := []struct {
+		name         string
+		forget       bool
+		readyLeaders []corev1.Pod
+		want         []string
+	}{
+		{
+			name:         "groups ready when the leaderworkerset is first seen are not reported",
+			readyLeaders: []corev1.Pod{leader("a")},
+		},
+		{
+			name:         "a group becoming ready is reported",
+			readyLeaders: []corev1.Pod{leader("a"), leader("b")},
+			want:         []string{"b"},
+		},
+		{
+			name:         "a group staying ready is not reported again",
+			readyLeaders: []corev1.Pod{leader("a"), leader("b")},
+		},
+		{
+			name:         "a group becoming unready is not reported",
+			readyLeaders: []corev1.Pod{leader("b")},
+		},
+		{
+			name:         "a recreated group is reported",
+			readyLeaders: []corev1.Pod{leader("a2"), leader("b")},
+			want:         []string{"a2"},
+		},
+		{
+			name:         "a forgotten leaderworkerset is seen anew",
+			forget:       true,
+			readyLeaders: []corev1.Pod{leader("a2"), leader("b"), leader("c")},
+		},
+	}
+	for _, step := range steps {
+		if step.forget {
+			groups.forget(lws)
+		}
+		var got []string
+		newlyReady, _ := groups.update(lws, step.readyLeaders, step.readyLeaders)
+		for _, pod := range newlyReady {
+			got = append(got, pod.Name)
+		}
+		if diff := cmp.Diff(step.want, got); diff != "" {
+			t.Errorf("%s: unexpected newly ready groups (-want +got):\n%s", step.name, diff)
+		}
+	}
+}
+
+func TestReadyGroupsDeletedBeforeReady(t *testing.T) {
+	lws := types.NamespacedName{Namespace: "default", Name: "test-sample"}
+	leader := func(uid string, deleting bool) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        uid,
+			UID:         types.UID(uid),
+			Annotations: map[string]string{leaderworkerset.TraceContextAnnotationKey: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		}}
+		if deleting {
+			pod.DeletionTimestamp = ptr.To(metav1.Now())
+		}
+		return pod
+	}
+	groups := newReadyGroups()
+
+	steps := []struct {
+		name         string
+		forget       bool
+		leaders      []corev1.Pod
+		readyLeaders []corev1.Pod
+		wantDeleted  []string
+		wantPending  []string
+	}{
+		{
+			name:    "groups not ready when the leaderworkerset is first seen are not tracked",
+			leaders: []corev1.Pod{leader("a", false)},
+		},
+		{
+			name:    "a group not ready when the leaderworkerset was first seen is not reported",
+			leaders: []corev1.Pod{leader("a", true)},
+		},
+		{
+			name:    "a new group not ready yet is not reported",
+			leaders: []corev1.Pod{leader("b", false), leader("c", false), leader("d", false)},
+		},
+		{
+			name:         "a group becoming ready is not reported as deleted",
+			leaders:      []corev1.Pod{leader("b", false), leader("c", true), leader("d", false)},
+			readyLeaders: []corev1.Pod{leader("b", false)},
+			wantDeleted:  []string{"c"},
+		},
+		{
+			name:        "groups deleted after becoming ready or reported already are not reported",
+			leaders:     []corev1.Pod{leader("b", true), leader("c", true), leader("d", false)},
+			wantDeleted: nil,
+		},
+		{
+			name:        "a group gone before becoming ready is reported",
+			leaders:     []corev1.Pod{leader("e", false)},
+			wantDeleted: []string{"d"},
+		},
+		{
+			name:        "the groups not ready yet are returned when the leaderworkerset is forgotten",
+			forget:      true,
+			wantPending: []string{"e"},
+		},
+	}
+	for _, step := range steps {
+		if step.forget {
+			var pending []string
+			for _, pod := range groups.forget(lws) {
+				pending = append(pending, pod.Name)
+			}
+			if diff := cmp.Diff(step.wantPending, pending); diff != "" {
+				t.Errorf("%s: unexpected pending groups (-want +got):\n%s", step.name, diff)
+			}
+			continue
+		}
+		var got []string
+		_, deleted := groups.update(lws, step.leaders, step.readyLeaders)
+		for _, pod := range deleted {
+			got = append(got, pod.Name)
+		}
+		if diff := cmp.Diff(step.wantDeleted, got); diff != "" {
+			t.Errorf("%s: unexpected deleted groups (-want +got):\n%s", step.name, diff)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates of the controller, which ship new behaviors
+// disabled until they are ready. A feature goes through the stages of the Kubernetes features:
+//   - Alpha: disabled by default, it may be buggy and may change or be removed without notice.
+//   - Beta: enabled by default, it is well tested and its behavior won't be dropped, but its
+//     details may still change.
+//   - GA: always enabled, the gate is locked to true and is removed after two releases,
+//     along with the code of the disabled behavior.
+//
+// The gates are set with the --feature-gates flag or with the featureGates of the configuration.
+package features
+
+import (
+	"testing"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+)
+
+const (
+	// beta: v0.6
+	//
+	// Recreates the groups with a pod on a node which has been NotReady or unreachable
+	// for longer than the node NotReady grace period of the group recreation configuration.
+	NodeFailureGroupRecreation featuregate.Feature = "NodeFailureGroupRecreation"
+)
+
+// defaultFeatureGates are the features known to the controller, with their stage.
+// To add a new feature, define a key for it above and add it here.
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	NodeFailureGroupRecreation: {Default: true, PreRelease: featuregate.Beta},
+}
+
+// DefaultMutableFeatureGate is the feature gate of the controller, it's only mutated
+// when the flags and the configuration are applied, and by the tests.
+var DefaultMutableFeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+func init() {
+	utilruntime.Must(DefaultMutableFeatureGate.Add(defaultFeatureGates))
+}
+
+// Enabled returns whether the feature is enabled.
+func Enabled(f featuregate.Feature) bool {
+	return DefaultMutableFeatureGate.Enabled(f)
+}
+
+// SetFeatureGateDuringTest sets the feature to the value for the duration of the test,
+// it works with a testing.T as well as with ginkgo.GinkgoTB() in the envtest suites.
+func SetFeatureGateDuringTest(tb testing.TB, f featuregate.Feature, value bool) {
+	featuregatetesting.SetFeatureGateDuringTest(tb, DefaultMutableFeatureGate, f, value)
+}
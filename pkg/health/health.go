@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health implements the readiness and the liveness checks of the controller manager.
+package health
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// CertsReadyChecker is healthy once the certsReady channel is closed.
+func CertsReadyChecker(certsReady <-chan struct{}) healthz.Checker {
+	return func(*http.Request) error {
+		select {
+		case <-certsReady:
+			return nil
+		default:
+			return errors.New("the webhook certificates are not ready")
+		}
+	}
+}
+
+// CacheSyncedChecker is healthy once the informers of the cache are synced. It waits
+// for them until the probe times out.
+func CacheSyncedChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		if !c.WaitForCacheSync(req.Context()) {
+			return errors.New("the informers are not synced")
+		}
+		return nil
+	}
+}
+
+// WebhookServerChecker is healthy once the webhook server accepts connections. The webhook server is
+// only started once the certificates are ready, so it isn't checked before the certsReady channel is closed.
+func WebhookServerChecker(server func() webhook.Server, certsReady <-chan struct{}) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-certsReady:
+			return server().StartedChecker()(req)
+		default:
+			return errors.New("the webhook server isn't started until the webhook certificates are ready")
+		}
+	}
+}
+
+// StallDetector tracks the work queues of the controllers, and reports the controllers whose
+// reconciles are pending but which didn't start or finish any reconcile for longer than the timeout.
+type StallDetector struct {
+	timeout time.Duration
+	clock   clock.PassiveClock
+
+	mu     sync.Mutex
+	queues map[string]*trackedQueue
+}
+
+// NewStallDetector returns a stall detector of the given timeout.
+func NewStallDetector(timeout time.Duration) *StallDetector {
+	return &StallDetector{timeout: timeout, clock: clock.RealClock{}, queues: map[string]*trackedQueue{}}
+}
+
+// NewQueue returns the default work queue of the controller tracked by the stall detector,
+// it's meant to be set as the NewQueue of the controller options.
+func (d *StallDetector) NewQueue(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	q := &trackedQueue{
+		TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+			Name: controllerName,
+		}),
+		clock:        d.clock,
+		lastProgress: d.clock.Now(),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queues[controllerName] = q
+	return q
+}
+
+// Checker fails when a controller is stalled.
+func (d *StallDetector) Checker(*http.Request) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var stalled []string
+	for name, q := range d.queues {
+		if q.stalledFor(d.timeout) {
+			stalled = append(stalled, name)
+		}
+	}
+	if len(stalled) > 0 {
+		sort.Strings(stalled)
+		return fmt.Errorf("the reconciles of the controllers %s made no progress for %s", strings.Join(stalled, ", "), d.timeout)
+	}
+	return nil
+}
+
+// trackedQueue records the last time a worker got an item from the queue or finished processing one.
+type trackedQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	clock clock.PassiveClock
+
+	mu           sync.Mutex
+	processing   int
+	lastProgress time.Time
+}
+
+func (q *trackedQueue) Get() (reconcile.Request, bool) {
+	item, shutdown := q.TypedRateLimitingInterface.Get()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !shutdown {
+		q.processing++
+	}
+	q.lastProgress = q.clock.Now()
+	return item, shutdown
+}
+
+func (q *trackedQueue) Done(item reconcile.Request) {
+	q.TypedRateLimitingInterface.Done(item)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.processing--
+	q.lastProgress = q.clock.Now()
+}
+
+// stalledFor returns true if items are queued or being processed, and no item was
+// got or done for longer than the timeout.
+func (q *trackedQueue) stalledFor(timeout time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.processing == 0 && q.Len() == 0 {
+		return false
+	}
+	return q.clock.Since(q.lastProgress) > timeout
+}
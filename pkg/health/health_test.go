@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func TestCertsReadyChecker(t *testing.T) {
+	certsReady := make(chan struct{})
+	checker := CertsReadyChecker(certsReady)
+	if err := checker(nil); err == nil {
+		t.Error("Expected the check to fail before the certs are ready")
+	}
+	close(certsReady)
+	if err := checker(nil); err != nil {
+		t.Errorf("Unexpected error once the certs are ready: %v", err)
+	}
+}
+
+type fakeWebhookServer struct {
+	webhook.Server
+	started bool
+}
+
+func (s *fakeWebhookServer) StartedChecker() healthz.Checker {
+	return func(*http.Request) error {
+		if !s.started {
+			return http.ErrServerClosed
+		}
+		return nil
+	}
+}
+
+func TestWebhookServerChecker(t *testing.T) {
+	certsReady := make(chan struct{})
+	server := &fakeWebhookServer{}
+	serverGot := false
+	checker := WebhookServerChecker(func() webhook.Server {
+		serverGot = true
+		return server
+	}, certsReady)
+
+	if err := checker(nil); err == nil {
+		t.Error("Expected the check to fail before the certs are ready")
+	}
+	if serverGot {
+		t.Error("Unexpected webhook server got before the certs are ready")
+	}
+	close(certsReady)
+	if err := checker(nil); err == nil {
+		t.Error("Expected the check to fail before the webhook server is started")
+	}
+	server.started = true
+	if err := checker(nil); err != nil {
+		t.Errorf("Unexpected error once the webhook server is started: %v", err)
+	}
+}
+
+func TestStallDetector(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	detector := NewStallDetector(10 * time.Minute)
+	detector.clock = fakeClock
+	queue := detector.NewQueue("pod", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	item := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "lws-0"}}
+
+	fakeClock.Step(time.Hour)
+	if err := detector.Checker(nil); err != nil {
+		t.Errorf("Unexpected error of an idle controller: %v", err)
+	}
+
+	queue.Add(item)
+	if err := detector.Checker(nil); err == nil {
+		t.Error("Expected a controller whose queued item wasn't got for longer than the timeout to be stalled")
+	}
+
+	queue.Get()
+	if err := detector.Checker(nil); err != nil {
+		t.Errorf("Unexpected error once the item is got: %v", err)
+	}
+
+	fakeClock.Step(11 * time.Minute)
+	if err := detector.Checker(nil); err == nil {
+		t.Error("Expected a controller processing an item for longer than the timeout to be stalled")
+	}
+
+	queue.Done(item)
+	if err := detector.Checker(nil); err != nil {
+		t.Errorf("Unexpected error once the item is done: %v", err)
+	}
+}
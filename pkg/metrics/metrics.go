@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	subsystemName = "lws"
+)
+
+// GroupRecreationReason is the reason a group was recreated, it's a label of the
+// group recreation counter and must stay a small closed set.
+type GroupRecreationReason string
+
+const (
+	// ContainerRestartedReason means a container of a pod in the group restarted.
+	ContainerRestartedReason GroupRecreationReason = "ContainerRestarted"
+	// PodDeletedReason means a pod of the group was deleted.
+	PodDeletedReason GroupRecreationReason = "PodDeleted"
+	// RestartRequestedReason means the group restart was requested with the restart-groups annotation.
+	RestartRequestedReason GroupRecreationReason = "RestartRequested"
+	// NodeNotReadyReason means the node of a pod in the group was NotReady for longer than the grace period.
+	NodeNotReadyReason GroupRecreationReason = "NodeNotReady"
+	// PodDisruptedReason means a pod of the group was preempted, evicted or deleted by the taint manager.
+	PodDisruptedReason GroupRecreationReason = "PodDisrupted"
+)
+
+var (
+	groups = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "groups",
+			Help:      "The number of groups of a LeaderWorkerSet, including the ones surging during a rolling update.",
+		}, []string{"namespace", "name"},
+	)
+
+	readyGroups = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "ready_groups",
+			Help:      "The number of ready groups of a LeaderWorkerSet.",
+		}, []string{"namespace", "name"},
+	)
+
+	updatedGroups = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "updated_groups",
+			Help:      "The number of groups of a LeaderWorkerSet running the latest revision.",
+		}, []string{"namespace", "name"},
+	)
+
+	rolloutPartition = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "rollout_partition",
+			Help:      "The partition of the leader StatefulSet of a LeaderWorkerSet, the groups with a lower index aren't updated yet.",
+		}, []string{"namespace", "name"},
+	)
+
+	rolloutProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "rollout_progress",
+			Help:      "The ratio of the groups of a LeaderWorkerSet running the latest revision, between 0 and 1.",
+		}, []string{"namespace", "name"},
+	)
+
+	groupRecreations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystemName,
+			Name:      "group_recreations_total",
+			Help: `The number of groups recreated, by reason:
+- ContainerRestarted: a container of a pod in the group restarted
+- PodDeleted: a pod of the group was deleted
+- RestartRequested: the restart of the group was requested with the restart-groups annotation
+- NodeNotReady: the node of a pod in the group was NotReady for longer than the grace period
+- PodDisrupted: a pod of the group was preempted, evicted or deleted by the taint manager`,
+		}, []string{"namespace", "name", "reason"},
+	)
+
+	workerStatefulSetCreationDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Subsystem: subsystemName,
+			Name:      "worker_statefulset_creation_duration_seconds",
+			Help:      "The time from the creation of a leader pod to the creation of its worker StatefulSet.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+	)
+
+	groupReadyDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Subsystem: subsystemName,
+			Name:      "group_ready_duration_seconds",
+			Help:      "The time from the creation of a leader pod to its group becoming ready.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		},
+	)
+
+	webhookCertificateExpiration = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: subsystemName,
+			Name:      "webhook_certificate_expiration_timestamp_seconds",
+			Help:      "The expiration time of the webhook certificate read from an external Secret, in seconds since the epoch.",
+		},
+	)
+
+	tracker = &leaderWorkerSetTracker{tracked: sets.New[types.NamespacedName]()}
+)
+
+// leaderWorkerSetTracker bounds the number of LeaderWorkerSets with their own series.
+type leaderWorkerSetTracker struct {
+	sync.Mutex
+	max     int
+	tracked sets.Set[types.NamespacedName]
+}
+
+// track returns whether the LeaderWorkerSet can be reported with its own series,
+// starting to track it if there is room for it.
+func (t *leaderWorkerSetTracker) track(lws types.NamespacedName) bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.tracked.Has(lws) {
+		return true
+	}
+	if t.tracked.Len() >= t.max {
+		return false
+	}
+	t.tracked.Insert(lws)
+	return true
+}
+
+func (t *leaderWorkerSetTracker) untrack(lws types.NamespacedName) {
+	t.Lock()
+	defer t.Unlock()
+	t.tracked.Delete(lws)
+}
+
+// Register registers the LeaderWorkerSet metrics in the controller-runtime metrics registry.
+// At most maxTrackedLeaderWorkerSets LeaderWorkerSets are reported with their own series.
+func Register(maxTrackedLeaderWorkerSets int32) {
+	tracker.Lock()
+	tracker.max = int(maxTrackedLeaderWorkerSets)
+	tracker.Unlock()
+	metrics.Registry.MustRegister(
+		groups,
+		readyGroups,
+		updatedGroups,
+		rolloutPartition,
+		rolloutProgress,
+		groupRecreations,
+		workerStatefulSetCreationDuration,
+		groupReadyDuration,
+		webhookCertificateExpiration,
+	)
+}
+
+// ReportLeaderWorkerSetStatus reports the groups and the rollout of a LeaderWorkerSet.
+// Nothing is reported if the LeaderWorkerSet can't be tracked.
+func ReportLeaderWorkerSetStatus(lws types.NamespacedName, replicas, total, ready, updated, partition int32) {
+	if !tracker.track(lws) {
+		return
+	}
+	groups.WithLabelValues(lws.Namespace, lws.Name).Set(float64(total))
+	readyGroups.WithLabelValues(lws.Namespace, lws.Name).Set(float64(ready))
+	updatedGroups.WithLabelValues(lws.Namespace, lws.Name).Set(float64(updated))
+	rolloutPartition.WithLabelValues(lws.Namespace, lws.Name).Set(float64(partition))
+	progress := 1.0
+	if replicas > 0 {
+		progress = min(float64(updated)/float64(replicas), 1)
+	}
+	rolloutProgress.WithLabelValues(lws.Namespace, lws.Name).Set(progress)
+}
+
+// GroupRecreated counts a group of the LeaderWorkerSet recreated for the given reason.
+func GroupRecreated(lws types.NamespacedName, reason GroupRecreationReason) {
+	if !tracker.track(lws) {
+		lws = types.NamespacedName{}
+	}
+	groupRecreations.WithLabelValues(lws.Namespace, lws.Name, string(reason)).Inc()
+}
+
+// WorkerStatefulSetCreated observes the time from the creation of the leader pod
+// to the creation of its worker StatefulSet.
+func WorkerStatefulSetCreated(latency time.Duration) {
+	workerStatefulSetCreationDuration.Observe(latency.Seconds())
+}
+
+// GroupReady observes the time from the creation of the leader pod to its group becoming ready.
+func GroupReady(latency time.Duration) {
+	groupReadyDuration.Observe(latency.Seconds())
+}
+
+// WebhookCertificateLoaded reports the expiration time of the webhook certificate read from an external Secret.
+func WebhookCertificateLoaded(notAfter time.Time) {
+	webhookCertificateExpiration.Set(float64(notAfter.Unix()))
+}
+
+// ClearLeaderWorkerSetMetrics deletes the series of a deleted LeaderWorkerSet,
+// making room for another LeaderWorkerSet to be tracked.
+func ClearLeaderWorkerSetMetrics(lws types.NamespacedName) {
+	for _, gauge := range []*prometheus.GaugeVec{groups, readyGroups, updatedGroups, rolloutPartition, rolloutProgress} {
+		gauge.DeleteLabelValues(lws.Namespace, lws.Name)
+	}
+	groupRecreations.DeletePartialMatch(prometheus.Labels{"namespace": lws.Namespace, "name": lws.Name})
+	tracker.untrack(lws)
+}
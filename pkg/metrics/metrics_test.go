@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func resetMetrics(maxTracked int) {
+	tracker = &leaderWorkerSetTracker{max: maxTracked, tracked: sets.New[types.NamespacedName]()}
+	for _, gauge := range []*prometheus.GaugeVec{groups, readyGroups, updatedGroups, rolloutPartition, rolloutProgress} {
+		gauge.Reset()
+	}
+	groupRecreations.Reset()
+}
+
+func TestReportLeaderWorkerSetStatus(t *testing.T) {
+	resetMetrics(1)
+	lws1 := types.NamespacedName{Namespace: "default", Name: "lws1"}
+	lws2 := types.NamespacedName{Namespace: "default", Name: "lws2"}
+
+	ReportLeaderWorkerSetStatus(lws1, 4, 5, 3, 2, 2)
+	ReportLeaderWorkerSetStatus(lws2, 4, 4, 4, 4, 0)
+
+	for _, tc := range []struct {
+		gauge *prometheus.GaugeVec
+		want  float64
+	}{
+		{gauge: groups, want: 5},
+		{gauge: readyGroups, want: 3},
+		{gauge: updatedGroups, want: 2},
+		{gauge: rolloutPartition, want: 2},
+		{gauge: rolloutProgress, want: 0.5},
+	} {
+		if got := testutil.ToFloat64(tc.gauge.WithLabelValues(lws1.Namespace, lws1.Name)); got != tc.want {
+			t.Errorf("Unexpected value for lws1, want %v, got %v", tc.want, got)
+		}
+		// lws2 is past the maximum number of tracked LeaderWorkerSets.
+		if count := testutil.CollectAndCount(tc.gauge); count != 1 {
+			t.Errorf("Expected 1 series, got %d", count)
+		}
+	}
+
+	ClearLeaderWorkerSetMetrics(lws1)
+	if count := testutil.CollectAndCount(groups); count != 0 {
+		t.Errorf("Expected no series after clearing lws1, got %d", count)
+	}
+
+	ReportLeaderWorkerSetStatus(lws2, 0, 0, 0, 0, 0)
+	if got := testutil.ToFloat64(rolloutProgress.WithLabelValues(lws2.Namespace, lws2.Name)); got != 1 {
+		t.Errorf("Expected a complete rollout without replicas, got %v", got)
+	}
+}
+
+func TestGroupRecreated(t *testing.T) {
+	resetMetrics(1)
+	lws1 := types.NamespacedName{Namespace: "default", Name: "lws1"}
+	lws2 := types.NamespacedName{Namespace: "default", Name: "lws2"}
+
+	GroupRecreated(lws1, ContainerRestartedReason)
+	GroupRecreated(lws1, ContainerRestartedReason)
+	GroupRecreated(lws1, PodDeletedReason)
+	GroupRecreated(lws2, PodDeletedReason)
+
+	for _, tc := range []struct {
+		labels []string
+		want   float64
+	}{
+		{labels: []string{"default", "lws1", string(ContainerRestartedReason)}, want: 2},
+		{labels: []string{"default", "lws1", string(PodDeletedReason)}, want: 1},
+		// lws2 is past the maximum number of tracked LeaderWorkerSets.
+		{labels: []string{"", "", string(PodDeletedReason)}, want: 1},
+	} {
+		if got := testutil.ToFloat64(groupRecreations.WithLabelValues(tc.labels...)); got != tc.want {
+			t.Errorf("Unexpected value for %v, want %v, got %v", tc.labels, tc.want, got)
+		}
+	}
+
+	ClearLeaderWorkerSetMetrics(lws1)
+	if count := testutil.CollectAndCount(groupRecreations); count != 1 {
+		t.Errorf("Expected only the untracked series after clearing lws1, got %d", count)
+	}
+}
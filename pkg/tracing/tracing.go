@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	crand "crypto/rand"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	tracerName    = "sigs.k8s.io/lws"
+	serviceName   = "lws-controller-manager"
+	groupSpanName = "LeaderWorkerSet group"
+
+	// traceParentKey is the key of the W3C traceparent in a propagation carrier.
+	traceParentKey = "traceparent"
+
+	// The events of the group span.
+	WorkerStatefulSetCreatedEvent = "WorkerStatefulSetCreated"
+	WorkersScheduledEvent         = "WorkersScheduled"
+)
+
+// groupSpanContextKey is the context key of the span context a group span is created with.
+type groupSpanContextKey struct{}
+
+// Tracer returns the tracer of the leaderworkerset components, from the global tracer provider.
+// It's a noop tracer unless a provider was registered with otel.SetTracerProvider.
+func Tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// NewProvider creates the tracer provider exporting the spans to the OTLP gRPC collector of the configuration.
+// Mostly inspired by k8s.io/component-base/tracing.NewProvider, which doesn't allow to set the ID generator
+// the group spans rely on.
+func NewProvider(ctx context.Context, cfg *tracingapi.TracingConfiguration) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithInsecure()}
+	if cfg.Endpoint != nil {
+		opts = append(opts, otlptracegrpc.WithEndpoint(*cfg.Endpoint))
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(cfg, sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
+
+func newProvider(cfg *tracingapi.TracingConfiguration, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	// The sampler respects the sampling of the parent span, and otherwise never samples
+	// unless a sampling rate is set.
+	sampler := sdktrace.NeverSample()
+	if cfg.SamplingRatePerMillion != nil && *cfg.SamplingRatePerMillion > 0 {
+		sampler = sdktrace.TraceIDRatioBased(float64(*cfg.SamplingRatePerMillion) / float64(1000000))
+	}
+	opts = append(opts, sdktrace.WithSampler(sdktrace.ParentBased(sampler)), sdktrace.WithIDGenerator(&idGenerator{}))
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// NewGroupTraceContext returns the W3C traceparent of the span of a new group, it is set as the
+// TraceContextAnnotationKey annotation of the group pods. The span itself is only created once
+// the group is ready, by EndGroupSpan, or deleted before, by FailGroupSpan. An empty string is returned if the group isn't traced.
+func NewGroupTraceContext(ctx context.Context) string {
+	// The span isn't ended, so it's never exported.
+	_, span := Tracer().Start(ctx, groupSpanName, trace.WithNewRoot())
+	if !span.SpanContext().IsSampled() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(ctx, span.SpanContext()), carrier)
+	return carrier.Get(traceParentKey)
+}
+
+// ContextWithGroup returns a context whose spans are children of the span of the group of the pod.
+// The context is returned as is if the group isn't traced.
+func ContextWithGroup(ctx context.Context, pod *corev1.Pod) context.Context {
+	spanContext := groupSpanContext(pod)
+	if !spanContext.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, spanContext)
+}
+
+// EndGroupSpan creates the span of the group of the leader pod once the group is ready. It starts
+// with the creation of the leader pod and has an event for the creation of the worker StatefulSet
+// and the scheduling of the workers, when their time is known.
+func EndGroupSpan(ctx context.Context, leaderPod *corev1.Pod, workerStatefulSetCreated, workersScheduled, ready time.Time) {
+	if span := startGroupSpan(ctx, leaderPod, workerStatefulSetCreated, workersScheduled); span != nil {
+		span.End(trace.WithTimestamp(ready))
+	}
+}
+
+// FailGroupSpan creates the span of the group of the leader pod, with an error status, when the
+// group is deleted or recreated before becoming ready.
+func FailGroupSpan(ctx context.Context, leaderPod *corev1.Pod, workerStatefulSetCreated, workersScheduled, deleted time.Time, description string) {
+	if span := startGroupSpan(ctx, leaderPod, workerStatefulSetCreated, workersScheduled); span != nil {
+		span.SetStatus(codes.Error, description)
+		span.End(trace.WithTimestamp(deleted))
+	}
+}
+
+// startGroupSpan starts the span of the group of the leader pod, it returns nil if the group isn't traced.
+func startGroupSpan(ctx context.Context, leaderPod *corev1.Pod, workerStatefulSetCreated, workersScheduled time.Time) trace.Span {
+	spanContext := groupSpanContext(leaderPod)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	ctx = context.WithValue(ctx, groupSpanContextKey{}, spanContext)
+	_, span := Tracer().Start(ctx, groupSpanName,
+		trace.WithNewRoot(),
+		trace.WithTimestamp(leaderPod.CreationTimestamp.Time),
+		trace.WithAttributes(
+			attribute.String("namespace", leaderPod.Namespace),
+			attribute.String("leaderworkerset", leaderPod.Labels[leaderworkerset.SetNameLabelKey]),
+			attribute.String("group", leaderPod.Labels[leaderworkerset.GroupIndexLabelKey]),
+			attribute.String("leader", leaderPod.Name),
+		),
+	)
+	if !workerStatefulSetCreated.IsZero() {
+		span.AddEvent(WorkerStatefulSetCreatedEvent, trace.WithTimestamp(workerStatefulSetCreated))
+	}
+	if !workersScheduled.IsZero() {
+		span.AddEvent(WorkersScheduledEvent, trace.WithTimestamp(workersScheduled))
+	}
+	return span
+}
+
+// groupSpanContext returns the span context of the group of the pod, from its annotation.
+func groupSpanContext(pod *corev1.Pod) trace.SpanContext {
+	traceParent := pod.Annotations[leaderworkerset.TraceContextAnnotationKey]
+	if traceParent == "" {
+		return trace.SpanContext{}
+	}
+	carrier := propagation.MapCarrier{traceParentKey: traceParent}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
+
+// idGenerator generates random IDs, except for the group spans which keep the IDs of their annotation.
+type idGenerator struct{}
+
+var _ sdktrace.IDGenerator = &idGenerator{}
+
+func (g *idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if spanContext, ok := ctx.Value(groupSpanContextKey{}).(trace.SpanContext); ok {
+		return spanContext.TraceID(), spanContext.SpanID()
+	}
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		_, _ = crand.Read(traceID[:])
+	}
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+func (g *idGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		_, _ = crand.Read(spanID[:])
+	}
+	return spanID
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func setupExporter(t *testing.T, samplingRatePerMillion int32) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := newProvider(&tracingapi.TracingConfiguration{SamplingRatePerMillion: ptr.To(samplingRatePerMillion)}, sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func leaderPod(traceContext string, created time.Time) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-sample-1",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "1",
+			},
+		},
+	}
+	if traceContext != "" {
+		pod.Annotations = map[string]string{leaderworkerset.TraceContextAnnotationKey: traceContext}
+	}
+	return pod
+}
+
+func TestGroupSpan(t *testing.T) {
+	exporter := setupExporter(t, 1000000)
+	ctx := context.Background()
+
+	traceContext := NewGroupTraceContext(ctx)
+	if traceContext == "" {
+		t.Fatal("Expected a trace context for a sampled group")
+	}
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Fatalf("Expected no span before the group is ready, got %d", len(spans))
+	}
+
+	created := time.Now().Add(-time.Minute).Truncate(time.Second)
+	workerStatefulSetCreated := created.Add(time.Second)
+	workersScheduled := created.Add(10 * time.Second)
+	ready := created.Add(30 * time.Second)
+	pod := leaderPod(traceContext, created)
+
+	_, child := Tracer().Start(ContextWithGroup(ctx, pod), "child")
+	child.End()
+	EndGroupSpan(ctx, pod, workerStatefulSetCreated, workersScheduled, ready)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	childSpan, groupSpan := spans[0], spans[1]
+	want := groupSpanContext(pod)
+	if groupSpan.SpanContext.TraceID() != want.TraceID() || groupSpan.SpanContext.SpanID() != want.SpanID() {
+		t.Errorf("Expected the group span to keep the IDs of the annotation, want %v, got %v", want, groupSpan.SpanContext)
+	}
+	if groupSpan.Parent.IsValid() {
+		t.Errorf("Expected the group span to be a root span, got parent %v", groupSpan.Parent)
+	}
+	if childSpan.Parent.SpanID() != want.SpanID() || childSpan.SpanContext.TraceID() != want.TraceID() {
+		t.Errorf("Expected the child span to be a child of the group span, got parent %v", childSpan.Parent)
+	}
+	if !groupSpan.StartTime.Equal(created) || !groupSpan.EndTime.Equal(ready) {
+		t.Errorf("Unexpected span duration, want %v to %v, got %v to %v", created, ready, groupSpan.StartTime, groupSpan.EndTime)
+	}
+	if len(groupSpan.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(groupSpan.Events))
+	}
+	for i, event := range []struct {
+		name string
+		time time.Time
+	}{
+		{name: WorkerStatefulSetCreatedEvent, time: workerStatefulSetCreated},
+		{name: WorkersScheduledEvent, time: workersScheduled},
+	} {
+		if got := groupSpan.Events[i]; got.Name != event.name || !got.Time.Equal(event.time) {
+			t.Errorf("Unexpected event, want %s at %v, got %s at %v", event.name, event.time, got.Name, got.Time)
+		}
+	}
+}
+
+func TestFailedGroupSpan(t *testing.T) {
+	exporter := setupExporter(t, 1000000)
+	ctx := context.Background()
+
+	created := time.Now().Add(-time.Minute).Truncate(time.Second)
+	deleted := created.Add(30 * time.Second)
+	pod := leaderPod(NewGroupTraceContext(ctx), created)
+	FailGroupSpan(ctx, pod, time.Time{}, time.Time{}, deleted, "Group 1 was deleted before becoming ready")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	groupSpan := spans[0]
+	want := groupSpanContext(pod)
+	if groupSpan.SpanContext.TraceID() != want.TraceID() || groupSpan.SpanContext.SpanID() != want.SpanID() {
+		t.Errorf("Expected the group span to keep the IDs of the annotation, want %v, got %v", want, groupSpan.SpanContext)
+	}
+	if !groupSpan.StartTime.Equal(created) || !groupSpan.EndTime.Equal(deleted) {
+		t.Errorf("Unexpected span duration, want %v to %v, got %v to %v", created, deleted, groupSpan.StartTime, groupSpan.EndTime)
+	}
+	if groupSpan.Status.Code != codes.Error || groupSpan.Status.Description != "Group 1 was deleted before becoming ready" {
+		t.Errorf("Unexpected span status %v", groupSpan.Status)
+	}
+	if len(groupSpan.Events) != 0 {
+		t.Errorf("Expected no event, got %d", len(groupSpan.Events))
+	}
+}
+
+func TestUntracedGroup(t *testing.T) {
+	exporter := setupExporter(t, 0)
+	ctx := context.Background()
+
+	traceContext := NewGroupTraceContext(ctx)
+	if traceContext != "" {
+		t.Fatalf("Expected no trace context without sampling, got %q", traceContext)
+	}
+	pod := leaderPod(traceContext, time.Now())
+	if got := ContextWithGroup(ctx, pod); got != ctx {
+		t.Error("Expected the context to be returned as is for an untraced group")
+	}
+	EndGroupSpan(ctx, pod, time.Time{}, time.Time{}, time.Now())
+	FailGroupSpan(ctx, pod, time.Time{}, time.Time{}, time.Now(), "deleted")
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Errorf("Expected no span for an untraced group, got %d", len(spans))
+	}
+}
@@ -34,8 +34,19 @@ const (
 	TpuWorkerId                     string              = "TPU_WORKER_ID"
 	TpuName                         string              = "TPU_NAME"
 	LeaderRequestsTPUsAnnotationKey string              = "leaderworkerset.sigs.k8s.io/leader-requests-tpus"
+	// TpuTopologyNodeSelectorKey is the GKE node selector that determines the
+	// topology of the TPU slice, e.g. 2x2x4.
+	TpuTopologyNodeSelectorKey string = "cloud.google.com/gke-tpu-topology"
 )
 
+// tpuEnvAnnotationKeys are the pod annotations holding the values of the TPU environment variables of the pods
+// which are not mutated by the pod webhook, the environment variables read them through the downward API.
+var tpuEnvAnnotationKeys = map[string]string{
+	TpuWorkerHostNames: "leaderworkerset.sigs.k8s.io/tpu-worker-hostnames",
+	TpuWorkerId:        "leaderworkerset.sigs.k8s.io/tpu-worker-id",
+	TpuName:            "leaderworkerset.sigs.k8s.io/tpu-name",
+}
+
 // PodRequestsTPUs returns true if the pod requesting TPUs
 func PodRequestsTPUs(podTs corev1.PodSpec) bool {
 	return containersRequestTPUs(podTs.Containers...) || containersRequestTPUs(podTs.InitContainers...)
@@ -224,6 +235,117 @@ func AddTPUVariables(pod *corev1.Pod, size int) error {
 	return nil
 }
 
+// AddTPUTemplateVariables adds the TPU environment variables to the container requesting TPUs of a pod template
+// whose pods are not mutated by the pod webhook. The values are read from the pod annotations set by TPUAnnotations,
+// the variables already set by the user are kept as AddTPUVariables does.
+func AddTPUTemplateVariables(spec *corev1.PodSpec) {
+	container := getContainerRequestingTPUs(spec)
+	if container == nil {
+		return
+	}
+	for _, env := range container.Env {
+		if env.Name == TpuWorkerHostNames || env.Name == TpuWorkerId {
+			return
+		}
+	}
+	for _, name := range []string{TpuWorkerHostNames, TpuWorkerId, TpuName} {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.annotations['%s']", tpuEnvAnnotationKeys[name])},
+			},
+		})
+	}
+}
+
+// TPUAnnotations returns the annotations read by the TPU environment variables added by AddTPUTemplateVariables,
+// with the values AddTPUVariables would inject into the pod. It returns nil if the pod has no such variables.
+func TPUAnnotations(pod *corev1.Pod, size int) (map[string]string, error) {
+	container := getContainerRequestingTPUs(&pod.Spec)
+	if container == nil {
+		return nil, nil
+	}
+	tpuPod := pod.DeepCopy()
+	tpuContainer := getContainerRequestingTPUs(&tpuPod.Spec)
+	templateVariable := false
+	env := make([]corev1.EnvVar, 0, len(tpuContainer.Env))
+	for _, e := range tpuContainer.Env {
+		if e.ValueFrom != nil && e.ValueFrom.FieldRef != nil && e.ValueFrom.FieldRef.FieldPath == fmt.Sprintf("metadata.annotations['%s']", tpuEnvAnnotationKeys[e.Name]) {
+			templateVariable = true
+			continue
+		}
+		env = append(env, e)
+	}
+	if !templateVariable {
+		return nil, nil
+	}
+	tpuContainer.Env = env
+	if err := AddTPUVariables(tpuPod, size); err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{}
+	for _, e := range tpuContainer.Env {
+		if key, found := tpuEnvAnnotationKeys[e.Name]; found {
+			annotations[key] = e.Value
+		}
+	}
+	return annotations, nil
+}
+
+// SetTPUWorkerHostName replaces the host name of the given TPU worker in the TPU_WORKER_HOSTNAMES annotation.
+func SetTPUWorkerHostName(annotations map[string]string, oldHostName, newHostName string) {
+	key := tpuEnvAnnotationKeys[TpuWorkerHostNames]
+	hostnames := strings.Split(annotations[key], ",")
+	for i := range hostnames {
+		if hostnames[i] == oldHostName {
+			hostnames[i] = newHostName
+		}
+	}
+	annotations[key] = strings.Join(hostnames, ",")
+}
+
+// numChipsFromTopology returns the number of TPU chips in the given topology,
+// which is expected to be in the format of AxB or AxBxC.
+func numChipsFromTopology(topology string) (int64, error) {
+	dimensions := strings.Split(topology, "x")
+	if len(dimensions) < 2 {
+		return 0, fmt.Errorf("invalid TPU topology %q, expected the format AxB or AxBxC", topology)
+	}
+	chips := int64(1)
+	for _, dimension := range dimensions {
+		value, err := strconv.ParseInt(dimension, 10, 32)
+		if err != nil || value < 1 {
+			return 0, fmt.Errorf("invalid TPU topology %q, dimensions must be positive integers", topology)
+		}
+		chips *= value
+	}
+	return chips, nil
+}
+
+// NumTPUHosts returns the number of hosts of the TPU slice that the pod spec
+// is requesting, computed from the TPU topology node selector and the number of
+// TPUs requested by its container. Returns 0 if the pod doesn't request TPUs or
+// doesn't specify a TPU topology.
+func NumTPUHosts(podSpec corev1.PodSpec) (int32, error) {
+	topology, found := podSpec.NodeSelector[TpuTopologyNodeSelectorKey]
+	if !found {
+		return 0, nil
+	}
+	container := getContainerRequestingTPUs(&podSpec)
+	if container == nil {
+		return 0, nil
+	}
+	chips, err := numChipsFromTopology(topology)
+	if err != nil {
+		return 0, err
+	}
+	chipsPerHost := numTPUsRequested(*container)
+	if chips%chipsPerHost != 0 {
+		return 0, fmt.Errorf("the number of chips in TPU topology %q is not divisible by the %d TPUs requested by container %s", topology, chipsPerHost, container.Name)
+	}
+	return int32(chips / chipsPerHost), nil
+}
+
 // AddTPUAnnotations adds TPU specific annotations.
 func AddTPUAnnotations(leaderPod corev1.Pod, annotations map[string]string) {
 	if PodRequestsTPUs(leaderPod.Spec) {
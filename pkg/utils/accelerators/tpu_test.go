@@ -244,3 +244,123 @@ func TestGetContainerRequestingTPUs(t *testing.T) {
 		})
 	}
 }
+
+func TestNumTPUHosts(t *testing.T) {
+	tests := []struct {
+		name          string
+		podSpec       corev1.PodSpec
+		topology      string
+		expectedHosts int32
+		expectedErr   bool
+	}{
+		{
+			name:          "No TPU topology node selector",
+			podSpec:       wrappers.MakeLeaderPodSpecWithTPUResource(),
+			expectedHosts: 0,
+		},
+		{
+			name:          "No container requesting TPUs",
+			podSpec:       wrappers.MakeLeaderPodSpec(),
+			topology:      "2x2x4",
+			expectedHosts: 0,
+		},
+		{
+			name:          "Three dimensional topology",
+			podSpec:       wrappers.MakeLeaderPodSpecWithTPUResource(),
+			topology:      "2x2x4",
+			expectedHosts: 4,
+		},
+		{
+			name:          "Two dimensional topology",
+			podSpec:       wrappers.MakeWorkerPodSpecWithTPUResource(),
+			topology:      "4x4",
+			expectedHosts: 4,
+		},
+		{
+			name:        "Malformed topology",
+			podSpec:     wrappers.MakeLeaderPodSpecWithTPUResource(),
+			topology:    "2by2",
+			expectedErr: true,
+		},
+		{
+			name:        "Topology with a zero dimension",
+			podSpec:     wrappers.MakeLeaderPodSpecWithTPUResource(),
+			topology:    "2x0",
+			expectedErr: true,
+		},
+		{
+			name:        "Chips not divisible by TPUs per host",
+			podSpec:     wrappers.MakeLeaderPodSpecWithTPUResource(),
+			topology:    "1x2",
+			expectedErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.topology != "" {
+				tc.podSpec.NodeSelector = map[string]string{TpuTopologyNodeSelectorKey: tc.topology}
+			}
+			hosts, err := NumTPUHosts(tc.podSpec)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedHosts, hosts); diff != "" {
+				t.Errorf("unexpected number of hosts (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTPUAnnotations(t *testing.T) {
+	tests := []struct {
+		name            string
+		spec            func() corev1.PodSpec
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "template variables",
+			spec: func() corev1.PodSpec {
+				spec := wrappers.MakeLeaderPodSpecWithTPUResource()
+				AddTPUTemplateVariables(&spec)
+				return spec
+			},
+			wantAnnotations: map[string]string{
+				"leaderworkerset.sigs.k8s.io/tpu-worker-hostnames": "test-sample-1.default,test-sample-1-1.default",
+				"leaderworkerset.sigs.k8s.io/tpu-worker-id":        "0",
+				"leaderworkerset.sigs.k8s.io/tpu-name":             "test-sample-1",
+			},
+		},
+		{
+			name: "variables set by the user",
+			spec: func() corev1.PodSpec {
+				spec := wrappers.MakeLeaderPodSpecWithTPUResource()
+				spec.Containers[0].Env = append(spec.Containers[0].Env, corev1.EnvVar{Name: TpuWorkerId, Value: "3"})
+				AddTPUTemplateVariables(&spec)
+				return spec
+			},
+		},
+		{
+			name: "no template variables",
+			spec: wrappers.MakeLeaderPodSpecWithTPUResource,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   "test-sample-1",
+					Labels: map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+				},
+				Spec: tc.spec(),
+			}
+			pod.Spec.Subdomain = "default"
+			annotations, err := TPUAnnotations(pod, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -18,46 +18,130 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
-func CreateHeadlessServiceIfNotExists(ctx context.Context, k8sClient client.Client, Scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, serviceName string, serviceSelector map[string]string, owner metav1.Object) error {
-	log := ctrl.LoggerFrom(ctx)
-	// If the headless service does not exist in the namespace, create it.
+// ServiceNotOwned Event reason used when a service with the name of a headless service
+// is not controlled by its owner, and is left untouched.
+const ServiceNotOwned = "ServiceNotOwned"
+
+// ReconcileHeadlessService creates the headless service if it does not exist, and otherwise corrects the drift of
+// its selector, its PublishNotReadyAddresses, its labels and its controller reference. A service which is not
+// headless is recreated, since the cluster IP of a service is immutable. An existing service is only changed when
+// it is controlled by the owner, or by a previous owner of the same kind and name, other services are left untouched.
+func ReconcileHeadlessService(ctx context.Context, k8sClient client.Client, Scheme *runtime.Scheme, recorder record.EventRecorder, lws *leaderworkerset.LeaderWorkerSet, serviceName string, serviceSelector map[string]string, owner metav1.Object) error {
+	log := ctrl.LoggerFrom(ctx).WithValues("service", serviceName)
 	var headlessService corev1.Service
 	if err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: lws.Namespace}, &headlessService); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return err
 		}
-		headlessService := corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceName,
-				Namespace: lws.Namespace,
-			},
-			Spec: corev1.ServiceSpec{
-				ClusterIP:                "None", // defines service as headless
-				Selector:                 serviceSelector,
-				PublishNotReadyAddresses: true,
-			},
-		}
-
-		// Set the controller owner reference for garbage collection and reconciliation.
-		if err := ctrl.SetControllerReference(owner, &headlessService, Scheme); err != nil {
-			return err
-		}
-		// create the service in the cluster
-		log.V(2).Info("Creating headless service.")
-		if err := k8sClient.Create(ctx, &headlessService); err != nil {
+		return createHeadlessService(ctx, k8sClient, Scheme, lws, serviceName, serviceSelector, owner)
+	}
+	if headlessService.DeletionTimestamp != nil {
+		return fmt.Errorf("headless service %s is being deleted", serviceName)
+	}
+	controlled, err := controlledByOwnerName(&headlessService, owner, Scheme)
+	if err != nil {
+		return err
+	}
+	if !controlled {
+		log.V(2).Info("Skipping the service which is not controlled by its owner.")
+		recorder.Eventf(lws, corev1.EventTypeWarning, ServiceNotOwned, fmt.Sprintf("Service %s already exists and is not controlled by %s, leaving it untouched", serviceName, owner.GetName()))
+		return nil
+	}
+	if headlessService.Spec.ClusterIP != corev1.ClusterIPNone {
+		log.V(2).Info("Recreating the service which is not headless.")
+		if err := k8sClient.Delete(ctx, &headlessService, client.Preconditions{UID: &headlessService.UID}); client.IgnoreNotFound(err) != nil {
 			return err
 		}
+		return createHeadlessService(ctx, k8sClient, Scheme, lws, serviceName, serviceSelector, owner)
+	}
+
+	desired := headlessService.DeepCopy()
+	desired.Spec.Selector = serviceSelector
+	desired.Spec.PublishNotReadyAddresses = true
+	if desired.Labels == nil {
+		desired.Labels = map[string]string{}
+	}
+	desired.Labels[leaderworkerset.SetNameLabelKey] = lws.Name
+	// The owner may have been recreated with the same name, e.g. a leader pod recreated by its statefulset,
+	// the controller reference is then moved to the new owner so that the service isn't garbage collected.
+	if err := ctrl.SetControllerReference(owner, desired, Scheme); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(desired.Spec, headlessService.Spec) && equality.Semantic.DeepEqual(desired.ObjectMeta, headlessService.ObjectMeta) {
+		return nil
+	}
+	log.V(2).Info("Updating the drifted headless service.")
+	return k8sClient.Update(ctx, desired)
+}
+
+// DeleteHeadlessService deletes the headless service if it exists and is controlled by the owner.
+func DeleteHeadlessService(ctx context.Context, k8sClient client.Client, namespace, serviceName string, owner metav1.Object) error {
+	var headlessService corev1.Service
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: namespace}, &headlessService); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(&headlessService, owner) || headlessService.DeletionTimestamp != nil {
+		return nil
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Deleting headless service.", "service", serviceName)
+	return client.IgnoreNotFound(k8sClient.Delete(ctx, &headlessService, client.Preconditions{UID: &headlessService.UID}))
+}
+
+// controlledByOwnerName returns whether the object is controlled by the owner, or by an object of the same
+// kind and name, e.g. a leader pod which was recreated by its statefulset.
+func controlledByOwnerName(obj metav1.Object, owner metav1.Object, scheme *runtime.Scheme) (bool, error) {
+	if metav1.IsControlledBy(obj, owner) {
+		return true, nil
+	}
+	controllerRef := metav1.GetControllerOf(obj)
+	if controllerRef == nil || controllerRef.Name != owner.GetName() {
+		return false, nil
+	}
+	runtimeOwner, ok := owner.(runtime.Object)
+	if !ok {
+		return false, fmt.Errorf("%T is not a runtime.Object", owner)
+	}
+	gvk, err := apiutil.GVKForObject(runtimeOwner, scheme)
+	if err != nil {
+		return false, err
+	}
+	return controllerRef.Kind == gvk.Kind && controllerRef.APIVersion == gvk.GroupVersion().String(), nil
+}
+
+func createHeadlessService(ctx context.Context, k8sClient client.Client, Scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, serviceName string, serviceSelector map[string]string, owner metav1.Object) error {
+	headlessService := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: lws.Namespace,
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lws.Name},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone, // defines service as headless
+			Selector:                 serviceSelector,
+			PublishNotReadyAddresses: true,
+		},
+	}
+
+	// Set the controller owner reference for garbage collection and reconciliation.
+	if err := ctrl.SetControllerReference(owner, &headlessService, Scheme); err != nil {
+		return err
 	}
-	return nil
+	// create the service in the cluster
+	ctrl.LoggerFrom(ctx).V(2).Info("Creating headless service.", "service", serviceName)
+	return k8sClient.Create(ctx, &headlessService)
 }
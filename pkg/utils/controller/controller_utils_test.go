@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func leaderPod(uid types.UID) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default", UID: uid}}
+}
+
+func leaderPodReference(uid types.UID) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "v1",
+		Kind:               "Pod",
+		Name:               "test-sample-0",
+		UID:                uid,
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+}
+
+func TestReconcileHeadlessService(t *testing.T) {
+	lws := &leaderworkerset.LeaderWorkerSet{ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"}}
+	selector := map[string]string{
+		leaderworkerset.SetNameLabelKey:    "test-sample",
+		leaderworkerset.GroupIndexLabelKey: "0",
+	}
+	wantSpec := corev1.ServiceSpec{
+		ClusterIP:                corev1.ClusterIPNone,
+		Selector:                 selector,
+		PublishNotReadyAddresses: true,
+	}
+	wantLabels := map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}
+
+	tests := []struct {
+		name     string
+		existing *corev1.Service
+	}{
+		{
+			name: "service created if it does not exist",
+		},
+		{
+			name: "drifted selector and publishNotReadyAddresses are corrected",
+			existing: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default", OwnerReferences: []metav1.OwnerReference{leaderPodReference("new")}},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: corev1.ClusterIPNone,
+					Selector:  map[string]string{"app": "changed"},
+				},
+			},
+		},
+		{
+			name: "controller reference moved to the recreated owner",
+			existing: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "test-sample-0",
+					Namespace:       "default",
+					Labels:          wantLabels,
+					OwnerReferences: []metav1.OwnerReference{leaderPodReference("old")},
+				},
+				Spec: wantSpec,
+			},
+		},
+		{
+			name: "service which isn't headless is recreated",
+			existing: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default", OwnerReferences: []metav1.OwnerReference{leaderPodReference("new")}},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "10.0.0.1",
+					Selector:  selector,
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme)
+			if tc.existing != nil {
+				builder = builder.WithObjects(tc.existing)
+			}
+			k8sClient := builder.Build()
+			if err := ReconcileHeadlessService(context.TODO(), k8sClient, clientgoscheme.Scheme, record.NewFakeRecorder(10), lws, "test-sample-0", selector, leaderPod("new")); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got corev1.Service
+			if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: "test-sample-0", Namespace: "default"}, &got); err != nil {
+				t.Fatalf("Getting the service: %v", err)
+			}
+			if diff := cmp.Diff(wantSpec, got.Spec); diff != "" {
+				t.Errorf("Unexpected spec (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantLabels, got.Labels); diff != "" {
+				t.Errorf("Unexpected labels (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]metav1.OwnerReference{leaderPodReference("new")}, got.OwnerReferences); diff != "" {
+				t.Errorf("Unexpected owner references (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileHeadlessServiceNotOwned(t *testing.T) {
+	lws := &leaderworkerset.LeaderWorkerSet{ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"}}
+	selector := map[string]string{
+		leaderworkerset.SetNameLabelKey:    "test-sample",
+		leaderworkerset.GroupIndexLabelKey: "0",
+	}
+	tests := []struct {
+		name     string
+		existing *corev1.Service
+	}{
+		{
+			name: "service without controller is kept",
+			existing: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "10.0.0.1",
+					Selector:  map[string]string{"app": "user"},
+				},
+			},
+		},
+		{
+			name: "service controlled by another object is kept",
+			existing: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-0",
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "test-sample-0",
+						UID:        "other",
+						Controller: ptr.To(true),
+					}},
+				},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: corev1.ClusterIPNone,
+					Selector:  map[string]string{"app": "user"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(tc.existing).Build()
+			recorder := record.NewFakeRecorder(10)
+			if err := ReconcileHeadlessService(context.TODO(), k8sClient, clientgoscheme.Scheme, recorder, lws, "test-sample-0", selector, leaderPod("new")); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got corev1.Service
+			if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.existing), &got); err != nil {
+				t.Fatalf("Getting the service: %v", err)
+			}
+			if diff := cmp.Diff(tc.existing.Spec, got.Spec); diff != "" {
+				t.Errorf("Unexpected spec (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.existing.OwnerReferences, got.OwnerReferences); diff != "" {
+				t.Errorf("Unexpected owner references (-want,+got):\n%s", diff)
+			}
+			if len(recorder.Events) != 1 {
+				t.Errorf("Expected a %s event, got %d events", ServiceNotOwned, len(recorder.Events))
+			}
+		})
+	}
+}
+
+func TestDeleteHeadlessService(t *testing.T) {
+	tests := []struct {
+		name        string
+		ownerUID    types.UID
+		wantDeleted bool
+	}{
+		{
+			name:        "service controlled by the owner is deleted",
+			ownerUID:    "new",
+			wantDeleted: true,
+		},
+		{
+			name:     "service controlled by another owner is kept",
+			ownerUID: "old",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default", OwnerReferences: []metav1.OwnerReference{leaderPodReference(tc.ownerUID)}},
+				Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone},
+			}
+			k8sClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(service).Build()
+			if err := DeleteHeadlessService(context.TODO(), k8sClient, "default", "test-sample-0", leaderPod("new")); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(service), &corev1.Service{})
+			if deleted := apierrors.IsNotFound(err); deleted != tc.wantDeleted {
+				t.Errorf("Unexpected deletion of the service, want deleted %t, got error %v", tc.wantDeleted, err)
+			}
+		})
+	}
+	if err := DeleteHeadlessService(context.TODO(), fake.NewClientBuilder().Build(), "default", "test-sample-0", leaderPod("new")); err != nil {
+		t.Errorf("Unexpected error deleting a missing service: %v", err)
+	}
+}
@@ -18,11 +18,17 @@ package pod
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
 )
 
 // ContainerRestarted return true when there is any container in the pod that gets restarted
@@ -49,6 +55,140 @@ func PodDeleted(pod corev1.Pod) bool {
 	return pod.DeletionTimestamp != nil
 }
 
+// The reasons of the DisruptionTarget condition of a pod disrupted by the scheduler, the eviction API
+// and the taint manager.
+const (
+	PreemptionByScheduler  = corev1.PodReasonPreemptionByScheduler
+	EvictionByEvictionAPI  = "EvictionByEvictionAPI"
+	DeletionByTaintManager = "DeletionByTaintManager"
+)
+
+// disruptionMessages describe the disruptions of the pods by their reason.
+var disruptionMessages = map[string]string{
+	PreemptionByScheduler:  "pod was preempted",
+	EvictionByEvictionAPI:  "pod was evicted",
+	DeletionByTaintManager: "pod was deleted by the taint manager",
+}
+
+// DisruptionCondition returns the DisruptionTarget condition of the pod if it is being disrupted, nil otherwise.
+func DisruptionCondition(pod corev1.Pod) *corev1.PodCondition {
+	_, condition := getPodCondition(&pod.Status, corev1.DisruptionTarget)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return nil
+	}
+	return condition
+}
+
+// PodScheduled checks if the pod is scheduled to a node.
+func PodScheduled(pod corev1.Pod) bool {
+	_, condition := getPodCondition(&pod.Status, corev1.PodScheduled)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// maxTerminationMessageLength bounds the termination message of a container kept in a PodFailure.
+const maxTerminationMessageLength = 256
+
+// PodFailure summarizes why a pod failed, from the statuses of its containers.
+type PodFailure struct {
+	// Reason is a CamelCase summary of the failure, e.g. OOMKilled or ImagePullBackOff.
+	Reason string
+	// Message details the failure: the failed container, its exit code and its last termination message.
+	Message string
+}
+
+// containerWaitingFailureReasons are the reasons of a waiting container that mean it can't start.
+var containerWaitingFailureReasons = sets.New(
+	"CrashLoopBackOff",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"ErrImagePull",
+	"ImagePullBackOff",
+	"InvalidImageName",
+)
+
+// conditionReasonRegexp is the format of the reason of a metav1.Condition.
+var conditionReasonRegexp = regexp.MustCompile(`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`)
+
+// GetPodFailure returns why the pod failed. The disruption of the pod is preferred, then a failed container,
+// then a container which can't start, and the failure falls back to the pod being deleted or restarted otherwise.
+func GetPodFailure(pod corev1.Pod) PodFailure {
+	if condition := DisruptionCondition(pod); condition != nil {
+		return disruptionFailure(condition)
+	}
+	statuses := append(slices.Clone(pod.Status.InitContainerStatuses), pod.Status.ContainerStatuses...)
+	var completed *PodFailure
+	for _, status := range statuses {
+		terminated := status.State.Terminated
+		if terminated == nil && status.RestartCount > 0 {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil {
+			continue
+		}
+		failure := containerTerminationFailure(status.Name, terminated)
+		if terminated.ExitCode != 0 || terminated.Reason == "OOMKilled" {
+			return failure
+		}
+		if completed == nil {
+			completed = &failure
+		}
+	}
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && containerWaitingFailureReasons.Has(waiting.Reason) {
+			message := fmt.Sprintf("container %s is waiting with %s", status.Name, waiting.Reason)
+			if waiting.Message != "" {
+				message += ": " + truncateMessage(waiting.Message)
+			}
+			return PodFailure{Reason: waiting.Reason, Message: message}
+		}
+	}
+	if completed != nil {
+		return *completed
+	}
+	if PodDeleted(pod) {
+		return PodFailure{Reason: "PodDeleted", Message: "pod was deleted"}
+	}
+	return PodFailure{Reason: "ContainerRestarted", Message: "a container was restarted"}
+}
+
+func disruptionFailure(condition *corev1.PodCondition) PodFailure {
+	reason := condition.Reason
+	if !conditionReasonRegexp.MatchString(reason) {
+		reason = "PodDisrupted"
+	}
+	message, ok := disruptionMessages[condition.Reason]
+	if !ok {
+		message = "pod was disrupted"
+	}
+	if condition.Message != "" {
+		message += ": " + truncateMessage(condition.Message)
+	}
+	return PodFailure{Reason: reason, Message: message}
+}
+
+func containerTerminationFailure(container string, terminated *corev1.ContainerStateTerminated) PodFailure {
+	reason := terminated.Reason
+	if !conditionReasonRegexp.MatchString(reason) {
+		reason = "ContainerTerminated"
+	}
+	message := fmt.Sprintf("container %s terminated with exit code %d", container, terminated.ExitCode)
+	if terminated.Reason != "" {
+		message += fmt.Sprintf(" (%s)", terminated.Reason)
+	}
+	if terminated.Message != "" {
+		message += ": " + truncateMessage(terminated.Message)
+	}
+	return PodFailure{Reason: reason, Message: message}
+}
+
+func truncateMessage(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) <= maxTerminationMessageLength {
+		return message
+	}
+	return strings.ToValidUTF8(message[:maxTerminationMessageLength], "") + "..."
+}
+
 // LeaderPod check is the pod is a leader pod
 func LeaderPod(pod corev1.Pod) bool {
 	return pod.Labels[leaderworkerset.WorkerIndexLabelKey] == "0"
@@ -159,6 +299,26 @@ func AddLWSVariables(pod *corev1.Pod) error {
 	return nil
 }
 
+// AddLWSTemplateVariables adds environment variables to every container of a pod template whose pods
+// are not mutated by the pod webhook. The leader name variable comes first, so that the leader address
+// can reference it as $(LWS_LEADER_NAME) when the leader name is only known to the pod itself.
+func AddLWSTemplateVariables(spec *corev1.PodSpec, leaderName corev1.EnvVar, leaderAddress string, size int32) {
+	leaderAddressEnvVar := corev1.EnvVar{
+		Name:  leaderworkerset.LwsLeaderAddress,
+		Value: leaderAddress,
+	}
+	sizeEnvVar := corev1.EnvVar{
+		Name:  leaderworkerset.LwsGroupSize,
+		Value: fmt.Sprint(size),
+	}
+	for i := range spec.Containers {
+		addEnvVarsIfNotExists(&spec.Containers[i], leaderName, leaderAddressEnvVar, sizeEnvVar)
+	}
+	for i := range spec.InitContainers {
+		addEnvVarsIfNotExists(&spec.InitContainers[i], leaderName, leaderAddressEnvVar, sizeEnvVar)
+	}
+}
+
 // IsPodReady returns true if a pod is ready; false otherwise.
 func IsPodReady(pod *corev1.Pod) bool {
 	return IsPodReadyConditionTrue(pod.Status)
@@ -199,3 +359,82 @@ func GetPodConditionFromList(conditions []corev1.PodCondition, conditionType cor
 	}
 	return -1, nil
 }
+
+// GenGroupUniqueKey returns the unique key shared by the pods of a group, or of a subgroup.
+func GenGroupUniqueKey(ns string, podName string) string {
+	return utils.Sha1Hash(fmt.Sprintf("%s/%s", ns, podName))
+}
+
+// SetExclusiveAffinities set the pod affinity/anti-affinity
+func SetExclusiveAffinities(pod *corev1.Pod, groupUniqueKey string, topologyKey string, podAffinityKey string) {
+	if exclusiveAffinityApplied(*pod, topologyKey) {
+		return
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.PodAffinity == nil {
+		pod.Spec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	if pod.Spec.Affinity.PodAntiAffinity == nil {
+		pod.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	// Pod affinity ensures the pods of this set land on the same topology domain.
+	pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      podAffinityKey,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{groupUniqueKey},
+				},
+			}},
+			TopologyKey: topologyKey,
+		})
+	// Pod anti-affinity ensures exclusively this set lands on the topology, preventing multiple sets per topology domain.
+	pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      podAffinityKey,
+					Operator: metav1.LabelSelectorOpExists,
+				},
+				{
+					Key:      podAffinityKey,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{groupUniqueKey},
+				},
+			}},
+			TopologyKey: topologyKey,
+		})
+}
+
+// exclusiveAffinityApplied return true if the exclusive placement terms have been applied
+func exclusiveAffinityApplied(pod corev1.Pod, topologyKey string) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAffinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
+		return false
+	}
+	hasAffinity := false
+	hasAntiAffinity := false
+	for _, podAffinityTerm := range pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if podAffinityTerm.TopologyKey == topologyKey {
+			hasAffinity = true
+		}
+	}
+	for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey == topologyKey {
+			hasAntiAffinity = true
+		}
+	}
+	return hasAffinity && hasAntiAffinity
+}
+
+// GetSubGroupIndex returns the index of the subgroup that the worker with the given index belongs to.
+func GetSubGroupIndex(podCount int, subGroupSize int, workerIndex int) string {
+	if (podCount-1)%subGroupSize == 0 {
+		// Leader is considered as extra pod, it is part of the first group
+		return fmt.Sprint((workerIndex - 1) / subGroupSize)
+	}
+	return fmt.Sprint(workerIndex / subGroupSize)
+}
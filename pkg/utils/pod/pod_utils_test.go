@@ -18,10 +18,14 @@ package pod
 
 import (
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/test/wrappers"
 )
 
@@ -100,6 +104,191 @@ func TestContainerRestarted(t *testing.T) {
 	}
 }
 
+func TestGetPodFailure(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name          string
+		pod           corev1.Pod
+		expectFailure PodFailure
+	}{
+		{
+			name: "OOMKilled container restarted",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "sidecar"},
+						{
+							Name:         "worker",
+							RestartCount: 1,
+							LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 137,
+								Reason:   "OOMKilled",
+							}},
+						},
+					},
+				},
+			},
+			expectFailure: PodFailure{Reason: "OOMKilled", Message: "container worker terminated with exit code 137 (OOMKilled)"},
+		},
+		{
+			name: "failed container with a termination message",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "worker",
+						RestartCount: 2,
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Reason:   "Error",
+							Message:  "CUDA error: out of memory\n",
+						}},
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "Error", Message: "container worker terminated with exit code 1 (Error): CUDA error: out of memory"},
+		},
+		{
+			name: "failed init container without a reason, truncated termination message",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "init",
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 2,
+							Message:  strings.Repeat("a", 300),
+						}},
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "ContainerTerminated", Message: "container init terminated with exit code 2: " + strings.Repeat("a", 256) + "..."},
+		},
+		{
+			name: "image pull error is preferred over a completed container",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:         "sidecar",
+							RestartCount: 1,
+							LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+								Reason: "Completed",
+							}},
+						},
+						{
+							Name: "worker",
+							State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+								Reason:  "ImagePullBackOff",
+								Message: "Back-off pulling image \"busybox:missing\"",
+							}},
+						},
+					},
+				},
+			},
+			expectFailure: PodFailure{Reason: "ImagePullBackOff", Message: "container worker is waiting with ImagePullBackOff: Back-off pulling image \"busybox:missing\""},
+		},
+		{
+			name: "completed container restarted",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "worker",
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							Reason: "Completed",
+						}},
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "Completed", Message: "container worker terminated with exit code 0 (Completed)"},
+		},
+		{
+			name: "preempted pod",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{
+						Type:    corev1.DisruptionTarget,
+						Status:  corev1.ConditionTrue,
+						Reason:  "PreemptionByScheduler",
+						Message: "default-scheduler: preempting to accommodate a higher priority pod",
+					}},
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name: "worker",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 143,
+							Reason:   "Error",
+						}},
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "PreemptionByScheduler", Message: "pod was preempted: default-scheduler: preempting to accommodate a higher priority pod"},
+		},
+		{
+			name: "pod disrupted for an unknown reason",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.DisruptionTarget,
+						Status: corev1.ConditionTrue,
+						Reason: "DeletionByPodGC",
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "DeletionByPodGC", Message: "pod was disrupted"},
+		},
+		{
+			name: "pod no longer disrupted",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.DisruptionTarget,
+						Status: corev1.ConditionFalse,
+						Reason: "EvictionByEvictionAPI",
+					}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "PodDeleted", Message: "pod was deleted"},
+		},
+		{
+			name: "deleted pod",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "worker"}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "PodDeleted", Message: "pod was deleted"},
+		},
+		{
+			name: "restarted container without a known termination",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "worker", RestartCount: 1}},
+				},
+			},
+			expectFailure: PodFailure{Reason: "ContainerRestarted", Message: "a container was restarted"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expectFailure, GetPodFailure(tc.pod)); diff != "" {
+				t.Errorf("Unexpected failure (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestAddLWSVariables(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -173,3 +362,246 @@ func TestAddLWSVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestGenGroupUniqueKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		podName     string
+		namespace   string
+		expectedKey string
+	}{
+		{
+			name:        "same namespace, pod name",
+			podName:     "test-sample",
+			namespace:   "default",
+			expectedKey: "95e88034e460983f51a9952fe128729fbc0663b5",
+		},
+		{
+			name:        "same namespace, different pod name",
+			podName:     "podName",
+			namespace:   "default",
+			expectedKey: "390b34ab671d29e9997d7d4252b8bbf8da02f5b7",
+		},
+		{
+			name:        "different namespace, same pod name",
+			podName:     "test-sample",
+			namespace:   "leaderworkerset",
+			expectedKey: "39f5d7e9122b9d94d3932e3720b43fd3b56347e8",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key := GenGroupUniqueKey(tc.namespace, tc.podName)
+			if diff := cmp.Diff(tc.expectedKey, key); diff != "" {
+				t.Errorf("unexpected key %s", diff)
+			}
+		})
+	}
+}
+
+func TestSetExclusiveAffinities(t *testing.T) {
+	tests := []struct {
+		name           string
+		pod            *corev1.Pod
+		groupUniqueKey string
+		topologyKey    string
+		podAffinityKey string
+		expectedPod    *corev1.Pod
+	}{
+		{
+			name: "Pod with only Exclusive Topology Annotation",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
+				},
+			},
+			groupUniqueKey: "test-key",
+			topologyKey:    "topologyKey",
+			podAffinityKey: leaderworkerset.GroupUniqueHashLabelKey,
+			expectedPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								TopologyKey: "topologyKey",
+								LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+									{
+										Key:      "leaderworkerset.sigs.k8s.io/group-key",
+										Operator: "In",
+										Values:   []string{"test-key"},
+									},
+								}},
+							}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								TopologyKey: "topologyKey",
+								LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+									{
+										Key:      "leaderworkerset.sigs.k8s.io/group-key",
+										Operator: "Exists",
+									},
+									{
+										Key:      "leaderworkerset.sigs.k8s.io/group-key",
+										Operator: "NotIn",
+										Values:   []string{"test-key"},
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Pod with Exclusive Annotation, Affinity, and AntiAffinity",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+			groupUniqueKey: "test-key",
+			topologyKey:    "topologyKey",
+			podAffinityKey: leaderworkerset.GroupUniqueHashLabelKey,
+			expectedPod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetExclusiveAffinities(tc.pod, tc.groupUniqueKey, tc.topologyKey, tc.podAffinityKey)
+			if diff := cmp.Diff(tc.pod, tc.expectedPod); diff != "" {
+				t.Errorf("unexpected set exclusive affinities operation: %s", diff)
+			}
+		})
+	}
+}
+
+func TestExclusiveAffinityApplied(t *testing.T) {
+	tests := []struct {
+		name                              string
+		pod                               corev1.Pod
+		expectedAppliedExclusivePlacement bool
+		topologyKey                       string
+	}{
+		{
+			name: "Has annotiation, Pod Affinity and Pod AntiAffinity",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+			expectedAppliedExclusivePlacement: true,
+			topologyKey:                       "topologyKey",
+		},
+		{
+			name: "Has annotiation, Pod Affinity, doesn't have Pod AntiAffinity",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+			expectedAppliedExclusivePlacement: false,
+			topologyKey:                       "topologyKey",
+		},
+		{
+			name: "Has annotiation, Pod AntiAffinity, doesn't have Pod Affinity",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+			expectedAppliedExclusivePlacement: false,
+			topologyKey:                       "topologyKey",
+		},
+		{
+			name: "Has annotiation, Pod Affinity and Pod AntiAffinity, Topology Key doesn't match",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey1"}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+						},
+					},
+				},
+			},
+			expectedAppliedExclusivePlacement: false,
+			topologyKey:                       "topologyKey",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			appliedExclusivePlacement := exclusiveAffinityApplied(tc.pod, tc.topologyKey)
+			if appliedExclusivePlacement != tc.expectedAppliedExclusivePlacement {
+				t.Errorf("Expected value %t, got %t", tc.expectedAppliedExclusivePlacement, appliedExclusivePlacement)
+			}
+		})
+	}
+}
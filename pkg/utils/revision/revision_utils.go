@@ -23,10 +23,12 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"strconv"
 
 	"github.com/davecgh/go-spew/spew"
 	appsv1 "k8s.io/api/apps/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -81,7 +83,7 @@ func NewRevision(ctx context.Context, k8sClient client.Client, lws *leaderworker
 		Revision: revision,
 	}
 
-	hash := hashRevision(cr)
+	hash := hashRevision(cr, lws.Status.CollisionCount)
 	if revisionKey == "" {
 		revisionKey = hash
 	}
@@ -90,11 +92,34 @@ func NewRevision(ctx context.Context, k8sClient client.Client, lws *leaderworker
 	return cr, nil
 }
 
-func CreateRevision(ctx context.Context, k8sClient client.Client, revision *appsv1.ControllerRevision, lws *leaderworkerset.LeaderWorkerSet) (*appsv1.ControllerRevision, error) {
-	if err := k8sClient.Create(ctx, revision); err != nil {
-		return nil, err
+// CreateRevision creates the ControllerRevision. If a ControllerRevision with the same name but a different
+// content already exists, collisionCount is incremented and the name is computed again until it is unique.
+// An existing ControllerRevision with the same content is returned as is.
+func CreateRevision(ctx context.Context, k8sClient client.Client, revision *appsv1.ControllerRevision, lws *leaderworkerset.LeaderWorkerSet, collisionCount *int32) (*appsv1.ControllerRevision, error) {
+	clone := revision.DeepCopy()
+	for {
+		err := k8sClient.Create(ctx, clone)
+		if !apierrors.IsAlreadyExists(err) {
+			if err != nil {
+				return nil, err
+			}
+			return clone, nil
+		}
+		exists := &appsv1.ControllerRevision{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(clone), exists); err != nil {
+			return nil, err
+		}
+		if EqualRevision(exists, clone) {
+			return exists, nil
+		}
+		previousHash := hashRevision(clone, collisionCount)
+		*collisionCount++
+		hash := hashRevision(clone, collisionCount)
+		if clone.Labels[leaderworkerset.RevisionKey] == previousHash {
+			clone.Labels[leaderworkerset.RevisionKey] = hash
+		}
+		clone.Name = revisionName(lws.Name, hash, clone.Revision)
 	}
-	return revision, nil
 }
 
 func GetRevisionKey(obj metav1.Object) string {
@@ -284,9 +309,10 @@ func revisionName(prefix string, hash string, revisionNumber int64) string {
 	return fmt.Sprintf("%s-%s-%v", prefix, hash, revisionNumber)
 }
 
-// hashRevision hashes the contents of revision's Data using FNV hashing.
-// The returned hash will be a safe encoded string to avoid bad words.
-func hashRevision(revision *appsv1.ControllerRevision) string {
+// hashRevision hashes the contents of revision's Data using FNV hashing. If probe is not nil and
+// positive, its value is written to the hash as well, so the hash of a revision stays the same
+// until a collision happens. The returned hash will be a safe encoded string to avoid bad words.
+func hashRevision(revision *appsv1.ControllerRevision, probe *int32) string {
 	hf := fnv.New32()
 	if len(revision.Data.Raw) > 0 {
 		hf.Write(revision.Data.Raw)
@@ -294,6 +320,9 @@ func hashRevision(revision *appsv1.ControllerRevision) string {
 	if revision.Data.Object != nil {
 		deepHashObject(hf, revision.Data.Object)
 	}
+	if probe != nil && *probe > 0 {
+		hf.Write([]byte(strconv.FormatInt(int64(*probe), 10)))
+	}
 	return rand.SafeEncodeString(fmt.Sprint(hf.Sum32()))
 }
 
@@ -224,3 +224,59 @@ func TestGetHighestRevision(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateRevision(t *testing.T) {
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	revision, err := NewRevision(context.TODO(), fake.NewClientBuilder().Build(), lws, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A revision with the same name, but the content of another template.
+	collidingRevision := revision.DeepCopy()
+	collidingRevision.Data.Raw = []byte(`{"spec":{"leaderWorkerTemplate":{"$patch":"replace"}}}`)
+
+	tests := []struct {
+		name                   string
+		existingRevisions      []*appsv1.ControllerRevision
+		wantCollisionCount     int32
+		wantRenamedRevisionKey bool
+	}{
+		{
+			name: "no existing revision",
+		},
+		{
+			name:              "existing revision with the same content is returned",
+			existingRevisions: []*appsv1.ControllerRevision{revision.DeepCopy()},
+		},
+		{
+			name:                   "existing revision with another content bumps the collision count",
+			existingRevisions:      []*appsv1.ControllerRevision{collidingRevision},
+			wantCollisionCount:     1,
+			wantRenamedRevisionKey: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder()
+			for _, existing := range tc.existingRevisions {
+				builder.WithObjects(existing)
+			}
+			client := builder.Build()
+			var collisionCount int32
+			created, err := CreateRevision(context.TODO(), client, revision, lws, &collisionCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if collisionCount != tc.wantCollisionCount {
+				t.Errorf("Expected collision count %d, got %d", tc.wantCollisionCount, collisionCount)
+			}
+			if !EqualRevision(revision, created) {
+				t.Errorf("Expected the content of the revision to be kept, got %s", string(created.Data.Raw))
+			}
+			renamed := created.Name != revision.Name
+			if renamed != tc.wantRenamedRevisionKey || (GetRevisionKey(created) != GetRevisionKey(revision)) != tc.wantRenamedRevisionKey {
+				t.Errorf("Unexpected name %s and revision key %s, renamed from %s and %s: %t", created.Name, GetRevisionKey(created), revision.Name, GetRevisionKey(revision), tc.wantRenamedRevisionKey)
+			}
+		})
+	}
+}
@@ -19,7 +19,9 @@ package utils
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -74,3 +76,21 @@ func GetOperatorNamespace() string {
 	}
 	return defaultNamespace
 }
+
+// ParseGroupIndexes parses a comma separated list of group indexes, e.g. "0,2".
+// The indexes are returned in the order of the list, without duplicates.
+func ParseGroupIndexes(value string) ([]int, error) {
+	var indexes []int
+	seen := map[int]bool{}
+	for _, item := range strings.Split(value, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid group index %q, must be a non-negative integer", item)
+		}
+		if !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
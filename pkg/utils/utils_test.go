@@ -63,3 +63,50 @@ func Test_SortByIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestParseGroupIndexes(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{
+			name:  "single index",
+			value: "1",
+			want:  []int{1},
+		},
+		{
+			name:  "indexes with spaces and duplicates",
+			value: "2, 0,2",
+			want:  []int{2, 0},
+		},
+		{
+			name:    "negative index",
+			value:   "0,-1",
+			wantErr: true,
+		},
+		{
+			name:    "not an index",
+			value:   "leader",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseGroupIndexes(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected result: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// conversionWebhookPath is the path the LeaderWorkerSet CRD sends its ConversionReviews to,
+// see config/crd/patches/webhook_in_leaderworkersets.yaml.
+const conversionWebhookPath = "/convert"
+
+// ConversionWebhook converts the LeaderWorkerSets between the served versions using the
+// conversion functions registered in the scheme.
+// The conversion webhook of controller-runtime isn't used since it requires the API types
+// to implement the controller-runtime Hub and Convertible interfaces.
+type ConversionWebhook struct {
+	scheme *runtime.Scheme
+}
+
+// SetupConversionWebhook registers the conversion webhook in the webhook server of the manager.
+func SetupConversionWebhook(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(conversionWebhookPath, &ConversionWebhook{scheme: mgr.GetScheme()})
+	return nil
+}
+
+func (wh *ConversionWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context()).WithValues("webhook", conversionWebhookPath)
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		log.Error(err, "Failed to decode the conversion review")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "conversion review without a request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.convertReview(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Error(err, "Failed to encode the conversion review")
+	}
+}
+
+func (wh *ConversionWebhook) convertReview(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{UID: req.UID}
+	desired, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		resp.Result = conversionFailure(err)
+		return resp
+	}
+	for _, obj := range req.Objects {
+		converted, err := wh.convert(obj.Raw, desired)
+		if err != nil {
+			resp.ConvertedObjects = nil
+			resp.Result = conversionFailure(err)
+			return resp
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+// convert converts the object encoded in raw to the desired version and returns it encoded.
+func (wh *ConversionWebhook) convert(raw []byte, desired schema.GroupVersion) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, err
+	}
+	gvk := typeMeta.GroupVersionKind()
+	if gvk.GroupVersion() == desired {
+		return raw, nil
+	}
+	src, err := wh.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, src); err != nil {
+		return nil, err
+	}
+	dst, err := wh.scheme.New(desired.WithKind(gvk.Kind))
+	if err != nil {
+		return nil, err
+	}
+	if err := wh.scheme.Convert(src, dst, nil); err != nil {
+		return nil, fmt.Errorf("converting %s to %s: %w", gvk, desired, err)
+	}
+	dst.GetObjectKind().SetGroupVersionKind(desired.WithKind(gvk.Kind))
+	return json.Marshal(dst)
+}
+
+func conversionFailure(err error) metav1.Status {
+	return metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	v2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
+)
+
+func TestConversionWebhook(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	v1Object := &v1.LeaderWorkerSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "LeaderWorkerSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "lws",
+			Namespace:   "default",
+			Annotations: map[string]string{v1.ExclusiveKeyAnnotationKey: "topology.kubernetes.io/zone"},
+		},
+		Spec: v1.LeaderWorkerSetSpec{
+			Replicas:             ptr.To[int32](1),
+			LeaderWorkerTemplate: v1.LeaderWorkerTemplate{Size: ptr.To[int32](2), RestartPolicy: v1.DeprecatedDefaultRestartPolicy},
+		},
+	}
+	v2Object := &v2.LeaderWorkerSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v2.GroupVersion.String(), Kind: "LeaderWorkerSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "lws", Namespace: "default"},
+		Spec: v2.LeaderWorkerSetSpec{
+			Replicas: ptr.To[int32](1),
+			LeaderWorkerTemplate: v2.LeaderWorkerTemplate{
+				Size:                 ptr.To[int32](2),
+				RestartPolicy:        v2.NoneRestartPolicy,
+				ExclusiveTopologyKey: ptr.To("topology.kubernetes.io/zone"),
+			},
+		},
+	}
+	v1Converted := v1Object.DeepCopy()
+	v1Converted.Spec.LeaderWorkerTemplate.RestartPolicy = v1.NoneRestartPolicy
+
+	tests := []struct {
+		name            string
+		desiredVersion  string
+		objects         []runtime.Object
+		expectedObjects []runtime.Object
+		expectedStatus  string
+	}{
+		{
+			name:            "v1 to v2",
+			desiredVersion:  v2.GroupVersion.String(),
+			objects:         []runtime.Object{v1Object},
+			expectedObjects: []runtime.Object{v2Object},
+			expectedStatus:  metav1.StatusSuccess,
+		},
+		{
+			name:            "v2 to v1",
+			desiredVersion:  v1.GroupVersion.String(),
+			objects:         []runtime.Object{v2Object},
+			expectedObjects: []runtime.Object{v1Converted},
+			expectedStatus:  metav1.StatusSuccess,
+		},
+		{
+			name:            "objects already in the desired version are returned as is",
+			desiredVersion:  v1.GroupVersion.String(),
+			objects:         []runtime.Object{v1Object, v2Object},
+			expectedObjects: []runtime.Object{v1Object, v1Converted},
+			expectedStatus:  metav1.StatusSuccess,
+		},
+		{
+			name:           "unknown version",
+			desiredVersion: "leaderworkerset.x-k8s.io/v3",
+			objects:        []runtime.Object{v1Object},
+			expectedStatus: metav1.StatusFailure,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			review := &apiextensionsv1.ConversionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+				Request:  &apiextensionsv1.ConversionRequest{UID: types.UID("uid"), DesiredAPIVersion: tc.desiredVersion},
+			}
+			for _, obj := range tc.objects {
+				raw, err := json.Marshal(obj)
+				if err != nil {
+					t.Fatal(err)
+				}
+				review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: raw})
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := httptest.NewRecorder()
+			wh := &ConversionWebhook{scheme: scheme}
+			wh.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, conversionWebhookPath, bytes.NewReader(body)))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Unexpected HTTP code %d: %s", recorder.Code, recorder.Body.String())
+			}
+			got := &apiextensionsv1.ConversionReview{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Response == nil {
+				t.Fatal("Expected a response in the conversion review")
+			}
+			if got.Response.UID != review.Request.UID {
+				t.Errorf("Expected UID %q, got %q", review.Request.UID, got.Response.UID)
+			}
+			if got.Response.Result.Status != tc.expectedStatus {
+				t.Errorf("Expected status %q, got %q: %s", tc.expectedStatus, got.Response.Result.Status, got.Response.Result.Message)
+			}
+			if len(got.Response.ConvertedObjects) != len(tc.expectedObjects) {
+				t.Fatalf("Expected %d converted objects, got %d", len(tc.expectedObjects), len(got.Response.ConvertedObjects))
+			}
+			for i, expected := range tc.expectedObjects {
+				gotObject, err := scheme.New(expected.GetObjectKind().GroupVersionKind())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.Unmarshal(got.Response.ConvertedObjects[i].Raw, gotObject); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(expected, gotObject); diff != "" {
+					t.Errorf("Unexpected converted object %d (-want,+got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid body", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		wh := &ConversionWebhook{scheme: scheme}
+		wh.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, conversionWebhookPath, bytes.NewReader([]byte("{"))))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected HTTP code %d, got %d", http.StatusBadRequest, recorder.Code)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// policyFor returns the defaults and the bounds of the leaderworkersets of the namespace, the fields
+// of the first override matching the labels of the namespace take precedence over the cluster wide ones.
+// It returns nil if no defaults are configured.
+func (r *LeaderWorkerSetWebhook) policyFor(ctx context.Context, namespace string) (*configapi.LeaderWorkerSetPolicy, error) {
+	if r.defaults == nil {
+		return nil, nil
+	}
+	defaults := r.defaults.Load()
+	if defaults == nil {
+		return nil, nil
+	}
+	policy := defaults.LeaderWorkerSetPolicy
+	if len(defaults.NamespaceOverrides) == 0 || r.client == nil {
+		return &policy, nil
+	}
+
+	ns := metav1.PartialObjectMetadata{}
+	ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	if err := r.client.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return nil, fmt.Errorf("getting namespace %s: %w", namespace, err)
+	}
+	for _, override := range defaults.NamespaceOverrides {
+		selector, err := metav1.LabelSelectorAsSelector(override.NamespaceSelector)
+		if err != nil {
+			// Rejected by the validation of the configuration.
+			continue
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			mergePolicy(&policy, &override.LeaderWorkerSetPolicy)
+			break
+		}
+	}
+	return &policy, nil
+}
+
+// mergePolicy overrides the fields of policy set in override.
+func mergePolicy(policy, override *configapi.LeaderWorkerSetPolicy) {
+	if override.ExclusiveTopology != "" {
+		policy.ExclusiveTopology = override.ExclusiveTopology
+	}
+	if override.RestartPolicy != "" {
+		policy.RestartPolicy = override.RestartPolicy
+	}
+	if override.StartupPolicy != "" {
+		policy.StartupPolicy = override.StartupPolicy
+	}
+	if override.SubdomainPolicy != "" {
+		policy.SubdomainPolicy = override.SubdomainPolicy
+	}
+	if override.MaxSurge != nil {
+		policy.MaxSurge = override.MaxSurge
+	}
+	if override.MaxSize != nil {
+		policy.MaxSize = override.MaxSize
+	}
+	if override.MaxReplicas != nil {
+		policy.MaxReplicas = override.MaxReplicas
+	}
+}
+
+// applyPolicyDefaults applies the defaults of the policy to a leaderworkerset being created. The fields
+// defaulted by the CRD are only changed when they hold their built-in default, since unset fields can't
+// be told apart from them. Nothing is applied to a leaderworkerset opting out with the skip-policy-defaults
+// annotation, so that it keeps the built-in values it sets explicitly.
+func applyPolicyDefaults(lws *v1.LeaderWorkerSet, policy *configapi.LeaderWorkerSetPolicy) {
+	if lws.Annotations[v1.SkipPolicyDefaultsAnnotationKey] == "true" {
+		return
+	}
+	if policy.ExclusiveTopology != "" {
+		if _, found := lws.Annotations[v1.ExclusiveKeyAnnotationKey]; !found {
+			if lws.Annotations == nil {
+				lws.Annotations = map[string]string{}
+			}
+			lws.Annotations[v1.ExclusiveKeyAnnotationKey] = policy.ExclusiveTopology
+		}
+	}
+	if policy.RestartPolicy != "" {
+		if restartPolicy := lws.Spec.LeaderWorkerTemplate.RestartPolicy; restartPolicy == "" || restartPolicy == v1.RecreateGroupOnPodRestart {
+			lws.Spec.LeaderWorkerTemplate.RestartPolicy = policy.RestartPolicy
+		}
+	}
+	if policy.StartupPolicy != "" {
+		if lws.Spec.StartupPolicy == "" || lws.Spec.StartupPolicy == v1.LeaderCreatedStartupPolicy {
+			lws.Spec.StartupPolicy = policy.StartupPolicy
+		}
+	}
+	if policy.SubdomainPolicy != "" {
+		if lws.Spec.NetworkConfig == nil {
+			lws.Spec.NetworkConfig = &v1.NetworkConfig{}
+		}
+		if lws.Spec.NetworkConfig.SubdomainPolicy == nil {
+			subdomainPolicy := policy.SubdomainPolicy
+			lws.Spec.NetworkConfig.SubdomainPolicy = &subdomainPolicy
+		}
+	}
+	if policy.MaxSurge != nil && (lws.Spec.RolloutStrategy.Type == "" || lws.Spec.RolloutStrategy.Type == v1.RollingUpdateStrategyType) {
+		if lws.Spec.RolloutStrategy.RollingUpdateConfiguration == nil {
+			lws.Spec.RolloutStrategy.RollingUpdateConfiguration = &v1.RollingUpdateConfiguration{
+				MaxUnavailable: intstr.FromInt32(1),
+				MaxSurge:       *policy.MaxSurge,
+			}
+		} else if maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge; maxSurge == intstr.FromInt32(0) {
+			lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge = *policy.MaxSurge
+		}
+	}
+}
+
+// validatePolicyBounds validates the size and the replicas of the leaderworkerset against the bounds of the policy.
+// Only the values which are set or increased are validated, so that lowering a bound doesn't block the updates of
+// the existing leaderworkersets. oldLws is nil when the leaderworkerset is created.
+func validatePolicyBounds(lws, oldLws *v1.LeaderWorkerSet, policy *configapi.LeaderWorkerSetPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if policy.MaxSize != nil && lws.Spec.LeaderWorkerTemplate.Size != nil {
+		size := *lws.Spec.LeaderWorkerTemplate.Size
+		if size > *policy.MaxSize && (oldLws == nil || oldLws.Spec.LeaderWorkerTemplate.Size == nil || size > *oldLws.Spec.LeaderWorkerTemplate.Size) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "size"), size,
+				fmt.Sprintf("must be no greater than %d in namespace %s", *policy.MaxSize, lws.Namespace)))
+		}
+	}
+	if policy.MaxReplicas != nil && lws.Spec.Replicas != nil {
+		replicas := *lws.Spec.Replicas
+		if replicas > *policy.MaxReplicas && (oldLws == nil || oldLws.Spec.Replicas == nil || replicas > *oldLws.Spec.Replicas) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), replicas,
+				fmt.Sprintf("must be no greater than %d in namespace %s", *policy.MaxReplicas, lws.Namespace)))
+		}
+	}
+	return allErrs
+}
+
+const validatingLeaderWorkerSetScaleWebhookPath = "/validate-leaderworkerset-x-k8s-io-v1-leaderworkerset-scale"
+
+//+kubebuilder:webhook:path=/validate-leaderworkerset-x-k8s-io-v1-leaderworkerset-scale,mutating=false,failurePolicy=fail,sideEffects=None,groups=leaderworkerset.x-k8s.io,resources=leaderworkersets/scale,verbs=update,versions=v1,name=vscaleleaderworkerset.kb.io,admissionReviewVersions=v1
+
+// scaleBoundsHandler validates the replicas set through the scale subresource, e.g. by kubectl scale or
+// a HorizontalPodAutoscaler, against the bounds of the namespace, since the validating webhook of the
+// leaderworkersets isn't called for the subresource.
+type scaleBoundsHandler struct {
+	webhook *LeaderWorkerSetWebhook
+}
+
+func (h *scaleBoundsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var scale, oldScale autoscalingv1.Scale
+	if err := json.Unmarshal(req.Object.Raw, &scale); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &oldScale); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if err := h.webhook.validateBounds(ctx, scaledLeaderWorkerSet(req.Namespace, &scale), scaledLeaderWorkerSet(req.Namespace, &oldScale)).ToAggregate(); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// scaledLeaderWorkerSet returns a leaderworkerset holding the replicas of the scale, for validatePolicyBounds.
+func scaledLeaderWorkerSet(namespace string, scale *autoscalingv1.Scale) *v1.LeaderWorkerSet {
+	return &v1.LeaderWorkerSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: scale.Name},
+		Spec:       v1.LeaderWorkerSetSpec{Replicas: &scale.Spec.Replicas},
+	}
+}
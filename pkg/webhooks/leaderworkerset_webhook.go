@@ -18,34 +18,93 @@ package webhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"math"
-	"strconv"
+	"sync/atomic"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
-	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
-
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/tracing"
+	"sigs.k8s.io/lws/pkg/utils"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
 )
 
-type LeaderWorkerSetWebhook struct{}
+const mutatingLeaderWorkerSetWebhookPath = "/mutate-leaderworkerset-x-k8s-io-v1-leaderworkerset"
+
+type LeaderWorkerSetWebhook struct {
+	// client is used to look up the namespaces of the leaderworkersets for their defaults, and to validate
+	// their pod templates with dry-run creations of StatefulSets, both are skipped if it is nil.
+	client client.Client
+	// apiReader is used to look up the nodes when warning about exclusive placement, the lookup
+	// is skipped if it is nil. The nodes are read from the apiserver so that they aren't cached.
+	apiReader client.Reader
+	// defaults are the cluster wide defaults and bounds of the leaderworkersets, swapped when
+	// the configuration is reloaded. Nothing is applied if it is nil or holds nil.
+	defaults *atomic.Pointer[configapi.LeaderWorkerSetDefaults]
+}
+
+// SetupLeaderWorkerSetWebhook will setup the manager to manage the webhooks,
+// defaults holds the cluster wide defaults and bounds of the leaderworkersets and may be nil.
+func SetupLeaderWorkerSetWebhook(mgr ctrl.Manager, defaults *atomic.Pointer[configapi.LeaderWorkerSetDefaults]) error {
+	lwsWebhook := &LeaderWorkerSetWebhook{client: mgr.GetClient(), apiReader: mgr.GetAPIReader(), defaults: defaults}
+	// The defaulting webhook is registered manually to return warnings for the deprecated values
+	// rewritten by Default, since the validating webhook only receives the defaulted object.
+	mutatingWebhook := admission.WithCustomDefaulter(mgr.GetScheme(), &v1.LeaderWorkerSet{}, lwsWebhook)
+	mutatingWebhook.Handler = &deprecationWarningHandler{Handler: mutatingWebhook.Handler}
+	mgr.GetWebhookServer().Register(mutatingLeaderWorkerSetWebhookPath, mutatingWebhook)
+	mgr.GetWebhookServer().Register(validatingLeaderWorkerSetScaleWebhookPath, &webhook.Admission{Handler: &scaleBoundsHandler{webhook: lwsWebhook}})
 
-// SetupLeaderWorkerSetWebhook will setup the manager to manage the webhooks
-func SetupLeaderWorkerSetWebhook(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&v1.LeaderWorkerSet{}).
-		WithDefaulter(&LeaderWorkerSetWebhook{}).
-		WithValidator(&LeaderWorkerSetWebhook{}).
+		WithValidator(lwsWebhook).
 		Complete()
 }
 
+// deprecationWarningHandler wraps the defaulting handler and adds warnings for the deprecated
+// values of the leaderworkerset in the request.
+type deprecationWarningHandler struct {
+	admission.Handler
+}
+
+func (h *deprecationWarningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := h.Handler.Handle(ctx, req)
+	if !resp.Allowed || len(req.Object.Raw) == 0 {
+		return resp
+	}
+	lws := &v1.LeaderWorkerSet{}
+	if err := json.Unmarshal(req.Object.Raw, lws); err != nil {
+		return resp
+	}
+	resp.Warnings = append(resp.Warnings, deprecationWarnings(lws)...)
+	return resp
+}
+
+// deprecationWarnings returns the warnings for the deprecated values set in the leaderworkerset,
+// it must be called before the leaderworkerset is defaulted.
+func deprecationWarnings(lws *v1.LeaderWorkerSet) admission.Warnings {
+	var warnings admission.Warnings
+	if lws.Spec.LeaderWorkerTemplate.RestartPolicy == v1.DeprecatedDefaultRestartPolicy {
+		warnings = append(warnings, fmt.Sprintf("spec.leaderWorkerTemplate.restartPolicy: %q is deprecated and will be replaced with %q, use %q instead",
+			v1.DeprecatedDefaultRestartPolicy, v1.NoneRestartPolicy, v1.NoneRestartPolicy))
+	}
+	return warnings
+}
+
 //+kubebuilder:webhook:path=/mutate-leaderworkerset-x-k8s-io-v1-leaderworkerset,mutating=true,failurePolicy=fail,sideEffects=None,groups=leaderworkerset.x-k8s.io,resources=leaderworkersets,verbs=create;update,versions=v1,name=mleaderworkerset.kb.io,admissionReviewVersions=v1
 
 var _ webhook.CustomDefaulter = &LeaderWorkerSetWebhook{}
@@ -53,6 +112,24 @@ var _ webhook.CustomDefaulter = &LeaderWorkerSetWebhook{}
 // Default implements webhook.Defaulter so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	lws := obj.(*v1.LeaderWorkerSet)
+	_, span := tracing.Tracer().Start(ctx, "LeaderWorkerSetWebhook.Default", trace.WithAttributes(
+		attribute.String("namespace", lws.Namespace),
+		attribute.String("leaderworkerset", lws.Name),
+	))
+	defer span.End()
+
+	// The cluster wide defaults are only applied when the leaderworkerset is created, the fields
+	// defaulted by the CRD can't be told apart from the ones set by the user on updates.
+	if req, err := admission.RequestFromContext(ctx); err != nil || req.Operation == admissionv1.Create {
+		policy, err := r.policyFor(ctx, lws.Namespace)
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			applyPolicyDefaults(lws, policy)
+		}
+	}
+
 	if lws.Spec.LeaderWorkerTemplate.RestartPolicy == "" {
 		lws.Spec.LeaderWorkerTemplate.RestartPolicy = v1.RecreateGroupOnPodRestart
 	}
@@ -90,33 +167,21 @@ func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object
 var _ webhook.CustomValidator = &LeaderWorkerSetWebhook{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+// The structural validation, e.g. of the size, subGroupSize, rollout strategy and the immutable fields,
+// is expressed as validation rules in the CRD, so only the rules that can't be expressed there are validated here.
 func (r *LeaderWorkerSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	allErrs := r.generalValidate(obj)
-	return nil, allErrs.ToAggregate()
+	allErrs = append(allErrs, r.validatePodTemplates(ctx, obj.(*v1.LeaderWorkerSet))...)
+	allErrs = append(allErrs, r.validateBounds(ctx, obj.(*v1.LeaderWorkerSet), nil)...)
+	return r.warnings(ctx, obj.(*v1.LeaderWorkerSet)), allErrs.ToAggregate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	allErrs := r.generalValidate(newObj)
-	specPath := field.NewPath("spec")
-
-	oldLws := oldObj.(*v1.LeaderWorkerSet)
-	newLws := newObj.(*v1.LeaderWorkerSet)
-	allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.Size, *oldLws.Spec.LeaderWorkerTemplate.Size, field.NewPath("spec", "leaderWorkerTemplate", "size"))...)
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, *oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, field.NewPath("spec", "leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"))...)
-	}
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot enable subGroupSize after the lws is already created"))
-	}
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot remove subGroupSize after enabled"))
-	}
-	if newLws.Spec.NetworkConfig != nil && newLws.Spec.NetworkConfig.SubdomainPolicy == nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("networkConfig", "subdomainPolicy"), oldLws.Spec.NetworkConfig.SubdomainPolicy, "cannot set subdomainPolicy as null"))
-	}
-
-	return nil, allErrs.ToAggregate()
+	allErrs = append(allErrs, r.validatePodTemplates(ctx, newObj.(*v1.LeaderWorkerSet))...)
+	allErrs = append(allErrs, r.validateBounds(ctx, newObj.(*v1.LeaderWorkerSet), oldObj.(*v1.LeaderWorkerSet))...)
+	return r.warnings(ctx, newObj.(*v1.LeaderWorkerSet)), allErrs.ToAggregate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -132,117 +197,180 @@ func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) field.Error
 	// Since the lws name is used as the name for headless service, it must be DNS-1035 compliant
 	ValidateName := apivalidation.NameIsDNS1035Label
 	allErrs := apivalidation.ValidateObjectMeta(&lws.ObjectMeta, true, apivalidation.ValidateNameFunc(ValidateName), field.NewPath("metadata"))
-	// Ensure replicas and groups number are valid
-	if lws.Spec.Replicas != nil && *lws.Spec.Replicas < 0 {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, "replicas must be equal or greater than 0"))
-	}
-	if *lws.Spec.LeaderWorkerTemplate.Size < 1 {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "size"), lws.Spec.LeaderWorkerTemplate.Size, "size must be equal or greater than 1"))
+
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil {
+		if _, foundSubEpKey := lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey], "cannot have subgroup-exclusive-topology without subGroupSize set"))
+		}
 	}
-	if int64(*lws.Spec.Replicas)*int64(*lws.Spec.LeaderWorkerTemplate.Size) > math.MaxInt32 {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, fmt.Sprintf("the product of replicas and worker replicas must not exceed %d", math.MaxInt32)))
+	if value, found := lws.Annotations[v1.RestartGroupsAnnotationKey]; found {
+		if _, err := utils.ParseGroupIndexes(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.RestartGroupsAnnotationKey), value, err.Error()))
+		}
 	}
+	allErrs = append(allErrs, validateTPUTopology(specPath, lws)...)
 
-	maxUnavailable := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable
-	maxUnavailablePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxUnavailable")
-	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxUnavailable, maxUnavailablePath)...)
-		// This is aligned with Statefulset.
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxUnavailable, maxUnavailablePath)...)
-	}
+	return allErrs
+}
 
-	maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
-	maxSurgePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxSurge")
-	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxSurge, maxSurgePath)...)
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxSurge, maxSurgePath)...)
-	}
-	maxUnavailableValue, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(*lws.Spec.Replicas), false)
+// validateBounds validates the leaderworkerset against the bounds configured for its namespace.
+func (r *LeaderWorkerSetWebhook) validateBounds(ctx context.Context, lws, oldLws *v1.LeaderWorkerSet) field.ErrorList {
+	policy, err := r.policyFor(ctx, lws.Namespace)
 	if err != nil {
-		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "invalid value"))
+		return field.ErrorList{field.InternalError(field.NewPath("metadata", "namespace"), err)}
 	}
-	maxSurgeValue, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, int(*lws.Spec.Replicas), true)
-	if err != nil {
-		allErrs = append(allErrs, field.Invalid(maxSurgePath, maxSurge, "invalid value"))
+	if policy == nil {
+		return nil
 	}
-	if maxUnavailableValue == 0 && maxSurgeValue == 0 {
-		// Both MaxSurge and MaxUnavailable cannot be zero.
-		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "must not be 0 when `maxSurge` is 0"))
+	return validatePolicyBounds(lws, oldLws, policy)
+}
+
+// warnings returns the warnings for the settings of the leaderworkerset that are valid but likely
+// to behave differently than expected.
+func (r *LeaderWorkerSetWebhook) warnings(ctx context.Context, lws *v1.LeaderWorkerSet) admission.Warnings {
+	var warnings admission.Warnings
+	specPath := field.NewPath("spec")
+
+	if lws.Spec.StartupPolicy == v1.LeaderReadyStartupPolicy {
+		leaderTemplate := &lws.Spec.LeaderWorkerTemplate.WorkerTemplate
+		if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+			leaderTemplate = lws.Spec.LeaderWorkerTemplate.LeaderTemplate
+		}
+		if !hasReadinessProbe(leaderTemplate.Spec) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s is set but the leader pod has no readinessProbe, the workers will be created as soon as the leader containers are running",
+				specPath.Child("startupPolicy"), v1.LeaderReadyStartupPolicy))
+		}
 	}
 
-	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, validateUpdateSubGroupPolicy(specPath, lws)...)
-	} else {
-		if _, foundSubEpKey := lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
-			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey], "cannot have subgroup-exclusive-topology without subGroupSize set"))
+	if lws.Spec.NetworkConfig != nil && lws.Spec.NetworkConfig.SubdomainPolicy != nil &&
+		*lws.Spec.NetworkConfig.SubdomainPolicy == v1.SubdomainUniquePerReplica && lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
+		maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
+		if maxSurgeValue, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, int(*lws.Spec.Replicas), true); err == nil && maxSurgeValue > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: a headless service is created for each surge replica when subdomainPolicy is %s, and deleted once the rolling update completes",
+				specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxSurge"), v1.SubdomainUniquePerReplica))
 		}
 	}
 
-	return allErrs
+	annotationsPath := field.NewPath("metadata", "annotations")
+	for _, key := range []string{v1.ExclusiveKeyAnnotationKey, v1.SubGroupExclusiveKeyAnnotationKey} {
+		topologyKey, found := lws.Annotations[key]
+		if !found {
+			continue
+		}
+		if inUse, err := r.nodeLabelKeyInUse(ctx, topologyKey); err != nil {
+			logf.FromContext(ctx).Error(err, "Checking whether the exclusive topology key is used by any node", "topologyKey", topologyKey)
+		} else if !inUse {
+			warnings = append(warnings, fmt.Sprintf("%s: no node has the label %q, the pods won't be scheduled until such nodes are available",
+				annotationsPath.Key(key), topologyKey))
+		}
+	}
+	return warnings
 }
 
-// This is mostly inspired by https://github.com/kubernetes/kubernetes/blob/be4b7176dc131ea842cab6882cd4a06dbfeed12a/pkg/apis/apps/validation/validation.go#L460,
-// but it's not importable.
-
-// validatePositiveIntOrPercent tests if a given value is a valid int or percentage.
-func validatePositiveIntOrPercent(intOrPercent intstr.IntOrString, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-	switch intOrPercent.Type {
-	case intstr.String:
-		for _, msg := range utilvalidation.IsValidPercent(intOrPercent.StrVal) {
-			allErrs = append(allErrs, field.Invalid(fldPath, intOrPercent, msg))
-		}
-	case intstr.Int:
-		allErrs = append(allErrs, validateNonnegativeField(int64(intOrPercent.IntValue()), fldPath)...)
-	default:
-		allErrs = append(allErrs, field.Invalid(fldPath, intOrPercent, "must be an integer or percentage (e.g '5%%')"))
+// nodeLabelKeyInUse returns true if any node has the given label key. Only the metadata of
+// a single node is listed, rather than caching the nodes of the cluster for the warning.
+func (r *LeaderWorkerSetWebhook) nodeLabelKeyInUse(ctx context.Context, key string) (bool, error) {
+	if r.apiReader == nil {
+		return true, nil
 	}
-	return allErrs
+	nodes := metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+	if err := r.apiReader.List(ctx, &nodes, client.HasLabels{key}, client.Limit(1)); err != nil {
+		return false, err
+	}
+	return len(nodes.Items) > 0, nil
 }
 
-// isNotMoreThan100Percent tests is a value can be represented as a percentage
-// and if this value is not more than 100%.
-func isNotMoreThan100Percent(intOrStringValue intstr.IntOrString, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-	value, isPercent := getPercentValue(intOrStringValue)
-	if !isPercent || value <= 100 {
+// validateLeaderTPUTopology validates the TPU topology of a leader template requesting TPUs against the
+// topology and the number of hosts of the worker template. A leader topology alone is only checked to be valid.
+func validateLeaderTPUTopology(leaderSpecPath *field.Path, leaderSpec corev1.PodSpec, workerTopology string, workerNumHosts int32) field.ErrorList {
+	topologyPath := leaderSpecPath.Child("nodeSelector").Key(acceleratorutils.TpuTopologyNodeSelectorKey)
+	topology, found := leaderSpec.NodeSelector[acceleratorutils.TpuTopologyNodeSelectorKey]
+	if _, err := acceleratorutils.NumTPUHosts(leaderSpec); err != nil {
+		return field.ErrorList{field.Invalid(topologyPath, topology, err.Error())}
+	}
+	if workerNumHosts == 0 {
 		return nil
 	}
-	allErrs = append(allErrs, field.Invalid(fldPath, intOrStringValue, "must not be greater than 100%"))
-	return allErrs
-}
-func getPercentValue(intOrStringValue intstr.IntOrString) (int, bool) {
-	if intOrStringValue.Type != intstr.String {
-		return 0, false
+	if found && topology != workerTopology {
+		return field.ErrorList{field.Invalid(topologyPath, topology, fmt.Sprintf("must match the TPU topology %s of the workerTemplate, the leader is a host of the same slice", workerTopology))}
 	}
-	if len(utilvalidation.IsValidPercent(intOrStringValue.StrVal)) != 0 {
-		return 0, false
+	// Without a topology of its own the leader requests its TPUs from the slice of the workers.
+	leaderSpec.NodeSelector = map[string]string{acceleratorutils.TpuTopologyNodeSelectorKey: workerTopology}
+	numHosts, err := acceleratorutils.NumTPUHosts(leaderSpec)
+	if err != nil {
+		return field.ErrorList{field.Invalid(leaderSpecPath, workerTopology, err.Error())}
+	}
+	if numHosts != workerNumHosts {
+		return field.ErrorList{field.Invalid(leaderSpecPath, workerTopology, fmt.Sprintf("the TPUs requested by the leader imply %d hosts in TPU topology %s, the TPUs requested by the workers imply %d hosts", numHosts, workerTopology, workerNumHosts))}
 	}
-	value, _ := strconv.Atoi(intOrStringValue.StrVal[:len(intOrStringValue.StrVal)-1])
-	return value, true
+	return nil
 }
 
-// validateNonnegativeField validates that given value is not negative.
-func validateNonnegativeField(value int64, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-	if value < 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath, value, "must be grater than or equal to 0"))
+func hasReadinessProbe(spec corev1.PodSpec) bool {
+	for _, container := range spec.Containers {
+		if container.ReadinessProbe != nil {
+			return true
+		}
 	}
-	return allErrs
+	return false
 }
 
-func validateUpdateSubGroupPolicy(specPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+// validateTPUTopology validates that the number of pods requesting TPUs in a group, or in a subgroup
+// when subGroupSize is set, matches the number of hosts implied by the TPU topology node selector
+// and the number of TPUs requested per container. The topology is the one of the worker template,
+// a leader template requesting TPUs is a host of the same slice, so its topology, when set, and the
+// number of hosts implied by its TPUs must match the ones of the workers.
+func validateTPUTopology(specPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
 	allErrs := field.ErrorList{}
-	size := int32(*lws.Spec.LeaderWorkerTemplate.Size)
-	subGroupSize := int32(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize)
+	templatePath := specPath.Child("leaderWorkerTemplate")
+	workerSpec := lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec
+	topology := workerSpec.NodeSelector[acceleratorutils.TpuTopologyNodeSelectorKey]
+	numHosts, err := acceleratorutils.NumTPUHosts(workerSpec)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(templatePath.Child("workerTemplate", "spec", "nodeSelector").Key(acceleratorutils.TpuTopologyNodeSelectorKey), topology, err.Error()))
+		return allErrs
+	}
+
+	// The leader uses the worker template when the leader template is not set.
+	leaderRequestsTPUs := true
+	if leaderTemplate := lws.Spec.LeaderWorkerTemplate.LeaderTemplate; leaderTemplate != nil {
+		leaderRequestsTPUs = acceleratorutils.PodRequestsTPUs(leaderTemplate.Spec)
+		if leaderRequestsTPUs {
+			allErrs = append(allErrs, validateLeaderTPUTopology(templatePath.Child("leaderTemplate", "spec"), leaderTemplate.Spec, topology, numHosts)...)
+		}
+	}
+	if numHosts == 0 || len(allErrs) != 0 {
+		return allErrs
+	}
+	size := *lws.Spec.LeaderWorkerTemplate.Size
+
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil || lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize == nil {
+		tpuPods := size
+		if !leaderRequestsTPUs {
+			tpuPods = size - 1
+		}
+		if tpuPods != numHosts {
+			allErrs = append(allErrs, field.Invalid(templatePath.Child("size"), size, fmt.Sprintf("the number of pods requesting TPUs (%d) must match the number of hosts (%d) in TPU topology %s", tpuPods, numHosts, topology)))
+		}
+		return allErrs
+	}
+
+	subGroupSize := *lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize
+	subGroupSizePath := templatePath.Child("subGroupPolicy", "subGroupSize")
 	if subGroupSize < 1 {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "subGroupSize must be equal or greater than 1"))
+		// Rejected by the subGroupSize validation rules of the CRD.
+		return allErrs
+	}
+	if subGroupSize != numHosts {
+		allErrs = append(allErrs, field.Invalid(subGroupSizePath, subGroupSize, fmt.Sprintf("subGroupSize must match the number of hosts (%d) in TPU topology %s", numHosts, topology)))
 	}
-	if (size%subGroupSize != 0) && ((size-1)%subGroupSize != 0) {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "size or size - 1 must be divisible by subGroupSize"))
+	// When the leader requests TPUs it is part of the first subgroup, otherwise it is the extra pod.
+	if leaderRequestsTPUs && size%subGroupSize != 0 {
+		allErrs = append(allErrs, field.Invalid(subGroupSizePath, subGroupSize, "size must be divisible by subGroupSize when the leader requests TPUs"))
 	}
-	if size < subGroupSize {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "subGroupSize cannot be larger than size"))
+	if !leaderRequestsTPUs && (size-1)%subGroupSize != 0 {
+		allErrs = append(allErrs, field.Invalid(subGroupSizePath, subGroupSize, "size - 1 must be divisible by subGroupSize when the leader does not request TPUs"))
 	}
 	return allErrs
 }
@@ -17,169 +17,553 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	"sigs.k8s.io/lws/test/wrappers"
 )
 
-func TestGetPercentValue(t *testing.T) {
+func TestValidateTPUTopology(t *testing.T) {
+	leaderTPUPodSpec := func(topology, tpus string) corev1.PodSpec {
+		spec := wrappers.MakeLeaderPodSpecWithTPUResource()
+		if topology != "" {
+			spec.NodeSelector = map[string]string{acceleratorutils.TpuTopologyNodeSelectorKey: topology}
+		}
+		spec.Containers[0].Resources.Limits[acceleratorutils.TpuResourceName] = resource.MustParse(tpus)
+		return spec
+	}
+
 	tests := []struct {
-		name           string
-		input          intstr.IntOrString
-		wantOutputVal  int
-		wantOutputBool bool
+		name      string
+		lws       *wrappers.LeaderWorkerSetWrapper
+		wantErr   bool
+		wantField string
 	}{
 		{
-			name: "input type int",
-			input: intstr.IntOrString{
-				Type:   0,
-				IntVal: 1,
-			},
-			wantOutputVal:  0,
-			wantOutputBool: false,
+			name: "no TPUs requested",
+			lws:  wrappers.BuildLeaderWorkerSet("default").Size(3).WorkerTPUTopology("2x2x4"),
 		},
 		{
-			name: "input type string - invalid format",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "1",
-			},
-			wantOutputVal:  0,
-			wantOutputBool: false,
+			name: "no TPU topology set",
+			lws:  wrappers.BuildLeaderWorkerSet("default").Size(3).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()),
 		},
 		{
-			name: "input type string - valid format",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "1%",
-			},
-			wantOutputVal:  1,
-			wantOutputBool: true,
+			name: "leader requests TPUs, size matches the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
 		},
 		{
-			name: "input type string - valid format",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "101%",
-			},
-			wantOutputVal:  101,
-			wantOutputBool: true,
+			name: "leader template unset, size matches the number of hosts",
+			lws: func() *wrappers.LeaderWorkerSetWrapper {
+				lws := wrappers.BuildLeaderWorkerSet("default").Size(4).
+					WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+				return lws
+			}(),
+		},
+		{
+			name: "leader requests TPUs, size doesn't match the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(5).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr: true,
+		},
+		{
+			name: "leader doesn't request TPUs, size - 1 matches the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(5).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+		},
+		{
+			name: "leader doesn't request TPUs, size matches the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr: true,
+		},
+		{
+			name: "invalid TPU topology",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x"),
+			wantErr: true,
+		},
+		{
+			name: "leader requests TPUs, subGroupSize matches the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(8).SubGroupSize(4).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+		},
+		{
+			name: "leader doesn't request TPUs, subGroupSize matches the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(9).SubGroupSize(4).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+		},
+		{
+			name: "subGroupPolicy set without subGroupSize, size matches the number of hosts",
+			lws: func() *wrappers.LeaderWorkerSetWrapper {
+				lws := wrappers.BuildLeaderWorkerSet("default").Size(5).
+					WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &v1.SubGroupPolicy{}
+				return lws
+			}(),
+		},
+		{
+			name: "subGroupPolicy set without subGroupSize, size doesn't match the number of hosts",
+			lws: func() *wrappers.LeaderWorkerSetWrapper {
+				lws := wrappers.BuildLeaderWorkerSet("default").Size(4).
+					WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &v1.SubGroupPolicy{}
+				return lws
+			}(),
+			wantErr:   true,
+			wantField: "spec.leaderWorkerTemplate.size",
+		},
+		{
+			name: "subGroupSize doesn't match the number of hosts",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(9).SubGroupSize(2).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr:   true,
+			wantField: "spec.leaderWorkerTemplate.subGroupPolicy.subGroupSize",
+		},
+		{
+			name: "leader requests TPUs, size not divisible by subGroupSize",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(9).SubGroupSize(4).
+				LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr: true,
+		},
+		{
+			name: "leader doesn't request TPUs, size - 1 not divisible by subGroupSize",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(8).SubGroupSize(4).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr: true,
+		},
+		{
+			name: "leader requests TPUs with the topology of the workers",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				LeaderTemplateSpec(leaderTPUPodSpec("2x2x4", "4")).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+		},
+		{
+			name: "leader requests TPUs with another topology than the workers",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				LeaderTemplateSpec(leaderTPUPodSpec("2x2x2", "4")).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr:   true,
+			wantField: "spec.leaderWorkerTemplate.leaderTemplate.spec.nodeSelector[cloud.google.com/gke-tpu-topology]",
+		},
+		{
+			name: "leader requests another number of TPUs per host than the workers",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				LeaderTemplateSpec(leaderTPUPodSpec("", "8")).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4"),
+			wantErr:   true,
+			wantField: "spec.leaderWorkerTemplate.leaderTemplate.spec",
+		},
+		{
+			name: "leader requests TPUs with an invalid topology",
+			lws: wrappers.BuildLeaderWorkerSet("default").Size(4).
+				LeaderTemplateSpec(leaderTPUPodSpec("2x2x", "4")).
+				WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()),
+			wantErr:   true,
+			wantField: "spec.leaderWorkerTemplate.leaderTemplate.spec.nodeSelector[cloud.google.com/gke-tpu-topology]",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			outputVal, outputBool := getPercentValue(tc.input)
-			if diff := cmp.Diff(tc.wantOutputVal, outputVal); diff != "" {
-				t.Errorf("unexpected result: (-want, +got) %s", diff)
+			errs := validateTPUTopology(field.NewPath("spec"), tc.lws.Obj())
+			if (len(errs) != 0) != tc.wantErr {
+				t.Errorf("unexpected errors: %v", errs)
 			}
-			if diff := cmp.Diff(tc.wantOutputBool, outputBool); diff != "" {
-				t.Errorf("unexpected result: (-want, +got) %s", diff)
+			if tc.wantField != "" && len(errs) != 0 && errs[0].Field != tc.wantField {
+				t.Errorf("unexpected field of the error, want %s, got %s", tc.wantField, errs[0].Field)
 			}
 		})
 	}
 }
 
-func TestValidateNonnegativeOrZeroField(t *testing.T) {
+func TestDeprecationWarnings(t *testing.T) {
 	tests := []struct {
-		name       string
-		input      int64
-		wantOutput field.ErrorList
+		name         string
+		lws          *wrappers.LeaderWorkerSetWrapper
+		wantWarnings int
 	}{
 		{
-			name:  "input less than 0",
-			input: -1,
-			wantOutput: []*field.Error{
-				{
-					Type:     field.ErrorTypeInvalid,
-					Field:    "test",
-					BadValue: int64(-1),
-					Detail:   "must be grater than or equal to 0",
-				},
-			},
+			name: "restartPolicy RecreateGroupOnPodRestart",
+			lws:  wrappers.BuildLeaderWorkerSet("default").RestartPolicy(v1.RecreateGroupOnPodRestart),
 		},
 		{
-			name:       "input equal to 0",
-			input:      0,
-			wantOutput: []*field.Error{},
+			name:         "deprecated restartPolicy Default",
+			lws:          wrappers.BuildLeaderWorkerSet("default").RestartPolicy(v1.DeprecatedDefaultRestartPolicy),
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := deprecationWarnings(tc.lws.Obj())
+			if diff := cmp.Diff(tc.wantWarnings, len(warnings)); diff != "" {
+				t.Errorf("unexpected warnings %v: (-want, +got) %s", warnings, diff)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	leaderPodSpecWithReadinessProbe := wrappers.MakeLeaderPodSpec()
+	leaderPodSpecWithReadinessProbe.Containers[0].ReadinessProbe = &corev1.Probe{}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{"cloud.google.com/gke-nodepool": "pool"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		lws          *wrappers.LeaderWorkerSetWrapper
+		nodes        []*corev1.Node
+		wantWarnings int
+	}{
+		{
+			name: "default leaderworkerset",
+			lws:  wrappers.BuildLeaderWorkerSet("default"),
+		},
+		{
+			name:         "LeaderReady without leader readiness probe",
+			lws:          wrappers.BuildLeaderWorkerSet("default").StartupPolicy(v1.LeaderReadyStartupPolicy),
+			wantWarnings: 1,
+		},
+		{
+			name: "LeaderReady with leader readiness probe",
+			lws:  wrappers.BuildLeaderWorkerSet("default").StartupPolicy(v1.LeaderReadyStartupPolicy).LeaderTemplateSpec(leaderPodSpecWithReadinessProbe),
 		},
 		{
-			name:       "input greater than 0",
-			input:      1,
-			wantOutput: []*field.Error{},
+			name: "maxSurge with Shared subdomainPolicy",
+			lws:  wrappers.BuildLeaderWorkerSet("default").MaxSurge(1),
+		},
+		{
+			name:         "maxSurge with UniquePerReplica subdomainPolicy",
+			lws:          wrappers.BuildLeaderWorkerSet("default").MaxSurge(1).SubdomainPolicy(v1.SubdomainUniquePerReplica),
+			wantWarnings: 1,
+		},
+		{
+			name:         "exclusive placement without matching nodes",
+			lws:          wrappers.BuildLeaderWorkerSet("default").ExclusivePlacement(),
+			wantWarnings: 1,
+		},
+		{
+			name:  "exclusive placement with matching nodes",
+			lws:   wrappers.BuildLeaderWorkerSet("default").ExclusivePlacement(),
+			nodes: []*corev1.Node{node},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			testPath := field.NewPath("test")
-			output := validateNonnegativeField(tc.input, testPath)
-			if diff := cmp.Diff(tc.wantOutput, output); diff != "" {
-				t.Errorf("unexpected result: (-want, +got) %s", diff)
+			clientBuilder := fake.NewClientBuilder()
+			for _, node := range tc.nodes {
+				clientBuilder.WithObjects(node)
+			}
+			r := &LeaderWorkerSetWebhook{apiReader: clientBuilder.Build()}
+			warnings := r.warnings(context.Background(), tc.lws.Obj())
+			if diff := cmp.Diff(tc.wantWarnings, len(warnings)); diff != "" {
+				t.Errorf("unexpected warnings %v: (-want, +got) %s", warnings, diff)
 			}
 		})
 	}
 }
 
-func TestIsNotMoreThan100Percent(t *testing.T) {
+func TestDefaultWithLeaderWorkerSetDefaults(t *testing.T) {
+	defaults := &configapi.LeaderWorkerSetDefaults{
+		LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+			ExclusiveTopology: "cloud.google.com/gke-nodepool",
+			RestartPolicy:     v1.NoneRestartPolicy,
+			StartupPolicy:     v1.LeaderReadyStartupPolicy,
+			SubdomainPolicy:   v1.SubdomainUniquePerReplica,
+			MaxSurge:          ptr.To(intstr.FromInt32(1)),
+		},
+		NamespaceOverrides: []configapi.LeaderWorkerSetPolicyOverride{{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "research"}},
+			LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+				ExclusiveTopology: "kubernetes.io/hostname",
+				MaxSurge:          ptr.To(intstr.FromString("50%")),
+			},
+		}},
+	}
+	namespaces := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "research", Labels: map[string]string{"team": "research"}}},
+	}
+
 	tests := []struct {
-		name       string
-		input      intstr.IntOrString
-		wantErr    string
-		wantOutput field.ErrorList
+		name      string
+		lws       *wrappers.LeaderWorkerSetWrapper
+		defaults  *configapi.LeaderWorkerSetDefaults
+		operation admissionv1.Operation
+		want      *wrappers.LeaderWorkerSetWrapper
 	}{
 		{
-			name: "invalid input",
-			input: intstr.IntOrString{
-				Type:   0,
-				IntVal: 1,
-			},
-			wantOutput: nil,
+			name:      "no defaults",
+			lws:       wrappers.BuildLeaderWorkerSet("default"),
+			operation: admissionv1.Create,
+			want:      wrappers.BuildLeaderWorkerSet("default"),
+		},
+		{
+			name:      "cluster wide defaults",
+			lws:       wrappers.BuildLeaderWorkerSet("default").SubdomainNil(),
+			defaults:  defaults,
+			operation: admissionv1.Create,
+			want: wrappers.BuildLeaderWorkerSet("default").
+				Annotation(map[string]string{v1.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"}).
+				RestartPolicy(v1.NoneRestartPolicy).
+				StartupPolicy(v1.LeaderReadyStartupPolicy).
+				SubdomainPolicy(v1.SubdomainUniquePerReplica).
+				MaxSurge(1),
+		},
+		{
+			name:      "namespace override",
+			lws:       wrappers.BuildLeaderWorkerSet("research").SubdomainNil(),
+			defaults:  defaults,
+			operation: admissionv1.Create,
+			want: func() *wrappers.LeaderWorkerSetWrapper {
+				lws := wrappers.BuildLeaderWorkerSet("research").
+					Annotation(map[string]string{v1.ExclusiveKeyAnnotationKey: "kubernetes.io/hostname"}).
+					RestartPolicy(v1.NoneRestartPolicy).
+					StartupPolicy(v1.LeaderReadyStartupPolicy).
+					SubdomainPolicy(v1.SubdomainUniquePerReplica)
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge = intstr.FromString("50%")
+				return lws
+			}(),
 		},
 		{
-			name: "valid input - greater than 100",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "101%",
+			name: "values set by the user are kept",
+			lws: wrappers.BuildLeaderWorkerSet("default").
+				Annotation(map[string]string{v1.ExclusiveKeyAnnotationKey: "kubernetes.io/hostname"}).
+				SubdomainPolicy(v1.SubdomainShared).
+				MaxSurge(2),
+			defaults:  defaults,
+			operation: admissionv1.Create,
+			want: wrappers.BuildLeaderWorkerSet("default").
+				Annotation(map[string]string{v1.ExclusiveKeyAnnotationKey: "kubernetes.io/hostname"}).
+				RestartPolicy(v1.NoneRestartPolicy).
+				StartupPolicy(v1.LeaderReadyStartupPolicy).
+				SubdomainPolicy(v1.SubdomainShared).
+				MaxSurge(2),
+		},
+		{
+			name:      "built-in values kept with the skip-policy-defaults annotation",
+			lws:       wrappers.BuildLeaderWorkerSet("default").Annotation(map[string]string{v1.SkipPolicyDefaultsAnnotationKey: "true"}),
+			defaults:  defaults,
+			operation: admissionv1.Create,
+			want:      wrappers.BuildLeaderWorkerSet("default").Annotation(map[string]string{v1.SkipPolicyDefaultsAnnotationKey: "true"}),
+		},
+		{
+			name:      "defaults aren't applied on updates",
+			lws:       wrappers.BuildLeaderWorkerSet("default"),
+			defaults:  defaults,
+			operation: admissionv1.Update,
+			want:      wrappers.BuildLeaderWorkerSet("default"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lwsDefaults := &atomic.Pointer[configapi.LeaderWorkerSetDefaults]{}
+			lwsDefaults.Store(tc.defaults)
+			r := &LeaderWorkerSetWebhook{
+				client:   fake.NewClientBuilder().WithObjects(namespaces...).Build(),
+				defaults: lwsDefaults,
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: tc.operation},
+			})
+			lws := tc.lws.Obj()
+			if err := r.Default(ctx, lws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.Obj(), lws); diff != "" {
+				t.Errorf("unexpected leaderworkerset: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateBounds(t *testing.T) {
+	defaults := &configapi.LeaderWorkerSetDefaults{
+		LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+			MaxSize:     ptr.To[int32](4),
+			MaxReplicas: ptr.To[int32](4),
+		},
+		NamespaceOverrides: []configapi.LeaderWorkerSetPolicyOverride{{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "research"}},
+			LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+				MaxSize: ptr.To[int32](16),
 			},
-			wantOutput: []*field.Error{
-				{
-					Type:  field.ErrorTypeInvalid,
-					Field: "test",
-					BadValue: intstr.IntOrString{
-						Type:   1,
-						StrVal: "101%",
-					},
-					Detail: "must not be greater than 100%",
-				},
+		}},
+	}
+	namespaces := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "research", Labels: map[string]string{"team": "research"}}},
+	}
+
+	tests := []struct {
+		name     string
+		lws      *wrappers.LeaderWorkerSetWrapper
+		oldLws   *wrappers.LeaderWorkerSetWrapper
+		defaults *configapi.LeaderWorkerSetDefaults
+		wantErrs field.ErrorList
+	}{
+		{
+			name: "no bounds",
+			lws:  wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(8),
+		},
+		{
+			name:     "within the bounds",
+			lws:      wrappers.BuildLeaderWorkerSet("default").Size(4).Replica(4),
+			defaults: defaults,
+		},
+		{
+			name:     "above the bounds",
+			lws:      wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(8),
+			defaults: defaults,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "leaderWorkerTemplate", "size"), int32(8), ""),
+				field.Invalid(field.NewPath("spec", "replicas"), int32(8), ""),
 			},
 		},
 		{
-			name: "valid input - less than 100",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "99%",
+			name:     "namespace override",
+			lws:      wrappers.BuildLeaderWorkerSet("research").Size(8).Replica(8),
+			defaults: defaults,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "replicas"), int32(8), ""),
 			},
-			wantOutput: nil,
 		},
 		{
-			name: "valid input - equal to 100",
-			input: intstr.IntOrString{
-				Type:   1,
-				StrVal: "100%",
+			name:     "update keeping the values above the bounds",
+			lws:      wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(6),
+			oldLws:   wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(8),
+			defaults: defaults,
+		},
+		{
+			name:     "update increasing the values above the bounds",
+			lws:      wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(8),
+			oldLws:   wrappers.BuildLeaderWorkerSet("default").Size(8).Replica(6),
+			defaults: defaults,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "replicas"), int32(8), ""),
 			},
-			wantOutput: nil,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			testPath := field.NewPath("test")
-			output := isNotMoreThan100Percent(tc.input, testPath)
-			if diff := cmp.Diff(tc.wantOutput, output); diff != "" {
-				t.Errorf("unexpected result: (-want, +got) %s", diff)
+			lwsDefaults := &atomic.Pointer[configapi.LeaderWorkerSetDefaults]{}
+			lwsDefaults.Store(tc.defaults)
+			r := &LeaderWorkerSetWebhook{
+				client:   fake.NewClientBuilder().WithObjects(namespaces...).Build(),
+				defaults: lwsDefaults,
+			}
+			var oldLws *v1.LeaderWorkerSet
+			if tc.oldLws != nil {
+				oldLws = tc.oldLws.Obj()
+			}
+			errs := r.validateBounds(context.Background(), tc.lws.Obj(), oldLws)
+			if diff := cmp.Diff(tc.wantErrs, errs, cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("unexpected errors: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestScaleBoundsHandler(t *testing.T) {
+	defaults := &configapi.LeaderWorkerSetDefaults{
+		LeaderWorkerSetPolicy: configapi.LeaderWorkerSetPolicy{
+			MaxReplicas: ptr.To[int32](4),
+		},
+	}
+	scale := func(replicas int32) runtime.RawExtension {
+		raw, err := json.Marshal(&autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default"},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runtime.RawExtension{Raw: raw}
+	}
+
+	tests := []struct {
+		name        string
+		replicas    int32
+		oldReplicas int32
+		defaults    *configapi.LeaderWorkerSetDefaults
+		wantAllowed bool
+	}{
+		{
+			name:        "no bounds",
+			replicas:    8,
+			oldReplicas: 2,
+			wantAllowed: true,
+		},
+		{
+			name:        "scaled within the bounds",
+			replicas:    4,
+			oldReplicas: 2,
+			defaults:    defaults,
+			wantAllowed: true,
+		},
+		{
+			name:        "scaled above the bounds",
+			replicas:    8,
+			oldReplicas: 2,
+			defaults:    defaults,
+		},
+		{
+			name:        "scaled down while above the bounds",
+			replicas:    6,
+			oldReplicas: 8,
+			defaults:    defaults,
+			wantAllowed: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lwsDefaults := &atomic.Pointer[configapi.LeaderWorkerSetDefaults]{}
+			lwsDefaults.Store(tc.defaults)
+			h := &scaleBoundsHandler{webhook: &LeaderWorkerSetWebhook{defaults: lwsDefaults}}
+			resp := h.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Namespace: "default",
+					Object:    scale(tc.replicas),
+					OldObject: scale(tc.oldReplicas),
+				},
+			})
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("unexpected admission, want allowed %t, got %v", tc.wantAllowed, resp.Result)
 			}
 		})
 	}
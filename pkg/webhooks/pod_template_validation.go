@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// statefulSetTemplatePath is the path of the pod template in the StatefulSets, the errors of the
+// apiserver under it are reported under the path of the template in the leaderworkerset.
+const statefulSetTemplatePath = "spec.template"
+
+// validatePodTemplates validates the leader and worker templates with the validation of the apiserver,
+// by a dry-run creation of a StatefulSet for each of them, so that the templates which would fail the
+// creation of the StatefulSets are rejected when the leaderworkerset is admitted.
+// The validation is skipped if the webhook has no client, and when the dry-run fails for another reason
+// than an invalid StatefulSet, e.g. the apiserver being unavailable, since the template isn't at fault.
+func (r *LeaderWorkerSetWebhook) validatePodTemplates(ctx context.Context, lws *v1.LeaderWorkerSet) field.ErrorList {
+	if r.client == nil {
+		return nil
+	}
+	templatePath := field.NewPath("spec", "leaderWorkerTemplate")
+	var allErrs field.ErrorList
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		allErrs = append(allErrs, r.validatePodTemplate(ctx, lws, lws.Spec.LeaderWorkerTemplate.LeaderTemplate, templatePath.Child("leaderTemplate"))...)
+	}
+	allErrs = append(allErrs, r.validatePodTemplate(ctx, lws, &lws.Spec.LeaderWorkerTemplate.WorkerTemplate, templatePath.Child("workerTemplate"))...)
+	return allErrs
+}
+
+func (r *LeaderWorkerSetWebhook) validatePodTemplate(ctx context.Context, lws *v1.LeaderWorkerSet, template *corev1.PodTemplateSpec, fldPath *field.Path) field.ErrorList {
+	err := r.client.Create(ctx, statefulSetForTemplate(lws, template), client.DryRunAll)
+	if err == nil {
+		return nil
+	}
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsInvalid(err) || !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		logf.FromContext(ctx).Error(err, "Validating the pod template with a dry-run creation of a StatefulSet", "template", fldPath.String())
+		return nil
+	}
+	var allErrs field.ErrorList
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		allErrs = append(allErrs, templateFieldError(cause, fldPath))
+	}
+	return allErrs
+}
+
+// statefulSetForTemplate returns a StatefulSet with the pod template, the name of the StatefulSet is generated
+// so that the dry-run doesn't conflict with the StatefulSets of the leaderworkerset.
+func statefulSetForTemplate(lws *v1.LeaderWorkerSet, template *corev1.PodTemplateSpec) *appsv1.StatefulSet {
+	selector := map[string]string{v1.SetNameLabelKey: lws.Name}
+	podTemplate := template.DeepCopy()
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = map[string]string{}
+	}
+	for key, value := range selector {
+		podTemplate.Labels[key] = value
+	}
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: lws.Name + "-",
+			Namespace:    lws.Namespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    ptr.To[int32](1),
+			Selector:    &metav1.LabelSelector{MatchLabels: selector},
+			ServiceName: lws.Name,
+			Template:    *podTemplate,
+		},
+	}
+}
+
+// templateFieldError converts a cause of the invalid StatefulSet to an error of the template at fldPath.
+// The causes outside of the pod template are reported on the template itself.
+func templateFieldError(cause metav1.StatusCause, fldPath *field.Path) *field.Error {
+	errorType := field.ErrorType(cause.Type)
+	fieldPath := fldPath.String()
+	if suffix, found := strings.CutPrefix(cause.Field, statefulSetTemplatePath); found && (suffix == "" || strings.HasPrefix(suffix, ".") || strings.HasPrefix(suffix, "[")) {
+		fieldPath += suffix
+	}
+	// The message of the cause already holds the value, it is omitted from the error.
+	detail := strings.TrimPrefix(strings.TrimPrefix(cause.Message, errorType.String()), ": ")
+	return &field.Error{Type: errorType, Field: fieldPath, BadValue: field.OmitValueType{}, Detail: detail}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/wrappers"
+)
+
+func TestValidatePodTemplates(t *testing.T) {
+	invalid := func(errs ...*field.Error) error {
+		return apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, "test-sample-abcde", errs)
+	}
+
+	// The templates are told apart by their role label in the dry-run StatefulSets.
+	lws := wrappers.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Labels = map[string]string{"role": "leader"}
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Labels = map[string]string{"role": "worker"}
+
+	tests := []struct {
+		name      string
+		createErr func(sts *appsv1.StatefulSet) error
+		wantErrs  []string
+	}{
+		{
+			name: "valid templates",
+		},
+		{
+			name: "invalid worker template",
+			createErr: func(sts *appsv1.StatefulSet) error {
+				if sts.Spec.Template.Labels["role"] != "worker" {
+					return nil
+				}
+				return invalid(
+					field.Invalid(field.NewPath("spec", "template", "spec", "containers").Index(0).Child("name"), "Worker", "a lowercase RFC 1123 label"),
+					field.Required(field.NewPath("spec", "template", "spec", "containers").Index(1).Child("image"), ""),
+				)
+			},
+			wantErrs: []string{
+				`spec.leaderWorkerTemplate.workerTemplate.spec.containers[0].name: Invalid value: "Worker": a lowercase RFC 1123 label`,
+				"spec.leaderWorkerTemplate.workerTemplate.spec.containers[1].image: Required value",
+			},
+		},
+		{
+			name: "invalid leader template",
+			createErr: func(sts *appsv1.StatefulSet) error {
+				if sts.Spec.Template.Labels["role"] != "leader" {
+					return nil
+				}
+				return invalid(field.NotSupported(field.NewPath("spec", "template", "spec", "restartPolicy"), "Never", []string{"Always"}))
+			},
+			wantErrs: []string{
+				`spec.leaderWorkerTemplate.leaderTemplate.spec.restartPolicy: Unsupported value: "Never": supported values: "Always"`,
+			},
+		},
+		{
+			name: "invalid StatefulSet outside of the template",
+			createErr: func(sts *appsv1.StatefulSet) error {
+				if sts.Spec.Template.Labels["role"] != "worker" {
+					return nil
+				}
+				return invalid(field.Invalid(field.NewPath("spec", "selector"), "", "empty selector"))
+			},
+			wantErrs: []string{
+				`spec.leaderWorkerTemplate.workerTemplate: Invalid value: "": empty selector`,
+			},
+		},
+		{
+			name: "dry-run failing for another reason",
+			createErr: func(*appsv1.StatefulSet) error {
+				return errors.New("connection refused")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					createOpts := &client.CreateOptions{}
+					createOpts.ApplyOptions(opts)
+					if diff := cmp.Diff([]string{"All"}, createOpts.DryRun); diff != "" {
+						t.Errorf("unexpected dry-run option (-want, +got): %s", diff)
+					}
+					sts := obj.(*appsv1.StatefulSet)
+					if diff := cmp.Diff(map[string]string{v1.SetNameLabelKey: "test-sample"}, sts.Spec.Selector.MatchLabels); diff != "" {
+						t.Errorf("unexpected selector (-want, +got): %s", diff)
+					}
+					if sts.Spec.Template.Labels[v1.SetNameLabelKey] != "test-sample" {
+						t.Errorf("expected the template to match the selector, got labels %v", sts.Spec.Template.Labels)
+					}
+					if tc.createErr != nil {
+						return tc.createErr(sts)
+					}
+					return nil
+				},
+			}).Build()
+			r := &LeaderWorkerSetWebhook{client: k8sClient}
+			var gotErrs []string
+			for _, err := range r.validatePodTemplates(context.Background(), lws) {
+				gotErrs = append(gotErrs, err.Error())
+			}
+			if diff := cmp.Diff(tc.wantErrs, gotErrs); diff != "" {
+				t.Errorf("unexpected errors (-want, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -20,15 +20,20 @@ import (
 	"fmt"
 	"strconv"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
-	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/pkg/tracing"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
@@ -36,6 +41,30 @@ import (
 
 type PodWebhook struct{}
 
+// lwsManagedLabelKeys are the pod labels set by the leaderworkerset controllers and the pod webhook,
+// they identify the group the pod belongs to and must not be changed once the pod is created.
+var lwsManagedLabelKeys = []string{
+	leaderworkerset.SetNameLabelKey,
+	leaderworkerset.GroupIndexLabelKey,
+	leaderworkerset.WorkerIndexLabelKey,
+	leaderworkerset.GroupUniqueHashLabelKey,
+	leaderworkerset.RevisionKey,
+	leaderworkerset.SubGroupIndexLabelKey,
+	leaderworkerset.SubGroupUniqueHashLabelKey,
+}
+
+// lwsManagedAnnotationKeys are the pod annotations set by the leaderworkerset controllers,
+// they must not be changed once the pod is created.
+var lwsManagedAnnotationKeys = []string{
+	leaderworkerset.SizeAnnotationKey,
+	leaderworkerset.LeaderPodNameAnnotationKey,
+	leaderworkerset.ExclusiveKeyAnnotationKey,
+	leaderworkerset.SubGroupSizeAnnotationKey,
+	leaderworkerset.SubGroupExclusiveKeyAnnotationKey,
+	leaderworkerset.SubdomainPolicyAnnotationKey,
+	acceleratorutils.LeaderRequestsTPUsAnnotationKey,
+}
+
 func SetupPodWebhook(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&corev1.Pod{}).
@@ -46,7 +75,7 @@ func SetupPodWebhook(mgr ctrl.Manager) error {
 
 //+kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=vpod.kb.io,sideEffects=None,admissionReviewVersions=v1
 
-// validate admits a pod if a specific annotation exists.
+// validate admits a leaderworkerset pod only if it is controlled by a StatefulSet of the leaderworkerset.
 func (p *PodWebhook) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	log := logf.FromContext(ctx)
 	pod, ok := obj.(*corev1.Pod)
@@ -57,12 +86,12 @@ func (p *PodWebhook) validate(ctx context.Context, obj runtime.Object) (admissio
 	log.V(2).Info("Validating Pod")
 
 	// if pod is not part of leaderworkerset, skip
-	_, found := pod.Labels[leaderworkerset.SetNameLabelKey]
+	lwsName, found := pod.Labels[leaderworkerset.SetNameLabelKey]
 	if !found {
 		return nil, nil
 	}
 
-	return nil, nil
+	return nil, validatePodOwner(pod, lwsName).ToAggregate()
 }
 
 func (p *PodWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -70,7 +99,28 @@ func (p *PodWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (ad
 }
 
 func (p *PodWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+	log := logf.FromContext(ctx)
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod but got a %T", oldObj)
+	}
+	newPod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod but got a %T", newObj)
+	}
+
+	log.V(2).Info("Validating Pod update")
+
+	allErrs := field.ErrorList{}
+	labelsPath := field.NewPath("metadata", "labels")
+	for _, key := range lwsManagedLabelKeys {
+		allErrs = append(allErrs, validateImmutableKey(oldPod.Labels, newPod.Labels, key, labelsPath.Key(key))...)
+	}
+	annotationsPath := field.NewPath("metadata", "annotations")
+	for _, key := range lwsManagedAnnotationKeys {
+		allErrs = append(allErrs, validateImmutableKey(oldPod.Annotations, newPod.Annotations, key, annotationsPath.Key(key))...)
+	}
+	return nil, allErrs.ToAggregate()
 }
 
 func (p *PodWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -100,6 +150,17 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	if err != nil {
 		return err
 	}
+	if podutils.LeaderPod(*pod) && pod.Annotations[leaderworkerset.TraceContextAnnotationKey] == "" {
+		// The group starts with the creation of its leader pod.
+		if traceContext := tracing.NewGroupTraceContext(ctx); traceContext != "" {
+			pod.Annotations[leaderworkerset.TraceContextAnnotationKey] = traceContext
+		}
+	}
+	_, span := tracing.Tracer().Start(tracing.ContextWithGroup(ctx, pod), "PodWebhook.Default", trace.WithAttributes(
+		attribute.String("namespace", pod.Namespace),
+		attribute.String("pod", pod.Name),
+	))
+	defer span.End()
 	// adding labels for pods
 	if podutils.LeaderPod(*pod) {
 		// add group index label to group pods
@@ -117,23 +178,23 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 		// add group unique key label for exclusive placement, and use it to check whether the node affinity has been applied
 		var groupUniqueKey string
 		if _, foundGroupKey := pod.Labels[leaderworkerset.GroupUniqueHashLabelKey]; !foundGroupKey {
-			groupUniqueKey = genGroupUniqueKey(pod.Namespace, pod.Name)
+			groupUniqueKey = podutils.GenGroupUniqueKey(pod.Namespace, pod.Name)
 			pod.Labels[leaderworkerset.GroupUniqueHashLabelKey] = groupUniqueKey
 		} else {
 			groupUniqueKey = pod.Labels[leaderworkerset.GroupUniqueHashLabelKey]
 		}
 		if epKey, foundEpKey := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; foundEpKey {
-			SetExclusiveAffinities(pod, groupUniqueKey, epKey, leaderworkerset.GroupUniqueHashLabelKey)
+			podutils.SetExclusiveAffinities(pod, groupUniqueKey, epKey, leaderworkerset.GroupUniqueHashLabelKey)
 		}
 		_, foundSubGroupSize := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
 		if foundSubGroupSize && pod.Labels[leaderworkerset.SubGroupIndexLabelKey] == "" {
 			// The leader pod always lands on SubGroup 0.
 			pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = "0"
-			subGroupUniqueKey := genGroupUniqueKey(pod.Name, "0")
+			subGroupUniqueKey := podutils.GenGroupUniqueKey(pod.Name, "0")
 			pod.Labels[leaderworkerset.SubGroupUniqueHashLabelKey] = subGroupUniqueKey
 
 			if subEpKey, foundSubEpKey := pod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
-				SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
+				podutils.SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
 			}
 		}
 	} else {
@@ -149,12 +210,12 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 				return err
 			}
 			leaderName := pod.Annotations[leaderworkerset.LeaderPodNameAnnotationKey]
-			subGroupIndexKey := getSubGroupIndex(podCount, subGroupSizeInt, workerIndex)
+			subGroupIndexKey := podutils.GetSubGroupIndex(podCount, subGroupSizeInt, workerIndex)
 			pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = subGroupIndexKey
-			subGroupUniqueKey := genGroupUniqueKey(leaderName, subGroupIndexKey)
+			subGroupUniqueKey := podutils.GenGroupUniqueKey(leaderName, subGroupIndexKey)
 			pod.Labels[leaderworkerset.SubGroupUniqueHashLabelKey] = subGroupUniqueKey
 			if subEpKey, foundSubEpKey := pod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; foundSubEpKey {
-				SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
+				podutils.SetExclusiveAffinities(pod, subGroupUniqueKey, subEpKey, leaderworkerset.SubGroupUniqueHashLabelKey)
 			}
 		}
 	}
@@ -173,79 +234,35 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	return nil
 }
 
-func genGroupUniqueKey(ns string, podName string) string {
-	return utils.Sha1Hash(fmt.Sprintf("%s/%s", ns, podName))
-}
-
-// SetExclusiveAffinities set the pod affinity/anti-affinity
-func SetExclusiveAffinities(pod *corev1.Pod, groupUniqueKey string, topologyKey string, podAffinityKey string) {
-	if exclusiveAffinityApplied(*pod, topologyKey) {
-		return
-	}
-	if pod.Spec.Affinity == nil {
-		pod.Spec.Affinity = &corev1.Affinity{}
-	}
-	if pod.Spec.Affinity.PodAffinity == nil {
-		pod.Spec.Affinity.PodAffinity = &corev1.PodAffinity{}
-	}
-	if pod.Spec.Affinity.PodAntiAffinity == nil {
-		pod.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
-	}
-
-	// Pod affinity ensures the pods of this set land on the same topology domain.
-	pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-		corev1.PodAffinityTerm{
-			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
-				{
-					Key:      podAffinityKey,
-					Operator: metav1.LabelSelectorOpIn,
-					Values:   []string{groupUniqueKey},
-				},
-			}},
-			TopologyKey: topologyKey,
-		})
-	// Pod anti-affinity ensures exclusively this set lands on the topology, preventing multiple sets per topology domain.
-	pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
-		corev1.PodAffinityTerm{
-			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
-				{
-					Key:      podAffinityKey,
-					Operator: metav1.LabelSelectorOpExists,
-				},
-				{
-					Key:      podAffinityKey,
-					Operator: metav1.LabelSelectorOpNotIn,
-					Values:   []string{groupUniqueKey},
-				},
-			}},
-			TopologyKey: topologyKey,
-		})
-}
-
-// exclusiveAffinityApplied return true if the exclusive placement terms have been applied
-func exclusiveAffinityApplied(pod corev1.Pod, topologyKey string) bool {
-	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAffinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
-		return false
+// validatePodOwner validates that the pod is controlled by the leader StatefulSet of the leaderworkerset
+// if it is a leader pod, or by one of the worker StatefulSets of the leaderworkerset if it is a worker pod.
+func validatePodOwner(pod *corev1.Pod, lwsName string) field.ErrorList {
+	ownerPath := field.NewPath("metadata", "ownerReferences")
+	owner := metav1.GetControllerOfNoCopy(pod)
+	if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() || owner.Kind != "StatefulSet" {
+		return field.ErrorList{field.Forbidden(ownerPath, fmt.Sprintf("pods with label %s must be controlled by a StatefulSet", leaderworkerset.SetNameLabelKey))}
 	}
-	hasAffinity := false
-	hasAntiAffinity := false
-	for _, podAffinityTerm := range pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
-		if podAffinityTerm.TopologyKey == topologyKey {
-			hasAffinity = true
-		}
+	// Pods of a StatefulSet are named <statefulset name>-<ordinal>.
+	if parentName, _ := statefulsetutils.GetParentNameAndOrdinal(pod.Name); parentName != owner.Name {
+		return field.ErrorList{field.Forbidden(ownerPath, fmt.Sprintf("pod name must be prefixed with the name of its controlling StatefulSet %s", owner.Name))}
 	}
-	for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
-		if term.TopologyKey == topologyKey {
-			hasAntiAffinity = true
-		}
+	stsName := owner.Name
+	if !podutils.LeaderPod(*pod) {
+		// Worker StatefulSets are named after their leader pods, i.e. <lws name>-<group index>.
+		stsName, _ = statefulsetutils.GetParentNameAndOrdinal(owner.Name)
 	}
-	return hasAffinity && hasAntiAffinity
+	if stsName != lwsName {
+		return field.ErrorList{field.Forbidden(ownerPath, fmt.Sprintf("pod must be controlled by a StatefulSet of leaderworkerset %s", lwsName))}
+	}
+	return nil
 }
 
-func getSubGroupIndex(podCount int, subGroupSize int, workerIndex int) string {
-	if (podCount-1)%subGroupSize == 0 {
-		// Leader is considered as extra pod, it is part of the first group
-		return fmt.Sprint((workerIndex - 1) / subGroupSize)
+// validateImmutableKey validates that the given key is neither added, removed nor changed.
+func validateImmutableKey(oldValues, newValues map[string]string, key string, fldPath *field.Path) field.ErrorList {
+	oldValue, oldFound := oldValues[key]
+	newValue, newFound := newValues[key]
+	if oldFound != newFound || oldValue != newValue {
+		return field.ErrorList{field.Invalid(fldPath, newValue, apivalidation.FieldImmutableErrorMsg)}
 	}
-	return fmt.Sprint(workerIndex / subGroupSize)
+	return nil
 }
@@ -17,253 +17,142 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
-func TestGenGroupUniqueKey(t *testing.T) {
+func TestValidatePodOwner(t *testing.T) {
+	stsOwner := func(name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: name, UID: "uid", Controller: ptr.To(true)}}
+	}
 	tests := []struct {
-		name        string
-		podName     string
-		namespace   string
-		expectedKey string
+		name    string
+		pod     *corev1.Pod
+		wantErr bool
 	}{
 		{
-			name:        "same namespace, pod name",
-			podName:     "test-sample",
-			namespace:   "default",
-			expectedKey: "95e88034e460983f51a9952fe128729fbc0663b5",
+			name: "leader pod controlled by the leader statefulset",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-sample-1",
+				Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+				OwnerReferences: stsOwner("test-sample"),
+			}},
 		},
 		{
-			name:        "same namespace, different pod name",
-			podName:     "podName",
-			namespace:   "default",
-			expectedKey: "390b34ab671d29e9997d7d4252b8bbf8da02f5b7",
+			name: "worker pod controlled by the worker statefulset",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-sample-1-2",
+				Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "2"},
+				OwnerReferences: stsOwner("test-sample-1"),
+			}},
 		},
 		{
-			name:        "different namespace, same pod name",
-			podName:     "test-sample",
-			namespace:   "leaderworkerset",
-			expectedKey: "39f5d7e9122b9d94d3932e3720b43fd3b56347e8",
+			name: "pod without owner",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-sample-1",
+				Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+			}},
+			wantErr: true,
 		},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			key := genGroupUniqueKey(tc.namespace, tc.podName)
-			if diff := cmp.Diff(tc.expectedKey, key); diff != "" {
-				t.Errorf("unexpected key %s", diff)
-			}
-		})
-	}
-}
-
-func TestSetExclusiveAffinities(t *testing.T) {
-	tests := []struct {
-		name           string
-		pod            *corev1.Pod
-		groupUniqueKey string
-		topologyKey    string
-		podAffinityKey string
-		expectedPod    *corev1.Pod
-	}{
 		{
-			name: "Pod with only Exclusive Topology Annotation",
-			pod: &corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
+			name: "pod controlled by a deployment",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-sample-1",
+				Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "test-sample", UID: "uid", Controller: ptr.To(true)},
 				},
-			},
-			groupUniqueKey: "test-key",
-			topologyKey:    "topologyKey",
-			podAffinityKey: leaderworkerset.GroupUniqueHashLabelKey,
-			expectedPod: &corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
-								TopologyKey: "topologyKey",
-								LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      "leaderworkerset.sigs.k8s.io/group-key",
-										Operator: "In",
-										Values:   []string{"test-key"},
-									},
-								}},
-							}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
-								TopologyKey: "topologyKey",
-								LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      "leaderworkerset.sigs.k8s.io/group-key",
-										Operator: "Exists",
-									},
-									{
-										Key:      "leaderworkerset.sigs.k8s.io/group-key",
-										Operator: "NotIn",
-										Values:   []string{"test-key"},
-									},
-								}},
-							}},
-						},
-					},
-				},
-			},
+			}},
+			wantErr: true,
 		},
 		{
-			name: "Pod with Exclusive Annotation, Affinity, and AntiAffinity",
-			pod: &corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
-			groupUniqueKey: "test-key",
-			topologyKey:    "topologyKey",
-			podAffinityKey: leaderworkerset.GroupUniqueHashLabelKey,
-			expectedPod: &corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey"},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
+			name: "leader pod controlled by a statefulset of another leaderworkerset",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "other-1",
+				Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+				OwnerReferences: stsOwner("other"),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "worker pod name doesn't match its statefulset",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-sample-2-1",
+				Labels:          map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "1"},
+				OwnerReferences: stsOwner("test-sample-1"),
+			}},
+			wantErr: true,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			SetExclusiveAffinities(tc.pod, tc.groupUniqueKey, tc.topologyKey, tc.podAffinityKey)
-			if diff := cmp.Diff(tc.pod, tc.expectedPod); diff != "" {
-				t.Errorf("unexpected set exclusive affinities operation: %s", diff)
+			errs := validatePodOwner(tc.pod, tc.pod.Labels[leaderworkerset.SetNameLabelKey])
+			if (len(errs) != 0) != tc.wantErr {
+				t.Errorf("unexpected errors: %v", errs)
 			}
 		})
 	}
 }
 
-func TestExclusiveAffinityApplied(t *testing.T) {
+func TestValidateUpdate(t *testing.T) {
+	oldPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "test-sample-1",
+		Labels: map[string]string{
+			leaderworkerset.SetNameLabelKey:         "test-sample",
+			leaderworkerset.GroupIndexLabelKey:      "1",
+			leaderworkerset.WorkerIndexLabelKey:     "0",
+			leaderworkerset.GroupUniqueHashLabelKey: "hash",
+			leaderworkerset.RevisionKey:             "revision",
+		},
+		Annotations: map[string]string{
+			leaderworkerset.SizeAnnotationKey: "2",
+		},
+	}}
 	tests := []struct {
-		name                              string
-		pod                               corev1.Pod
-		expectedAppliedExclusivePlacement bool
-		topologyKey                       string
+		name    string
+		update  func(*corev1.Pod)
+		wantErr bool
 	}{
 		{
-			name: "Has annotiation, Pod Affinity and Pod AntiAffinity",
-			pod: corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
-					},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
-			expectedAppliedExclusivePlacement: true,
-			topologyKey:                       "topologyKey",
+			name:   "update unmanaged label",
+			update: func(pod *corev1.Pod) { pod.Labels["foo"] = "bar" },
 		},
 		{
-			name: "Has annotiation, Pod Affinity, doesn't have Pod AntiAffinity",
-			pod: corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
-					},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
-			expectedAppliedExclusivePlacement: false,
-			topologyKey:                       "topologyKey",
+			name:    "update group index label",
+			update:  func(pod *corev1.Pod) { pod.Labels[leaderworkerset.GroupIndexLabelKey] = "2" },
+			wantErr: true,
 		},
 		{
-			name: "Has annotiation, Pod AntiAffinity, doesn't have Pod Affinity",
-			pod: corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
-					},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
-			expectedAppliedExclusivePlacement: false,
-			topologyKey:                       "topologyKey",
+			name:    "remove revision label",
+			update:  func(pod *corev1.Pod) { delete(pod.Labels, leaderworkerset.RevisionKey) },
+			wantErr: true,
 		},
 		{
-			name: "Has annotiation, Pod Affinity and Pod AntiAffinity, Topology Key doesn't match",
-			pod: corev1.Pod{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						"leaderworkerset.sigs.k8s.io/exclusive-topology": "topologyKey",
-					},
-				},
-				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey1"}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
-						},
-					},
-				},
-			},
-			expectedAppliedExclusivePlacement: false,
-			topologyKey:                       "topologyKey",
+			name:    "add subgroup index label",
+			update:  func(pod *corev1.Pod) { pod.Labels[leaderworkerset.SubGroupIndexLabelKey] = "0" },
+			wantErr: true,
+		},
+		{
+			name:    "update size annotation",
+			update:  func(pod *corev1.Pod) { pod.Annotations[leaderworkerset.SizeAnnotationKey] = "3" },
+			wantErr: true,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			appliedExclusivePlacement := exclusiveAffinityApplied(tc.pod, tc.topologyKey)
-			if appliedExclusivePlacement != tc.expectedAppliedExclusivePlacement {
-				t.Errorf("Expected value %t, got %t", tc.expectedAppliedExclusivePlacement, appliedExclusivePlacement)
+			newPod := oldPod.DeepCopy()
+			tc.update(newPod)
+			_, err := (&PodWebhook{}).ValidateUpdate(context.Background(), oldPod, newPod)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("unexpected error: %v", err)
 			}
 		})
 	}
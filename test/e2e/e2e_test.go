@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	v2 "sigs.k8s.io/lws/api/leaderworkerset/v2"
 	"sigs.k8s.io/lws/test/testutils"
 	testing "sigs.k8s.io/lws/test/testutils"
 	"sigs.k8s.io/lws/test/wrappers"
@@ -310,6 +311,24 @@ var _ = ginkgo.Describe("leaderWorkerSet e2e tests", func() {
 		}, timeout, interval).Should(gomega.Equal(0))
 	})
 
+	ginkgo.It("Can read and write lws through the v2 API", func() {
+		lws = wrappers.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).Obj()
+		lws.Annotations = map[string]string{v1.ExclusiveKeyAnnotationKey: "kubernetes.io/hostname"}
+		testing.MustCreateLws(ctx, k8sClient, lws)
+
+		lwsV2 := &v2.LeaderWorkerSet{}
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: lws.Name}, lwsV2)).To(gomega.Succeed())
+		gomega.Expect(lwsV2.Spec.LeaderWorkerTemplate.ExclusiveTopologyKey).To(gomega.HaveValue(gomega.Equal("kubernetes.io/hostname")))
+		gomega.Expect(lwsV2.Annotations).NotTo(gomega.HaveKey(v1.ExclusiveKeyAnnotationKey))
+
+		lwsV2.Spec.LeaderWorkerTemplate.ExclusiveTopologyKey = nil
+		gomega.Expect(k8sClient.Update(ctx, lwsV2)).To(gomega.Succeed())
+		gomega.Eventually(func(g gomega.Gomega) {
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: lws.Name}, lws)).To(gomega.Succeed())
+			g.Expect(lws.Annotations).NotTo(gomega.HaveKey(v1.ExclusiveKeyAnnotationKey))
+		}, timeout, interval).Should(gomega.Succeed())
+	})
+
 	// metricsRoleBindingName := "lws-metrics-reader-rolebinding"
 	serviceAccountName := "lws-controller-manager"
 	metricsServiceName := "lws-controller-manager-metrics-service"
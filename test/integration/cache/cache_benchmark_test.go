@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/config"
+)
+
+const (
+	// The cluster runs the groups of 10 leaderworkersets among many other pods and statefulsets.
+	leaderWorkerSetPods         = 200
+	otherPods                   = 2000
+	leaderWorkerSetStatefulSets = 20
+	otherStatefulSets           = 200
+)
+
+// BenchmarkCacheMemory reports the heap used by the cache of the pods and statefulsets of the controller,
+// with and without the cache filters. Run it with:
+//
+//	KUBEBUILDER_ASSETS=... go test ./test/integration/cache -run '^$' -bench . -benchtime 3x
+func BenchmarkCacheMemory(b *testing.B) {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: filepath.Join("..", "..", "..", "bin", "k8s",
+			fmt.Sprintf("1.28.3-%s-%s", goruntime.GOOS, goruntime.GOARCH)),
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			b.Error(err)
+		}
+	}()
+	if err := leaderworkerset.AddToScheme(scheme.Scheme); err != nil {
+		b.Fatal(err)
+	}
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := createObjects(ctx, k8sClient); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("unfiltered", func(b *testing.B) {
+		benchmarkCacheMemory(ctx, b, cfg, cache.Options{Scheme: scheme.Scheme}, otherPods+leaderWorkerSetPods)
+	})
+	b.Run("filtered", func(b *testing.B) {
+		options := cache.Options{Scheme: scheme.Scheme}
+		config.AddCacheFiltersTo(&options)
+		benchmarkCacheMemory(ctx, b, cfg, options, leaderWorkerSetPods)
+	})
+}
+
+func benchmarkCacheMemory(ctx context.Context, b *testing.B, cfg *rest.Config, options cache.Options, wantPods int) {
+	var heapUsed uint64
+	for range b.N {
+		before := heapAlloc()
+		c, err := cache.New(cfg, options)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cacheCtx, cancel := context.WithCancel(ctx)
+		for _, object := range []client.Object{&corev1.Pod{}, &appsv1.StatefulSet{}} {
+			if _, err := c.GetInformer(cacheCtx, object); err != nil {
+				b.Fatal(err)
+			}
+		}
+		go func() {
+			if err := c.Start(cacheCtx); err != nil {
+				b.Error(err)
+			}
+		}()
+		if !c.WaitForCacheSync(cacheCtx) {
+			b.Fatal("cache not synced")
+		}
+		var pods corev1.PodList
+		if err := c.List(cacheCtx, &pods); err != nil {
+			b.Fatal(err)
+		}
+		if len(pods.Items) != wantPods {
+			b.Fatalf("unexpected number of cached pods, want %d, got %d", wantPods, len(pods.Items))
+		}
+		pods = corev1.PodList{}
+		after := heapAlloc()
+		if after > before {
+			heapUsed += after - before
+		}
+		goruntime.KeepAlive(c)
+		cancel()
+	}
+	b.ReportMetric(float64(heapUsed)/float64(b.N)/(1<<20), "MiB/cache")
+}
+
+func heapAlloc() uint64 {
+	goruntime.GC()
+	var stats goruntime.MemStats
+	goruntime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// createObjects creates the pods and statefulsets of the leaderworkersets, labeled with their name,
+// and the pods and statefulsets of other workloads.
+func createObjects(ctx context.Context, k8sClient client.Client) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "cache-benchmark-"}}
+	if err := k8sClient.Create(ctx, namespace); err != nil {
+		return err
+	}
+	for i := range leaderWorkerSetPods + otherPods {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: namespace.Name},
+			Spec:       podSpec(),
+		}
+		if i < leaderWorkerSetPods {
+			pod.Labels = map[string]string{leaderworkerset.SetNameLabelKey: fmt.Sprintf("lws-%d", i%10)}
+		}
+		if err := k8sClient.Create(ctx, pod); err != nil {
+			return err
+		}
+	}
+	for i := range leaderWorkerSetStatefulSets + otherStatefulSets {
+		labels := map[string]string{"app": fmt.Sprintf("sts-%d", i)}
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("sts-%d", i), Namespace: namespace.Name},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To[int32](0),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       podSpec(),
+				},
+			},
+		}
+		if i < leaderWorkerSetStatefulSets {
+			statefulSet.Labels = map[string]string{leaderworkerset.SetNameLabelKey: fmt.Sprintf("lws-%d", i%10)}
+		}
+		if err := k8sClient.Create(ctx, statefulSet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func podSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:    "worker",
+			Image:   "registry.k8s.io/pause:3.9",
+			Command: []string{"/pause"},
+			Env: []corev1.EnvVar{
+				{Name: "MODEL", Value: "meta-llama/Llama-3.1-405B-Instruct"},
+				{Name: "TENSOR_PARALLEL_SIZE", Value: "8"},
+				{Name: "PIPELINE_PARALLEL_SIZE", Value: "2"},
+			},
+		}},
+	}
+}
@@ -24,13 +24,16 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/component-base/featuregate"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/pkg/controllers"
+	"sigs.k8s.io/lws/pkg/features"
 	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	testing "sigs.k8s.io/lws/test/testutils"
 	"sigs.k8s.io/lws/test/wrappers"
@@ -47,10 +50,14 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 	type testCase struct {
 		makeLeaderWorkerSet func(nsName string) *wrappers.LeaderWorkerSetWrapper
 		updates             []*update
+		featureGates        map[featuregate.Feature]bool
 	}
 	ginkgo.DescribeTable("leaderWorkerSet creating or updating",
 		func(tc *testCase) {
 			ctx := context.Background()
+			for feature, enabled := range tc.featureGates {
+				features.SetFeatureGateDuringTest(ginkgo.GinkgoTB(), feature, enabled)
+			}
 			// Create test namespace for each entry.
 			ns := &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -229,6 +236,123 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 				},
 			},
 		}),
+		ginkgo.Entry("Worker statefulSet node selector changed by another manager will be reconciled", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).ExclusivePlacement().Replica(1)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						node := corev1.Node{ObjectMeta: metav1.ObjectMeta{
+							Name:   "node-" + lws.Namespace,
+							Labels: map[string]string{lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]: "pool-a"},
+						}}
+						gomega.Expect(k8sClient.Create(ctx, &node)).To(gomega.Succeed())
+						ginkgo.DeferCleanup(func() {
+							gomega.Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &node))).To(gomega.Succeed())
+						})
+						// schedule the leader pod, the worker statefulset follows its topology
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						binding := corev1.Binding{Target: corev1.ObjectReference{Kind: "Node", Name: node.Name}}
+						gomega.Expect(k8sClient.SubResource("binding").Create(ctx, &leaderPod, &binding)).To(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectWorkerNodeSelector(ctx, k8sClient, lws, map[string]string{lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]: "pool-a"})
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() error {
+							var sts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &sts); err != nil {
+								return err
+							}
+							sts.Spec.Template.Spec.NodeSelector[lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]] = "changed"
+							return k8sClient.Update(ctx, &sts, client.FieldOwner("other-manager"))
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectWorkerNodeSelector(ctx, k8sClient, lws, map[string]string{lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]: "pool-a"})
+						testing.ValidateEvent(ctx, k8sClient, controllers.FieldsConflict, corev1.EventTypeWarning, "Overriding the fields of worker statefulset "+lws.Name+"-0 changed by other managers", lws.Namespace)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("Worker statefulSet template and replicas changed by another manager will be reconciled", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(1)
+			},
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() error {
+							var sts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &sts); err != nil {
+								return err
+							}
+							sts.Spec.Template.Spec.Containers[0].Image = "changed"
+							sts.Spec.Replicas = ptr.To(*sts.Spec.Replicas + 1)
+							return k8sClient.Update(ctx, &sts, client.FieldOwner("other-manager"))
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() (string, error) {
+							var sts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &sts); err != nil {
+								return "", err
+							}
+							return sts.Spec.Template.Spec.Containers[0].Image, nil
+						}, testing.Timeout, testing.Interval).Should(gomega.Equal(lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image))
+						testing.ValidateEvent(ctx, k8sClient, controllers.FieldsConflict, corev1.EventTypeWarning, "Overriding the fields of worker statefulset "+lws.Name+"-0 changed by other managers", lws.Namespace)
+						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("Worker statefulSet is not rolled when the annotations of the leaderWorkerSet change", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(1).Size(4).SubGroupSize(2)
+			},
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetLeaderWorkerSetAnnotation(ctx, k8sClient, lws, leaderworkerset.SubGroupExclusiveKeyAnnotationKey, ptr.To("topologyKey"))
+						// update the leader pod to reconcile its worker statefulset
+						gomega.Eventually(func() error {
+							var leaderPod corev1.Pod
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod); err != nil {
+								return err
+							}
+							leaderPod.Labels["reconcile"] = "true"
+							return k8sClient.Update(ctx, &leaderPod)
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Consistently(func() (int64, error) {
+							var sts appsv1.StatefulSet
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &sts); err != nil {
+								return 0, err
+							}
+							if _, found := sts.Spec.Template.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]; found {
+								return 0, fmt.Errorf("worker statefulset %s has the annotations of the leaderworkerset", sts.Name)
+							}
+							return sts.Generation, nil
+						}, testing.Timeout, testing.Interval).Should(gomega.Equal(int64(1)))
+					},
+				},
+			},
+		}),
 		ginkgo.Entry("headless service created", &testCase{
 			makeLeaderWorkerSet: wrappers.BuildLeaderWorkerSet,
 			updates: []*update{
@@ -261,6 +385,92 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 				},
 			},
 		}),
+		ginkgo.Entry("drifted headless service will be corrected", &testCase{
+			makeLeaderWorkerSet: wrappers.BuildLeaderWorkerSet,
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidServices(ctx, k8sClient, lws, 1)
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() error {
+							var service corev1.Service
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &service); err != nil {
+								return err
+							}
+							service.Spec.Selector = map[string]string{"app": "changed"}
+							service.Spec.PublishNotReadyAddresses = false
+							return k8sClient.Update(ctx, &service)
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidServices(ctx, k8sClient, lws, 1)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("headless services are migrated by the rolling update when the subdomain policy changes", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(2)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectValidServices(ctx, k8sClient, lws, 1)
+					},
+				},
+				{
+					// The groups still use the shared subdomain until the rolling update is done.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.UpdateSubdomainPolicy(ctx, k8sClient, lws, leaderworkerset.SubdomainUniquePerReplica)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						var service corev1.Service
+						gomega.Consistently(func() error {
+							return k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &service)
+						}, testing.Timeout, testing.Interval).Should(gomega.Succeed())
+					},
+				},
+				{
+					// The shared headless service is deleted once all the groups moved to their own subdomains.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetNoUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectValidServices(ctx, k8sClient, lws, 2)
+					},
+				},
+				{
+					// The shared headless service is recreated right away, the per replica ones are kept until
+					// the rolling update is done.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.UpdateSubdomainPolicy(ctx, k8sClient, lws, leaderworkerset.SubdomainShared)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectValidServices(ctx, k8sClient, lws, 3)
+					},
+				},
+				{
+					// The per replica headless services are deleted once all the groups moved back to the shared subdomain.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetNoUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectValidServices(ctx, k8sClient, lws, 1)
+					},
+				},
+			},
+		}),
 		ginkgo.Entry("subdomain policy LeadersSharedWorkersDedicated, more than one headless service created", &testCase{
 			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
 				return wrappers.BuildLeaderWorkerSet(nsName).SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica)
@@ -446,7 +656,126 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 						var leaderPod corev1.Pod
 						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
 						gomega.Expect(leaderPod.DeletionTimestamp != nil).To(gomega.BeTrue())
-						testing.ValidateEvent(ctx, k8sClient, "RecreateGroupOnPodRestart", corev1.EventTypeNormal, "Worker pod test-sample-0-1 failed, deleted leader pod test-sample-0 to recreate group 0", lws.Namespace)
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRecreated, corev1.EventTypeWarning, "Pod test-sample-0-1 of group 0 failed: pod was deleted, deleted leader pod test-sample-0 to recreate the group", lws.Namespace)
+						testing.ExpectLeaderWorkerSetDegraded(ctx, k8sClient, lws, "Pod test-sample-0-1 of group 0 failed: pod was deleted")
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("Pod eviction will delay the recreation of the pod group when restart policy is RecreateGroupOnPodRestart", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).Replica(1).Size(3)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						testing.CreateWorkerPodsForLeaderPod(ctx, leaderPod, k8sClient, *lws)
+						// evict one worker pod
+						var worker corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0-1", Namespace: lws.Namespace}, &worker)).To(gomega.Succeed())
+						worker.Status.Conditions = append(worker.Status.Conditions, corev1.PodCondition{
+							Type:    corev1.DisruptionTarget,
+							Status:  corev1.ConditionTrue,
+							Reason:  "EvictionByEvictionAPI",
+							Message: "Eviction API: evicting",
+						})
+						gomega.Expect(k8sClient.Status().Update(ctx, &worker)).To(gomega.Succeed())
+						gomega.Expect(k8sClient.Delete(ctx, &worker)).To(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						message := "Pod test-sample-0-1 of group 0 failed: pod was evicted: Eviction API: evicting"
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRecreationDeferred, corev1.EventTypeWarning, message+", delaying the recreation of the group by 2s", lws.Namespace)
+						testing.ExpectLeaderWorkerSetDegraded(ctx, k8sClient, lws, message)
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRecreated, corev1.EventTypeWarning, "Group 0 was disrupted (EvictionByEvictionAPI), deleted leader pod test-sample-0 to recreate the group", lws.Namespace)
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						gomega.Expect(leaderPod.DeletionTimestamp != nil).To(gomega.BeTrue())
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("Pod on a NotReady node will delete the pod group after the grace period when restart policy is RecreateGroupOnPodRestart", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).Replica(1).Size(3)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + lws.Namespace}}
+						gomega.Expect(k8sClient.Create(ctx, &node)).To(gomega.Succeed())
+						ginkgo.DeferCleanup(func() {
+							gomega.Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &node))).To(gomega.Succeed())
+						})
+						node.Status.Conditions = []corev1.NodeCondition{{
+							Type:               corev1.NodeReady,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+						}}
+						gomega.Expect(k8sClient.Status().Update(ctx, &node)).To(gomega.Succeed())
+
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						testing.CreateWorkerPodsForLeaderPod(ctx, leaderPod, k8sClient, *lws)
+						// bind one worker pod to the NotReady node
+						var worker corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0-1", Namespace: lws.Namespace}, &worker)).To(gomega.Succeed())
+						binding := corev1.Binding{Target: corev1.ObjectReference{Kind: "Node", Name: node.Name}}
+						gomega.Expect(k8sClient.SubResource("binding").Create(ctx, &worker, &binding)).To(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() (bool, error) {
+							var leaderPod corev1.Pod
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod); err != nil {
+								return false, err
+							}
+							return leaderPod.DeletionTimestamp != nil, nil
+						}, testing.Timeout, testing.Interval).Should(gomega.BeTrue())
+						message := fmt.Sprintf("Pod test-sample-0-1 of group 0 failed: node node-%s has been NotReady for more than 1m0s", lws.Namespace)
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRecreated, corev1.EventTypeWarning, message+", deleted leader pod test-sample-0 to recreate the group", lws.Namespace)
+						testing.ExpectLeaderWorkerSetDegraded(ctx, k8sClient, lws, message)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("Pod on a NotReady node will not delete the pod group when the NodeFailureGroupRecreation feature is disabled", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).RestartPolicy(leaderworkerset.RecreateGroupOnPodRestart).Replica(1).Size(3)
+			},
+			featureGates: map[featuregate.Feature]bool{features.NodeFailureGroupRecreation: false},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + lws.Namespace}}
+						gomega.Expect(k8sClient.Create(ctx, &node)).To(gomega.Succeed())
+						ginkgo.DeferCleanup(func() {
+							gomega.Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &node))).To(gomega.Succeed())
+						})
+						node.Status.Conditions = []corev1.NodeCondition{{
+							Type:               corev1.NodeReady,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+						}}
+						gomega.Expect(k8sClient.Status().Update(ctx, &node)).To(gomega.Succeed())
+
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						testing.CreateWorkerPodsForLeaderPod(ctx, leaderPod, k8sClient, *lws)
+						// bind one worker pod to the NotReady node
+						var worker corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0-1", Namespace: lws.Namespace}, &worker)).To(gomega.Succeed())
+						binding := corev1.Binding{Target: corev1.ObjectReference{Kind: "Node", Name: node.Name}}
+						gomega.Expect(k8sClient.SubResource("binding").Create(ctx, &worker, &binding)).To(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Consistently(func() (bool, error) {
+							var leaderPod corev1.Pod
+							if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod); err != nil {
+								return false, err
+							}
+							return leaderPod.DeletionTimestamp == nil, nil
+						}, testing.Timeout, testing.Interval).Should(gomega.BeTrue())
 					},
 				},
 			},
@@ -493,6 +822,28 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 			},
 		}),
 
+		ginkgo.Entry("Groups restarted with the restart-groups annotation", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(3)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetLeaderWorkerSetAnnotation(ctx, k8sClient, lws, leaderworkerset.RestartGroupsAnnotationKey, ptr.To("0,2"))
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectGroupRestarted(ctx, k8sClient, lws, 0)
+						testing.ExpectGroupRestarted(ctx, k8sClient, lws, 2)
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRestarted, corev1.EventTypeNormal, "Deleted leader pod test-sample-0 to restart group 0", lws.Namespace)
+						testing.ValidateEvent(ctx, k8sClient, controllers.GroupRestarted, corev1.EventTypeNormal, "Deleted leader pod test-sample-2 to restart group 2", lws.Namespace)
+						var leader corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-1", Namespace: lws.Namespace}, &leader)).To(gomega.Succeed())
+						gomega.Expect(leader.DeletionTimestamp).To(gomega.BeNil())
+					},
+				},
+			},
+		}),
+
 		// Rolling update test cases
 		ginkgo.Entry("leaderTemplate changed with default strategy", &testCase{
 			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
@@ -510,6 +861,7 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 4)
 						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
 						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 4)
+						testing.ExpectLeaderWorkerSetStatusRevisions(ctx, k8sClient, lws, true)
 					},
 				},
 				{
@@ -527,6 +879,7 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 						testing.ValidateEvent(ctx, k8sClient, controllers.GroupsUpdating, corev1.EventTypeNormal, "Updating replicas 4 to 3", lws.Namespace)
 						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 3)
 						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 0)
+						testing.ExpectLeaderWorkerSetStatusRevisions(ctx, k8sClient, lws, false)
 					},
 				},
 				{
@@ -588,6 +941,57 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 						testing.ExpectLeaderWorkerSetNoUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
 						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
 						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 4)
+						testing.ExpectLeaderWorkerSetStatusRevisions(ctx, k8sClient, lws, true)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("rolling update paused and resumed", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(nsName).Replica(4)
+			},
+			updates: []*update{
+				{
+					// Set lws to available condition.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 4)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 0)
+					},
+				},
+				{
+					// A paused rolling update doesn't update any group.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetRolloutPaused(ctx, k8sClient, lws, true)
+						testing.UpdateWorkerTemplate(ctx, k8sClient, lws)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 4)
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 0)
+					},
+				},
+				{
+					// Resuming the rolling update moves the partition again.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetRolloutPaused(ctx, k8sClient, lws, false)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 3)
+					},
+				},
+				{
+					// Rolling update all the replicas.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 4)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 0)
+						testing.ExpectLeaderWorkerSetNoUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 4)
 					},
 				},
 			},
@@ -1799,6 +2203,58 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 			},
 		}),
 	) // end of DescribeTable
+
+	// The controllers suite runs without webhooks, so these validations are enforced by the CRD validation rules only.
+	ginkgo.DescribeTable("leaderWorkerSet validation without webhooks",
+		func(makeLeaderWorkerSet func(nsName string) *wrappers.LeaderWorkerSetWrapper, updateLeaderWorkerSet func(*leaderworkerset.LeaderWorkerSet)) {
+			ctx := context.Background()
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "lws-ns-",
+				},
+			}
+			gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+			lws := makeLeaderWorkerSet(ns.Name).Obj()
+			if updateLeaderWorkerSet == nil {
+				gomega.Expect(k8sClient.Create(ctx, lws)).Should(gomega.Not(gomega.Succeed()))
+				return
+			}
+			gomega.Expect(k8sClient.Create(ctx, lws)).Should(gomega.Succeed())
+			updateLeaderWorkerSet(lws)
+			gomega.Expect(k8sClient.Update(ctx, lws)).Should(gomega.Not(gomega.Succeed()))
+		},
+		ginkgo.Entry("creation with size 0 should fail", func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+			return wrappers.BuildLeaderWorkerSet(nsName).Size(0)
+		}, nil),
+		ginkgo.Entry("creation with size not divisible by subGroupSize should fail", func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+			return wrappers.BuildLeaderWorkerSet(nsName).Size(6).SubGroupSize(4)
+		}, nil),
+		ginkgo.Entry("creation with maxUnavailable and maxSurge both 0 should fail", func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+			return wrappers.BuildLeaderWorkerSet(nsName).MaxUnavailable(0).MaxSurge(0)
+		}, nil),
+		ginkgo.Entry("update of size should fail", func(nsName string) *wrappers.LeaderWorkerSetWrapper {
+			return wrappers.BuildLeaderWorkerSet(nsName)
+		}, func(lws *leaderworkerset.LeaderWorkerSet) {
+			lws.Spec.LeaderWorkerTemplate.Size = ptr.To[int32](3)
+		}),
+	)
+
+	// subdomainPolicy has no omitempty, clearing it is rejected because the field is required by the CRD schema.
+	ginkgo.It("clearing subdomainPolicy should fail as it is required", func() {
+		ctx := context.Background()
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "lws-ns-",
+			},
+		}
+		gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+		lws := wrappers.BuildLeaderWorkerSet(ns.Name).SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica).Obj()
+		gomega.Expect(k8sClient.Create(ctx, lws)).Should(gomega.Succeed())
+		lws.Spec.NetworkConfig.SubdomainPolicy = nil
+		err := k8sClient.Update(ctx, lws)
+		gomega.Expect(apierrors.IsInvalid(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("spec.networkConfig.subdomainPolicy: Required value"))
+	})
 }) // end of Describe
 
 func ToUnstructured(o client.Object) (*unstructured.Unstructured, error) {
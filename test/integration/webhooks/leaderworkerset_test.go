@@ -243,6 +243,18 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with invalid restart-groups annotation should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,leader"})
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with restart-groups annotation should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Annotation(map[string]string{leaderworkerset.RestartGroupsAnnotationKey: "0,1"})
+			},
+			lwsCreationShouldFail: false,
+		}),
 		ginkgo.Entry("creation with invalid subGroupSize should fail", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
 				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(2).SubGroupSize(-1)
@@ -454,5 +466,85 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with size matching the TPU topology should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(5).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with size not matching the TPU topology should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with size matching the TPU topology when the leader requests TPUs should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).LeaderTemplateSpec(wrappers.MakeLeaderPodSpecWithTPUResource()).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with a leader TPU topology different from the workers one should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				leaderPodSpec := wrappers.MakeLeaderPodSpecWithTPUResource()
+				leaderPodSpec.NodeSelector = map[string]string{"cloud.google.com/gke-tpu-topology": "2x2x2"}
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(4).LeaderTemplateSpec(leaderPodSpec).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with subGroupSize not matching the TPU topology should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(9).SubGroupSize(2).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with subGroupSize matching the TPU topology should succeed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name).Size(9).SubGroupSize(4).WorkerTemplateSpec(wrappers.MakeWorkerPodSpecWithTPUResource()).WorkerTPUTopology("2x2x4")
+			},
+			lwsCreationShouldFail: false,
+		}),
+		ginkgo.Entry("creation with leader template restartPolicy Never should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				podSpec := wrappers.MakeLeaderPodSpec()
+				podSpec.RestartPolicy = corev1.RestartPolicyNever
+				return wrappers.BuildLeaderWorkerSet(ns.Name).LeaderTemplateSpec(podSpec)
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with duplicate worker container names should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				podSpec := wrappers.MakeLeaderPodSpecWithTPUResourceMultipleContainers()
+				podSpec.Containers[1].Name = podSpec.Containers[0].Name
+				return wrappers.BuildLeaderWorkerSet(ns.Name).WorkerTemplateSpec(podSpec)
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("creation with a leader container without image should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				podSpec := wrappers.MakeLeaderPodSpec()
+				podSpec.Containers[0].Image = ""
+				return wrappers.BuildLeaderWorkerSet(ns.Name).LeaderTemplateSpec(podSpec)
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("update with worker template restartPolicy Never should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.RestartPolicy = corev1.RestartPolicyNever
+			},
+			updateShouldFail: true,
+		}),
+		ginkgo.Entry("update with worker volume mount referencing a missing volume should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *wrappers.LeaderWorkerSetWrapper {
+				return wrappers.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}}
+			},
+			updateShouldFail: true,
+		}),
 	)
 })
@@ -26,10 +26,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
-	"sigs.k8s.io/lws/pkg/webhooks"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 	testutils "sigs.k8s.io/lws/test/testutils"
 	"sigs.k8s.io/lws/test/wrappers"
 )
@@ -58,6 +60,7 @@ var _ = ginkgo.Describe("leaderworkerset pod defaulting, creation and update", f
 			// Create LeaderWorkerSet pods
 			ginkgo.By("creating lws pod")
 			expectedPod := tc.makePod(ns)
+			setStatefulSetOwner(&expectedPod)
 			gomega.Expect(k8sClient.Create(ctx, &expectedPod)).To(gomega.Succeed())
 			var gotPod corev1.Pod
 			gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: expectedPod.Namespace, Name: expectedPod.Name}, &gotPod)).To(gomega.Succeed())
@@ -705,7 +708,7 @@ var _ = ginkgo.Describe("leaderworkerset pod defaulting, creation and update", f
 					},
 					Spec: wrappers.MakeLeaderPodSpecWithTPUResource(),
 				}
-				webhooks.SetExclusiveAffinities(pod, "uniquehash", "topologyKey", leaderworkerset.GroupUniqueHashLabelKey)
+				podutils.SetExclusiveAffinities(pod, "uniquehash", "topologyKey", leaderworkerset.GroupUniqueHashLabelKey)
 				return *pod
 			},
 			checkExpectedPod: func(expected corev1.Pod, got corev1.Pod) error {
@@ -854,5 +857,133 @@ var _ = ginkgo.Describe("leaderworkerset pod defaulting, creation and update", f
 			},
 			podCreationShouldFail: false,
 		}),
+		ginkgo.Entry("leader pod controlled by the leader statefulset should be created", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			podCreationShouldFail: false,
+		}),
+		ginkgo.Entry("worker pod controlled by the worker statefulset should be created", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := *wrappers.MakePodWithLabels("test-sample", "1", "1", ns.Name, 2)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			podCreationShouldFail: false,
+		}),
+		ginkgo.Entry("leaderworkerset pod without statefulset owner should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				return makeLeaderPod(ns.Name)
+			},
+			podCreationShouldFail: true,
+		}),
+		ginkgo.Entry("leaderworkerset pod controlled by a statefulset of another leaderworkerset should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				pod.OwnerReferences[0].Name = "another-lws"
+				return pod
+			},
+			podCreationShouldFail: true,
+		}),
+		ginkgo.Entry("update of unmanaged labels should be allowed", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				pod.Labels["random-label"] = "random-value"
+				return *pod
+			},
+			podUpdateShouldFail: false,
+		}),
+		ginkgo.Entry("update of group index label should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				pod.Labels[leaderworkerset.GroupIndexLabelKey] = "2"
+				return *pod
+			},
+			podUpdateShouldFail: true,
+		}),
+		ginkgo.Entry("update of worker index label should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := *wrappers.MakePodWithLabels("test-sample", "1", "1", ns.Name, 2)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				pod.Labels[leaderworkerset.WorkerIndexLabelKey] = "2"
+				return *pod
+			},
+			podUpdateShouldFail: true,
+		}),
+		ginkgo.Entry("removal of group key label should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				delete(pod.Labels, leaderworkerset.GroupUniqueHashLabelKey)
+				return *pod
+			},
+			podUpdateShouldFail: true,
+		}),
+		ginkgo.Entry("update of revision label should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				pod.Labels[leaderworkerset.RevisionKey] = "another-revision"
+				return *pod
+			},
+			podUpdateShouldFail: true,
+		}),
+		ginkgo.Entry("update of size annotation should be rejected", &testValidationCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				pod := makeLeaderPod(ns.Name)
+				setStatefulSetOwner(&pod)
+				return pod
+			},
+			updatePod: func(pod *corev1.Pod) corev1.Pod {
+				pod.Annotations[leaderworkerset.SizeAnnotationKey] = "3"
+				return *pod
+			},
+			podUpdateShouldFail: true,
+		}),
 	)
 })
+
+// setStatefulSetOwner sets the StatefulSet that would have created the pod as its controller,
+// since the pod webhook rejects leaderworkerset pods that are not controlled by a StatefulSet.
+func setStatefulSetOwner(pod *corev1.Pod) {
+	if _, found := pod.Labels[leaderworkerset.SetNameLabelKey]; !found {
+		return
+	}
+	stsName, _ := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion:         "apps/v1",
+			Kind:               "StatefulSet",
+			Name:               stsName,
+			UID:                types.UID("statefulset-uid"),
+			Controller:         ptr.To(true),
+			BlockOwnerDeletion: ptr.To(true),
+		},
+	}
+}
+
+func makeLeaderPod(namespace string) corev1.Pod {
+	pod := wrappers.MakePodWithLabels("test-sample", "1", "0", namespace, 2)
+	pod.Labels[leaderworkerset.WorkerIndexLabelKey] = "0"
+	return *pod
+}
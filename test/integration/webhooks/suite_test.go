@@ -133,7 +133,7 @@ var _ = BeforeSuite(func() {
 
 	/*err = controller.SetupIndexes(mgr.GetFieldIndexer())
 	Expect(err).NotTo(HaveOccurred())*/
-	err = webhooks.SetupLeaderWorkerSetWebhook(mgr)
+	err = webhooks.SetupLeaderWorkerSetWebhook(mgr, nil)
 	Expect(err).NotTo(HaveOccurred())
 
 	err = webhooks.SetupPodWebhook(mgr)
@@ -187,6 +187,12 @@ func createLeaderPods(ctx context.Context, k8sClient client.Client, leaderSts ap
 		if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
 			pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
 		}
+		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
+			pod.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey]
+		}
+		if lws.Spec.NetworkConfig != nil && lws.Spec.NetworkConfig.SubdomainPolicy != nil && *lws.Spec.NetworkConfig.SubdomainPolicy == leaderworkerset.SubdomainUniquePerReplica {
+			pod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
+		}
 		// Set the controller owner reference for garbage collection and reconciliation.
 		if err := ctrl.SetControllerReference(&leaderSts, &pod, scheme.Scheme); err != nil {
 			return err
@@ -298,6 +304,15 @@ func SetLeaderPodToReady(ctx context.Context, k8sClient client.Client, podName s
 			return err
 		}
 		leaderPod.Labels[leaderworkerset.RevisionKey] = revisionutils.GetRevisionKey(&leaderSts)
+		// The subdomain policy of the pod follows its revision, as if it was recreated from the leader statefulset.
+		if subdomainPolicy, found := leaderSts.Spec.Template.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey]; found {
+			if leaderPod.Annotations == nil {
+				leaderPod.Annotations = map[string]string{}
+			}
+			leaderPod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] = subdomainPolicy
+		} else {
+			delete(leaderPod.Annotations, leaderworkerset.SubdomainPolicyAnnotationKey)
+		}
 		return k8sClient.Update(ctx, &leaderPod)
 	}, Timeout, Interval).Should(gomega.Succeed())
 
@@ -328,6 +343,7 @@ func SetPodGroupToReady(ctx context.Context, k8sClient client.Client, statefulse
 		}
 
 		sts.Status.ReadyReplicas = *sts.Spec.Replicas
+		sts.Status.AvailableReplicas = *sts.Spec.Replicas
 		sts.Status.Replicas = *sts.Spec.Replicas
 		sts.Status.CurrentRevision = ""
 		sts.Status.UpdateRevision = ""
@@ -545,6 +561,34 @@ func UpdateWorkerTemplate(ctx context.Context, k8sClient client.Client, leaderWo
 	}, Timeout, Interval).Should(gomega.Succeed())
 }
 
+// SetRolloutPaused sets or removes the rollout-paused annotation of the leaderworkerset.
+func SetRolloutPaused(ctx context.Context, k8sClient client.Client, leaderWorkerSet *leaderworkerset.LeaderWorkerSet, paused bool) {
+	var value *string
+	if paused {
+		value = ptr.To("true")
+	}
+	SetLeaderWorkerSetAnnotation(ctx, k8sClient, leaderWorkerSet, leaderworkerset.RolloutPausedAnnotationKey, value)
+}
+
+// SetLeaderWorkerSetAnnotation sets the annotation of the leaderworkerset, or removes it if the value is nil.
+func SetLeaderWorkerSetAnnotation(ctx context.Context, k8sClient client.Client, leaderWorkerSet *leaderworkerset.LeaderWorkerSet, key string, value *string) {
+	gomega.Eventually(func() error {
+		var lws leaderworkerset.LeaderWorkerSet
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: leaderWorkerSet.Name, Namespace: leaderWorkerSet.Namespace}, &lws); err != nil {
+			return err
+		}
+		if value == nil {
+			delete(lws.Annotations, key)
+		} else {
+			if lws.Annotations == nil {
+				lws.Annotations = map[string]string{}
+			}
+			lws.Annotations[key] = *value
+		}
+		return k8sClient.Update(ctx, &lws)
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
 // DeleteNamespace deletes all objects the tests typically create in the namespace.
 func DeleteNamespace(ctx context.Context, c client.Client, ns *corev1.Namespace) error {
 	if ns == nil {
@@ -579,6 +623,7 @@ func SetLeaderPodsToReady(ctx context.Context, k8sClient client.Client, lws *lea
 			return err
 		}
 		sts.Status.ReadyReplicas = *sts.Spec.Replicas
+		sts.Status.AvailableReplicas = *sts.Spec.Replicas
 		sts.Status.Replicas = *sts.Spec.Replicas
 		sts.Status.CurrentRevision = ""
 		sts.Status.UpdateRevision = ""
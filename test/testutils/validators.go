@@ -302,6 +302,18 @@ func ExpectValidWorkerStatefulSets(ctx context.Context, leaderWorkerSet *leaderw
 	}, Timeout, Interval).Should(gomega.Succeed())
 }
 
+// ExpectWorkerNodeSelector checks the node selector of the worker statefulset of the first group.
+func ExpectWorkerNodeSelector(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, nodeSelector map[string]string) {
+	ginkgo.By(fmt.Sprintf("checking the node selector of worker statefulset %s-0", lws.Name))
+	gomega.Eventually(func() (map[string]string, error) {
+		var sts appsv1.StatefulSet
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &sts); err != nil {
+			return nil, err
+		}
+		return sts.Spec.Template.Spec.NodeSelector, nil
+	}, Timeout, Interval).Should(gomega.Equal(nodeSelector))
+}
+
 // Expect that the revisionKey and the container name in the Worker Sts have been updated
 func ExpectUpdatedWorkerStatefulSet(ctx context.Context, k8sClient client.Client, leaderWorkerSet *leaderworkerset.LeaderWorkerSet, statefulsetName string) {
 	gomega.Eventually(func() error {
@@ -374,6 +386,38 @@ func ExpectLeaderWorkerSetNotProgressing(ctx context.Context, k8sClient client.C
 	gomega.Eventually(CheckLeaderWorkerSetHasCondition, Timeout, Interval).WithArguments(ctx, k8sClient, lws, condition).Should(gomega.Equal(true))
 }
 
+func ExpectLeaderWorkerSetDegraded(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, message string) {
+	ginkgo.By(fmt.Sprintf("checking leaderworkerset status(%s) is true", leaderworkerset.LeaderWorkerSetDegraded))
+	condition := metav1.Condition{
+		Type:    string(leaderworkerset.LeaderWorkerSetDegraded),
+		Status:  metav1.ConditionTrue,
+		Message: message,
+	}
+	gomega.Eventually(CheckLeaderWorkerSetHasCondition, Timeout, Interval).WithArguments(ctx, k8sClient, lws, condition).Should(gomega.Equal(true))
+}
+
+// ExpectGroupRestarted checks the leader pod of the group is deleted and the restart-groups annotation removed.
+func ExpectGroupRestarted(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, groupIndex int) {
+	ginkgo.By(fmt.Sprintf("checking group %d is restarted", groupIndex))
+	gomega.Eventually(func() error {
+		var leader corev1.Pod
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%d", lws.Name, groupIndex), Namespace: lws.Namespace}, &leader); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if leader.DeletionTimestamp == nil {
+			return fmt.Errorf("leader pod %s not deleted", leader.Name)
+		}
+		return nil
+	}, Timeout, Interval).Should(gomega.Succeed())
+	gomega.Eventually(func() (map[string]string, error) {
+		var got leaderworkerset.LeaderWorkerSet
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &got); err != nil {
+			return nil, err
+		}
+		return got.Annotations, nil
+	}, Timeout, Interval).ShouldNot(gomega.HaveKey(leaderworkerset.RestartGroupsAnnotationKey))
+}
+
 func ExpectLeaderWorkerSetUpgradeInProgress(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, message string) {
 	ginkgo.By(fmt.Sprintf("checking leaderworkerset status(%s) is true", leaderworkerset.LeaderWorkerSetUpdateInProgress))
 	condition := metav1.Condition{
@@ -410,6 +454,31 @@ func ExpectLeaderWorkerSetStatusReplicas(ctx context.Context, k8sClient client.C
 	}, Timeout, Interval).Should(gomega.Succeed())
 }
 
+// ExpectLeaderWorkerSetStatusRevisions checks the status reflects the latest generation, and that the
+// current revision only catches up with the update revision of the leader statefulset once the rolling update is done.
+func ExpectLeaderWorkerSetStatusRevisions(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, rollingUpdateDone bool) {
+	ginkgo.By("checking leaderworkerset status revisions")
+	gomega.Eventually(func() error {
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: lws.Name}, lws); err != nil {
+			return err
+		}
+		if lws.Status.ObservedGeneration != lws.Generation {
+			return fmt.Errorf("observedGeneration in status not match, want: %d, got %d", lws.Generation, lws.Status.ObservedGeneration)
+		}
+		var leaderSts appsv1.StatefulSet
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: lws.Namespace, Name: lws.Name}, &leaderSts); err != nil {
+			return err
+		}
+		if updateRevision := revisionutils.GetRevisionKey(&leaderSts); lws.Status.UpdateRevision != updateRevision {
+			return fmt.Errorf("updateRevision in status not match, want: %s, got %s", updateRevision, lws.Status.UpdateRevision)
+		}
+		if rollingUpdateDone != (lws.Status.CurrentRevision == lws.Status.UpdateRevision) {
+			return fmt.Errorf("unexpected currentRevision %s in status with updateRevision %s, rolling update done: %t", lws.Status.CurrentRevision, lws.Status.UpdateRevision, rollingUpdateDone)
+		}
+		return nil
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
 func ExpectLeaderWorkerSetAvailable(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, message string) {
 	ginkgo.By(fmt.Sprintf("checking leaderworkerset status(%s) is true", leaderworkerset.LeaderWorkerSetAvailable))
 	condition := metav1.Condition{
@@ -78,6 +78,13 @@ func (lwsWrapper *LeaderWorkerSetWrapper) ExclusivePlacement() *LeaderWorkerSetW
 	return lwsWrapper
 }
 
+func (lwsWrapper *LeaderWorkerSetWrapper) WorkerTPUTopology(topology string) *LeaderWorkerSetWrapper {
+	lwsWrapper.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.NodeSelector = map[string]string{
+		"cloud.google.com/gke-tpu-topology": topology,
+	}
+	return lwsWrapper
+}
+
 func (lwsWrapper *LeaderWorkerSetWrapper) RestartPolicy(policy leaderworkerset.RestartPolicyType) *LeaderWorkerSetWrapper {
 	lwsWrapper.Spec.LeaderWorkerTemplate.RestartPolicy = policy
 	return lwsWrapper